@@ -2,13 +2,14 @@
 // Use of this source code is governed by the GNU General
 // Public License version 2 (or any later version).
 
+//go:build !noflint
+
 package arith
 
-// #cgo LDFLAGS: -lflint -lmpir -lmpfr -lm
 // #include <stdlib.h>
-// #include <flint.h>
-// #include <fmpz.h>
-// #include <arith.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/arith.h>
 import "C"
 
 import "github.com/frithjof-schulze/go.flint/fmpz"
@@ -1,2 +1,4 @@
+//go:build cgo && !noflint
+
 package arith
 
@@ -0,0 +1,10 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !flint_nopkgconfig && !noflint
+
+package arith
+
+// #cgo pkg-config: flint
+import "C"
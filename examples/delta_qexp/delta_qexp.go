@@ -22,6 +22,8 @@
    Copyright (C) 2012 Frithjof Schulze
 */
 
+//go:build cgo && !noflint
+
 package main
 
 // Demo program for computing the q-expansion of the delta function.
@@ -39,8 +41,7 @@ func main() {
 
 	flag.Parse()
 	if flag.NArg() == 1 {
-		err := n.SetString(flag.Arg(0), 10)
-		if err != nil {
+		if _, ok := n.SetString(flag.Arg(0), 10); !ok {
 			fmt.Println("Syntax: delta_qexp <integer>")
 			fmt.Println("where <integer> is the (positive) number of terms to compute")
 			fmt.Println("Error: Can not parse argument:", flag.Arg(0))
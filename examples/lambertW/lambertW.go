@@ -1,5 +1,7 @@
 // A go.flint example: Lambert W function power series.
 
+//go:build !noflint
+
 // This is basically code from Fredrik Johansson's blog March 11, 2011
 // http://fredrikj.net/blog/2011/03/a-flint-example-lambert-w-function-power-series/
 // translated to Go and go.flint.
@@ -24,29 +26,27 @@ import (
 // expansion, each iteration at least doubles the number of
 // correct coefficients.
 //
-// This is a simple recursive implementation with
-// asymptotically optimal performance (up to constant
-// factors). Beyond the base case W(x) = 0 + O(x), the
-// function just computes w to accuracy ceil(n/2), and then
-// extends it to accuracy n using a single Newton step.
+// fmpq.Poly.NewtonSeries performs this iteration for us: we
+// only supply F(w) = w exp(w) - x and its derivative
+// F'(w) = (w+1) exp(w), and it takes care of doubling the
+// precision, starting from the first-order expansion
+// W(x) = 0 + O(x). The same equation is available ready-made
+// as fmpq.Poly.LambertWSeries.
 func lambertw(w, x *mp.Poly, n int64) {
-	if n == 1 {
-		w.SetInt64(0)
-		return
-	}
-
-	lambertw(w, x, (n+1)/2)
-
 	t := new(mp.Poly)
-	t.ExpSeries(w, n)
-
-	u := new(mp.Poly)
-	v := new(mp.Poly)
-	u.MulLow(t, w, n)
-	v.Sub(u, x)
-	t.Add(u, t)
-	u.DivSeries(v, t, n)
-	w.Sub(w, u)
+	f := func(z, w *mp.Poly, n int64) *mp.Poly {
+		t.ExpSeries(w, n)
+		z.MulLow(t, w, n)
+		return z.Sub(z, x)
+	}
+	df := func(z, w *mp.Poly, n int64) *mp.Poly {
+		t.ExpSeries(w, n)
+		z.MulLow(t, w, n)
+		return z.Add(z, t)
+	}
+	if _, err := w.NewtonSeries(mp.NewPoly(0), f, df, n); err != nil {
+		panic(err)
+	}
 }
 
 func main() {
@@ -2,13 +2,14 @@
 // Use of this source code is governed by the GNU General
 // Public License version 2 (or any later version).
 
+//go:build !noflint
+
 package extras
 
-// #cgo LDFLAGS: -lflint -lmpir -lmpfr -lm
 // #include <stdlib.h>
-// #include <flint.h>
-// #include <mpir.h>
-// #include <ulong_extras.h>
+// #include <flint/flint.h>
+// #include <gmp.h>
+// #include <flint/ulong_extras.h>
 import "C"
 
 import (
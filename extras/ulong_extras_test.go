@@ -1 +1,3 @@
+//go:build cgo && !noflint
+
 package extras
\ No newline at end of file
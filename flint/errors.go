@@ -0,0 +1,69 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+// Package flint holds what the go.flint packages share: the errors
+// they report, and the hook that turns an abort inside FLINT into
+// a Go panic instead of killing the process.
+//
+// Operations whose arguments are outside their domain, such as a
+// division by zero, panic with an *Error wrapping one of the Err
+// values below.  Use Try to turn such a panic into an error.
+package flint
+
+import (
+	"errors"
+)
+
+var (
+	ErrDivisionByZero = errors.New("division by zero")
+	ErrDomain         = errors.New("argument outside the domain")
+	ErrNotInvertible  = errors.New("not invertible")
+	ErrOverflow       = errors.New("overflow")
+
+	// ErrAbort is reported when FLINT aborts without saying why,
+	// as FLINT before version 3 always does.
+	ErrAbort = errors.New("FLINT aborted")
+)
+
+// An Error is the panic value of a failed operation.
+type Error struct {
+	Op     string // the operation, e.g. "fmpz.Int.Div"
+	Err    error  // one of the Err values of this package
+	Detail string // FLINT's own message, if any
+}
+
+func (e *Error) Error() string {
+	s := e.Op + ": " + e.Err.Error()
+	if e.Detail != "" {
+		s += " (" + e.Detail + ")"
+	}
+	return s
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Panic panics with an *Error for op and err.
+func Panic(op string, err error) {
+	panic(&Error{Op: op, Err: err})
+}
+
+// Try calls f and returns the *Error f panics with, or nil if f
+// returns normally.  Any other panic is propagated.
+//
+// A panic raised from inside FLINT leaves the C stack frames it
+// unwinds without cleanup, so memory FLINT allocated there is
+// leaked; the Go values involved stay valid.
+func Try(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*Error)
+			if !ok {
+				panic(r)
+			}
+			err = e
+		}
+	}()
+	f()
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package flint
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include "hook.h"
+import "C"
+
+import (
+	"strings"
+)
+
+// goFlintThrow is called by the hook in hook.c with one of the
+// codes of hook.h and FLINT's message.
+//
+//export goFlintThrow
+func goFlintThrow(code C.int, msg *C.char) {
+	err := ErrAbort
+	switch code {
+	case C.GOFLINT_OVERFLOW:
+		err = ErrOverflow
+	case C.GOFLINT_NOT_INVERTIBLE:
+		err = ErrNotInvertible
+	case C.GOFLINT_DOMAIN:
+		err = ErrDomain
+	case C.GOFLINT_DIVISION_BY_ZERO:
+		err = ErrDivisionByZero
+	}
+	panic(&Error{Op: "flint", Err: err, Detail: strings.TrimSpace(C.GoString(msg))})
+}
+
+func init() {
+	C.goflint_install_hook()
+}
+
+// throw makes FLINT throw a division by zero through the hook.
+func throw() {
+	C.goflint_throw()
+}
@@ -0,0 +1,29 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package flint
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTryFLINT(t *testing.T) {
+	// FLINT before version 3 aborts without saying why.
+	want, detail := ErrDivisionByZero, "Exception (goflint_throw). Division by zero."
+	if strings.HasPrefix(Version(), "2.") {
+		want, detail = ErrAbort, ""
+	}
+	// The hook stays installed after a throw.
+	for i := 0; i < 2; i++ {
+		err := Try(throw)
+		var e *Error
+		if !errors.As(err, &e) || e.Op != "flint" || !errors.Is(err, want) || e.Detail != detail {
+			t.Errorf("Try(throw) = %v, want a flint *Error wrapping %v", err, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+// FLINT reports fatal errors through a replaceable hook that must
+// not return.  Ours hands the error to goFlintThrow, which panics.
+
+#include <stdarg.h>
+#include <stdio.h>
+#include <flint/flint.h>
+#include "_cgo_export.h"
+#include "hook.h"
+
+#if __FLINT_RELEASE >= 30000
+
+static void throw_hook(flint_err_t e, const char *fmt, va_list ap)
+{
+	char msg[256];
+	int code;
+
+	vsnprintf(msg, sizeof msg, fmt, ap);
+	switch (e) {
+	case FLINT_OVERFLOW:
+	case FLINT_EXPOF:
+		code = GOFLINT_OVERFLOW;
+		break;
+	case FLINT_IMPINV:
+		code = GOFLINT_NOT_INVERTIBLE;
+		break;
+	case FLINT_DOMERR:
+		code = GOFLINT_DOMAIN;
+		break;
+	case FLINT_DIVZERO:
+		code = GOFLINT_DIVISION_BY_ZERO;
+		break;
+	default:
+		code = GOFLINT_ABORT;
+	}
+	goFlintThrow(code, msg);
+}
+
+void goflint_install_hook(void)
+{
+	flint_set_throw(throw_hook);
+}
+
+#else
+
+static void abort_hook(void)
+{
+	char msg[1] = "";
+
+	goFlintThrow(GOFLINT_ABORT, msg);
+}
+
+void goflint_install_hook(void)
+{
+	flint_set_abort(abort_hook);
+}
+
+#endif
+
+// goflint_throw makes FLINT report a division by zero the way its
+// own functions do, so that the tests can check the hook.
+void goflint_throw(void)
+{
+#if __FLINT_RELEASE >= 30000
+	flint_throw(FLINT_DIVZERO, "Exception (goflint_throw). Division by zero.\n");
+#else
+	flint_abort();
+#endif
+}
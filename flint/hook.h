@@ -0,0 +1,24 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+// The codes the FLINT hook in hook.c passes to goFlintThrow, which
+// maps them to the Err values of errors.go.
+
+#ifndef GOFLINT_HOOK_H
+#define GOFLINT_HOOK_H
+
+enum goflint_code {
+	GOFLINT_ABORT,
+	GOFLINT_OVERFLOW,
+	GOFLINT_NOT_INVERTIBLE,
+	GOFLINT_DOMAIN,
+	GOFLINT_DIVISION_BY_ZERO
+};
+
+void goflint_install_hook(void);
+void goflint_throw(void);
+
+#endif
@@ -0,0 +1,24 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package flint
+
+// #include <flint/flint.h>
+//
+// #if __FLINT_RELEASE < 20900
+// #error "go.flint requires FLINT 2.9 or later"
+// #endif
+import "C"
+
+import (
+	"fmt"
+)
+
+// Version returns the version of the FLINT headers go.flint was
+// compiled against, e.g. "3.1.2".
+func Version() string {
+	return fmt.Sprintf("%d.%d.%d", C.__FLINT_VERSION, C.__FLINT_VERSION_MINOR, C.__FLINT_VERSION_PATCHLEVEL)
+}
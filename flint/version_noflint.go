@@ -0,0 +1,13 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package flint
+
+// Version returns the empty string: this build uses the pure Go
+// backend and is not linked with FLINT.
+func Version() string {
+	return ""
+}
@@ -0,0 +1,15 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package flint
+
+import "testing"
+
+func TestVersion(t *testing.T) {
+	if v := Version(); v != "" {
+		t.Errorf("Version = %q, want \"\" without FLINT", v)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package flint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	v := Version()
+	var major, minor, patch int
+	if n, err := fmt.Sscanf(v, "%d.%d.%d", &major, &minor, &patch); n != 3 || err != nil || fmt.Sprintf("%d.%d.%d", major, minor, patch) != v {
+		t.Fatalf("Version = %q, want major.minor.patch", v)
+	}
+	// The build rejects headers older than 2.9.
+	if major < 2 || major == 2 && minor < 9 {
+		t.Errorf("Version = %q, want 2.9 or later", v)
+	}
+}
@@ -2,15 +2,17 @@
 // Use of this source code is governed by the GNU General
 // Public License version 2 (or any later version).
 
+//go:build !noflint
+
 package fmpq
 
-// #cgo LDFLAGS: -lflint -lmpir -lmpfr -lm
 // #include <stdlib.h>
-// #include <flint.h>
-// #include <fmpq.h>
+// #include <flint/flint.h>
+// #include <flint/fmpq.h>
 import "C"
 
 import (
+	"github.com/frithjof-schulze/go.flint/flint"
 	"github.com/frithjof-schulze/go.flint/fmpz"
 )
 
@@ -33,9 +35,9 @@ func (z *Rat) Denom(x *fmpz.Int) *fmpz.Int {
 	return x
 }
 
-// Num returns the numerator of z as a flint.Int.
+// Num sets x to the numerator of z and returns x.
 func (z *Rat) Num(x *fmpz.Int) *fmpz.Int {
-	C.fmpz_set((*C.fmpz)(x), &(*C.fmpq)(z).den)
+	C.fmpz_set((*C.fmpz)(x), &(*C.fmpq)(z).num)
 	return x
 }
 
@@ -48,7 +50,17 @@ func (z *Rat) Set(x *Rat) *Rat {
 // SetRat64 sets z = p/q and returns z.
 func (z *Rat) SetRat64(p, q int64) *Rat {
 	// TODO(rsc): more work on 32-bit platforms
-	C.fmpq_set_si((*C.fmpq)(z), C.long(p), C.ulong(q))
+	C.fmpq_set_si((*C.fmpq)(z), C.slong(p), C.ulong(q))
+	return z
+}
+
+// SetFrac sets z = a/b and returns z.  If b is zero, a
+// division-by-zero run-time panic occurs.
+func (z *Rat) SetFrac(a, b *fmpz.Int) *Rat {
+	if b.Sign() == 0 {
+		flint.Panic("fmpq.Rat.SetFrac", flint.ErrDivisionByZero)
+	}
+	C.fmpq_set_fmpz_frac((*C.fmpq)(z), (*C.fmpz)(a), (*C.fmpz)(b))
 	return z
 }
 
@@ -87,3 +99,58 @@ func (z *Rat) Neg(x *Rat) *Rat {
 	C.fmpq_neg((*C.fmpq)(z), (*C.fmpq)(x))
 	return z
 }
+
+// Div sets z = x / y and returns z.  If y == 0, a division-by-zero
+// run-time panic occurs.
+func (z *Rat) Div(x, y *Rat) *Rat {
+	if C.fmpq_is_zero((*C.fmpq)(y)) != 0 {
+		flint.Panic("fmpq.Rat.Div", flint.ErrDivisionByZero)
+	}
+	C.fmpq_div((*C.fmpq)(z), (*C.fmpq)(x), (*C.fmpq)(y))
+	return z
+}
+
+// TryDiv is like Div, but if y == 0 it leaves z unchanged and
+// returns an *flint.Error wrapping flint.ErrDivisionByZero instead
+// of panicking.
+func (z *Rat) TryDiv(x, y *Rat) (*Rat, error) {
+	if C.fmpq_is_zero((*C.fmpq)(y)) != 0 {
+		return z, &flint.Error{Op: "fmpq.Rat.TryDiv", Err: flint.ErrDivisionByZero}
+	}
+	return z.Div(x, y), nil
+}
+
+// Inv sets z = 1/x and returns z.  If x == 0, a division-by-zero
+// run-time panic occurs.
+func (z *Rat) Inv(x *Rat) *Rat {
+	if C.fmpq_is_zero((*C.fmpq)(x)) != 0 {
+		flint.Panic("fmpq.Rat.Inv", flint.ErrDivisionByZero)
+	}
+	C.fmpq_inv((*C.fmpq)(z), (*C.fmpq)(x))
+	return z
+}
+
+// TryInv is like Inv, but if x == 0 it leaves z unchanged and
+// returns an *flint.Error wrapping flint.ErrDivisionByZero instead
+// of panicking.
+func (z *Rat) TryInv(x *Rat) (*Rat, error) {
+	if C.fmpq_is_zero((*C.fmpq)(x)) != 0 {
+		return z, &flint.Error{Op: "fmpq.Rat.TryInv", Err: flint.ErrDivisionByZero}
+	}
+	return z.Inv(x), nil
+}
+
+// Cmp compares z and y and returns:
+//
+//	-1 if z <  y
+//	 0 if z == y
+//	+1 if z >  y
+func (z *Rat) Cmp(y *Rat) (r int) {
+	r = int(C.fmpq_cmp((*C.fmpq)(z), (*C.fmpq)(y)))
+	if r < 0 {
+		r = -1
+	} else if r > 0 {
+		r = 1
+	}
+	return
+}
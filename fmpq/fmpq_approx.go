@@ -0,0 +1,77 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpq
+
+import (
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// Convergents returns the convergents of the continued fraction
+// with partial quotients cs, the values of its prefixes cs[:1],
+// cs[:2], ..., cs[:len(cs)].
+func Convergents(cs []*fmpz.Int) []*Rat {
+	rs := make([]*Rat, len(cs))
+	p0, q0 := fmpz.NewInt(0), fmpz.NewInt(1)
+	p1, q1 := fmpz.NewInt(1), fmpz.NewInt(0)
+	t := fmpz.NewInt(0)
+	for i, a := range cs {
+		// p_i = a*p_(i-1) + p_(i-2), likewise for q.
+		p0.Add(p0, t.Mul(a, p1))
+		q0.Add(q0, t.Mul(a, q1))
+		p0, p1 = p1, p0
+		q0, q1 = q1, q0
+		rs[i] = NewRat(0, 1).SetFrac(p1, q1)
+	}
+	return rs
+}
+
+// BestApprox sets z to the rational number closest to x among
+// those with denominator at most q, and returns z.  Of two equally
+// close candidates, the one with the smaller denominator is
+// chosen.
+func (z *Rat) BestApprox(x *Rat, q *fmpz.Int) *Rat {
+	if q.Sign() <= 0 {
+		panic("fmpq: denominator bound must be positive")
+	}
+	if x.Denom(fmpz.NewInt(0)).Cmp(q) <= 0 {
+		return z.Set(x)
+	}
+
+	// Walk the convergents until the next denominator exceeds q;
+	// the answer is the last convergent or the largest
+	// semiconvergent below the bound.
+	p0, q0 := fmpz.NewInt(0), fmpz.NewInt(1)
+	p1, q1 := fmpz.NewInt(1), fmpz.NewInt(0)
+	t := fmpz.NewInt(0)
+	for _, a := range x.CFrac() {
+		t.Add(q0, t.Mul(a, q1))
+		if t.Cmp(q) > 0 {
+			break
+		}
+		p0.Add(p0, t.Mul(a, p1))
+		q0.Add(q0, t.Mul(a, q1))
+		p0, p1 = p1, p0
+		q0, q1 = q1, q0
+	}
+	k := fmpz.NewInt(0).Sub(q, q0)
+	k.Div(k, q1)
+	s := NewRat(0, 1).SetFrac(p0.Add(p0, t.Mul(k, p1)), q0.Add(q0, t.Mul(k, q1)))
+	c := NewRat(0, 1).SetFrac(p1, q1)
+
+	// Compare the squared distances to x.
+	ds, dc := NewRat(0, 1).Sub(s, x), NewRat(0, 1).Sub(c, x)
+	ds.Mul(ds, ds)
+	dc.Mul(dc, dc)
+	switch dc.Cmp(ds) {
+	case -1:
+		return z.Set(c)
+	case 1:
+		return z.Set(s)
+	}
+	if q1.Cmp(q0) <= 0 {
+		return z.Set(c)
+	}
+	return z.Set(s)
+}
@@ -0,0 +1,107 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_vec.h>
+// #include <flint/fmpq.h>
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// CFrac returns the partial quotients a_0, a_1, ..., a_n of the
+// continued fraction expansion
+//
+//	z = a_0 + 1/(a_1 + 1/(a_2 + ... + 1/a_n))
+//
+// in which a_1, ..., a_n are positive and a_n > 1 unless n = 0.
+func (z *Rat) CFrac() []*fmpz.Int {
+	n := C.fmpq_cfrac_bound((*C.fmpq)(z))
+	c := C._fmpz_vec_init(n)
+	defer C._fmpz_vec_clear(c, n)
+	var rem C.fmpq
+	C.fmpq_init(&rem)
+	defer C.fmpq_clear(&rem)
+
+	k := C.fmpq_get_cfrac(c, &rem, (*C.fmpq)(z), n)
+	a := unsafe.Slice(c, int(k))
+	cs := make([]*fmpz.Int, len(a))
+	for i := range cs {
+		cs[i] = fmpz.NewInt(0)
+		C.fmpz_set((*C.fmpz)(cs[i]), &a[i])
+	}
+	return cs
+}
+
+// SetCFrac sets z to the value of the continued fraction with
+// partial quotients cs and returns z.  All quotients except cs[0]
+// must be positive.
+func (z *Rat) SetCFrac(cs []*fmpz.Int) *Rat {
+	if len(cs) == 0 {
+		panic("fmpq: empty continued fraction")
+	}
+	n := C.slong(len(cs))
+	c := C._fmpz_vec_init(n)
+	defer C._fmpz_vec_clear(c, n)
+	a := unsafe.Slice(c, len(cs))
+	for i := range cs {
+		if i > 0 && cs[i].Sign() <= 0 {
+			panic("fmpq: non-positive partial quotient")
+		}
+		C.fmpz_set(&a[i], (*C.fmpz)(cs[i]))
+	}
+	C.fmpq_set_cfrac((*C.fmpq)(z), c, n)
+	return z
+}
+
+// FareyNeighbors returns the fractions l and r directly below and
+// above z in the Farey sequence of order q, the sequence of
+// fractions with denominator at most q.  The denominator of z must
+// not exceed q.
+func (z *Rat) FareyNeighbors(q *fmpz.Int) (l, r *Rat) {
+	if z.Denom(fmpz.NewInt(0)).Cmp(q) > 0 {
+		panic("fmpq: Farey order smaller than denominator")
+	}
+	l, r = NewRat(0, 1), NewRat(0, 1)
+	C.fmpq_farey_neighbors((*C.fmpq)(l), (*C.fmpq)(r), (*C.fmpq)(z), (*C.fmpz)(q))
+	return l, r
+}
+
+// NextCalkinWilf sets z to the successor of x in the breadth-first
+// traversal of the Calkin-Wilf tree, 0, 1, 1/2, 2, 1/3, 3/2, 2/3,
+// 3, ..., which lists every non-negative rational exactly once,
+// and returns z.  x must be non-negative.
+func (z *Rat) NextCalkinWilf(x *Rat) *Rat {
+	if C.fmpq_sgn((*C.fmpq)(x)) < 0 {
+		panic("fmpq: negative argument to NextCalkinWilf")
+	}
+	C.fmpq_next_calkin_wilf((*C.fmpq)(z), (*C.fmpq)(x))
+	return z
+}
+
+// NextSignedCalkinWilf sets z to the successor of x in the
+// enumeration 0, 1, -1, 1/2, -1/2, 2, -2, ... of all rationals,
+// which follows the Calkin-Wilf tree, and returns z.
+func (z *Rat) NextSignedCalkinWilf(x *Rat) *Rat {
+	C.fmpq_next_signed_calkin_wilf((*C.fmpq)(z), (*C.fmpq)(x))
+	return z
+}
+
+// SimplestBetween sets z to the simplest rational in the closed
+// interval between l and r, the one with the smallest denominator
+// and, among those, the smallest numerator in absolute value, and
+// returns z.
+func (z *Rat) SimplestBetween(l, r *Rat) *Rat {
+	C.fmpq_simplest_between((*C.fmpq)(z), (*C.fmpq)(l), (*C.fmpq)(r))
+	return z
+}
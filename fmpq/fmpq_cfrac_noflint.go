@@ -0,0 +1,169 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpq
+
+import (
+	"math/big"
+
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// CFrac returns the partial quotients a_0, a_1, ..., a_n of the
+// continued fraction expansion
+//
+//	z = a_0 + 1/(a_1 + 1/(a_2 + ... + 1/a_n))
+//
+// in which a_1, ..., a_n are positive and a_n > 1 unless n = 0.
+func (z *Rat) CFrac() []*fmpz.Int {
+	p := new(big.Int).Set(z.big().Num())
+	q := new(big.Int).Set(z.big().Denom())
+	var cs []*fmpz.Int
+	for q.Sign() != 0 {
+		a := fmpz.NewInt(0)
+		(*big.Int)(a).Div(p, q) // floor, as q > 0
+		cs = append(cs, a)
+		p.Sub(p, new(big.Int).Mul((*big.Int)(a), q))
+		p, q = q, p
+	}
+	return cs
+}
+
+// SetCFrac sets z to the value of the continued fraction with
+// partial quotients cs and returns z.  All quotients except cs[0]
+// must be positive.
+func (z *Rat) SetCFrac(cs []*fmpz.Int) *Rat {
+	if len(cs) == 0 {
+		panic("fmpq: empty continued fraction")
+	}
+	for i := range cs {
+		if i > 0 && cs[i].Sign() <= 0 {
+			panic("fmpq: non-positive partial quotient")
+		}
+	}
+	p, q := big.NewInt(1), big.NewInt(0)
+	for i := len(cs) - 1; i >= 0; i-- {
+		// a + q/p = (a*p + q)/p
+		q.Add(q, new(big.Int).Mul((*big.Int)(cs[i]), p))
+		p, q = q, p
+	}
+	z.big().SetFrac(p, q)
+	return z
+}
+
+// FareyNeighbors returns the fractions l and r directly below and
+// above z in the Farey sequence of order q, the sequence of
+// fractions with denominator at most q.  The denominator of z must
+// not exceed q.
+func (z *Rat) FareyNeighbors(q *fmpz.Int) (l, r *Rat) {
+	if z.Denom(fmpz.NewInt(0)).Cmp(q) > 0 {
+		panic("fmpq: Farey order smaller than denominator")
+	}
+	a, b := z.big().Num(), z.big().Denom()
+	Q := (*big.Int)(q)
+
+	// The neighbours c/d of a/b satisfy b*c - a*d = ±1, so d is
+	// determined modulo b; take the largest d <= q.
+	neighbor := func(sign int64) *Rat {
+		d := new(big.Int)
+		if b.Cmp(big.NewInt(1)) != 0 {
+			d.ModInverse(a, b)
+			if sign > 0 {
+				d.Sub(b, d)
+			}
+		}
+		t := new(big.Int).Sub(Q, d)
+		d.Add(d, t.Mul(t.Div(t, b), b))
+		c := new(big.Int).Mul(a, d)
+		c.Add(c, big.NewInt(sign))
+		c.Quo(c, b)
+		x := new(Rat)
+		x.big().SetFrac(c, d)
+		return x
+	}
+	return neighbor(-1), neighbor(1)
+}
+
+// NextCalkinWilf sets z to the successor of x in the breadth-first
+// traversal of the Calkin-Wilf tree, 0, 1, 1/2, 2, 1/3, 3/2, 2/3,
+// 3, ..., which lists every non-negative rational exactly once,
+// and returns z.  x must be non-negative.
+func (z *Rat) NextCalkinWilf(x *Rat) *Rat {
+	if x.big().Sign() < 0 {
+		panic("fmpq: negative argument to NextCalkinWilf")
+	}
+	// The successor of x is 1/(2*floor(x) - x + 1).
+	f := new(big.Int).Div(x.big().Num(), x.big().Denom())
+	f.Lsh(f, 1).Add(f, big.NewInt(1))
+	t := new(big.Rat).SetInt(f)
+	t.Sub(t, x.big())
+	z.big().Inv(t)
+	return z
+}
+
+// NextSignedCalkinWilf sets z to the successor of x in the
+// enumeration 0, 1, -1, 1/2, -1/2, 2, -2, ... of all rationals,
+// which follows the Calkin-Wilf tree, and returns z.
+func (z *Rat) NextSignedCalkinWilf(x *Rat) *Rat {
+	if x.big().Sign() > 0 {
+		return z.Neg(x)
+	}
+	return z.NextCalkinWilf(new(Rat).Neg(x))
+}
+
+// SimplestBetween sets z to the simplest rational in the closed
+// interval between l and r, the one with the smallest denominator
+// and, among those, the smallest numerator in absolute value, and
+// returns z.
+func (z *Rat) SimplestBetween(l, r *Rat) *Rat {
+	a, b := new(big.Rat).Set(l.big()), new(big.Rat).Set(r.big())
+	if a.Cmp(b) > 0 {
+		a, b = b, a
+	}
+	switch {
+	case a.Sign() <= 0 && b.Sign() >= 0:
+		z.big().SetInt64(0)
+	case a.Sign() > 0:
+		z.big().Set(simplestPositive(a, b))
+	default:
+		z.big().Neg(simplestPositive(new(big.Rat).Neg(b), new(big.Rat).Neg(a)))
+	}
+	return z
+}
+
+// simplestPositive returns the simplest rational in [a, b] for
+// 0 < a <= b; a and b are overwritten.
+func simplestPositive(a, b *big.Rat) *big.Rat {
+	// Find the common leading partial quotients of a and b; the
+	// answer shares them and ends where the expansions first differ.
+	var cs []*big.Int
+	one := big.NewInt(1)
+	for {
+		n := new(big.Int).Div(a.Num(), a.Denom())
+		if a.IsInt() {
+			cs = append(cs, n)
+			break
+		}
+		// ceil(a) <= b: ceil(a) is the simplest choice.
+		c := new(big.Int).Add(n, one)
+		if new(big.Rat).SetInt(c).Cmp(b) <= 0 {
+			cs = append(cs, c)
+			break
+		}
+		// floor(a) = floor(b) < a <= b < floor(a) + 1.
+		cs = append(cs, n)
+		t := new(big.Rat).SetInt(n)
+		a.Sub(a, t)
+		b.Sub(b, t)
+		a, b = b.Inv(b), a.Inv(a)
+	}
+	x := new(big.Rat).SetInt(cs[len(cs)-1])
+	for i := len(cs) - 2; i >= 0; i-- {
+		x.Inv(x)
+		x.Add(x, new(big.Rat).SetInt(cs[i]))
+	}
+	return x
+}
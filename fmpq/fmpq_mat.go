@@ -0,0 +1,347 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_vec.h>
+// #include <flint/fmpz_mat.h>
+// #include <flint/fmpq.h>
+// #include <flint/fmpq_poly.h>
+// #include <flint/fmpq_mat.h>
+//
+// // The layout of the matrix structs differs between FLINT
+// // versions; the entry macros do not.
+// static fmpz *goflint_fmpz_mat_entry(fmpz_mat_struct *m, slong i, slong j)
+// {
+// 	return fmpz_mat_entry(m, i, j);
+// }
+//
+// static fmpq *goflint_fmpq_mat_entry(fmpq_mat_struct *m, slong i, slong j)
+// {
+// 	return fmpq_mat_entry(m, i, j);
+// }
+import "C"
+
+import (
+	"runtime"
+	"strings"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// A Mat represents a dense matrix with rational entries.
+type Mat C.fmpq_mat_struct
+
+// NewMat returns the zero matrix with the given dimensions.
+func NewMat(rows, cols int) *Mat {
+	z := new(Mat)
+	C.fmpq_mat_init((*C.fmpq_mat_struct)(z), C.slong(rows), C.slong(cols))
+	runtime.SetFinalizer(z, (*Mat).destroy)
+	return z
+}
+
+// NewMatRats returns the matrix with the given rows of entries.
+// All rows must have the same length.
+func NewMatRats(x [][]*Rat) *Mat {
+	z := NewMat(len(x), cols(len(x), func(i int) int { return len(x[i]) }))
+	for i, row := range x {
+		for j, a := range row {
+			z.SetEntry(i, j, a)
+		}
+	}
+	return z
+}
+
+// NewMatFrac returns the matrix with entries num[i][j]/den[i][j].
+// If den is nil, the entries are the integers num[i][j].  All rows
+// must have the same length.  A zero denominator panics with
+// flint.ErrDivisionByZero.
+func NewMatFrac(num, den [][]int64) *Mat {
+	z := NewMat(len(num), cols(len(num), func(i int) int { return len(num[i]) }))
+	if den != nil && len(den) != len(num) {
+		panic("fmpq: Mat dimension mismatch")
+	}
+	q := NewRat(0, 1)
+	a, b := fmpz.NewInt(0), fmpz.NewInt(0)
+	for i, row := range num {
+		if den != nil && len(den[i]) != len(row) {
+			panic("fmpq: Mat dimension mismatch")
+		}
+		for j, p := range row {
+			d := int64(1)
+			if den != nil {
+				d = den[i][j]
+			}
+			if d == 0 {
+				flint.Panic("fmpq.NewMatFrac", flint.ErrDivisionByZero)
+			}
+			// SetFrac normalises the sign, which negating p and d
+			// would not for math.MinInt64.
+			z.SetEntry(i, j, q.SetFrac(a.SetInt64(p), b.SetInt64(d)))
+		}
+	}
+	return z
+}
+
+// cols returns the common length of n rows, or panics.
+func cols(n int, length func(i int) int) int {
+	if n == 0 {
+		return 0
+	}
+	c := length(0)
+	for i := 1; i < n; i++ {
+		if length(i) != c {
+			panic("fmpq: rows of different length")
+		}
+	}
+	return c
+}
+
+func (z *Mat) destroy() {
+	C.fmpq_mat_clear((*C.fmpq_mat_struct)(z))
+}
+
+func (z *Mat) mat() *C.fmpq_mat_struct {
+	return (*C.fmpq_mat_struct)(z)
+}
+
+// Rows returns the number of rows of z.
+func (z *Mat) Rows() int {
+	return int(z.r)
+}
+
+// Cols returns the number of columns of z.
+func (z *Mat) Cols() int {
+	return int(z.c)
+}
+
+// dims panics unless z has the given dimensions.
+func (z *Mat) dims(rows, cols int) {
+	if z.Rows() != rows || z.Cols() != cols {
+		panic("fmpq: Mat dimension mismatch")
+	}
+}
+
+// entry returns a pointer to the entry of z in row i and column j.
+func (z *Mat) entry(i, j int) *C.fmpq {
+	if i < 0 || i >= z.Rows() || j < 0 || j >= z.Cols() {
+		panic("fmpq: Mat index out of range")
+	}
+	return C.goflint_fmpq_mat_entry(z.mat(), C.slong(i), C.slong(j))
+}
+
+// Entry returns a new Rat equal to the entry of z in row i and
+// column j.
+func (z *Mat) Entry(i, j int) *Rat {
+	x := NewRat(0, 1)
+	C.fmpq_set((*C.fmpq)(x), z.entry(i, j))
+	return x
+}
+
+// SetEntry sets the entry of z in row i and column j to x and
+// returns z.
+func (z *Mat) SetEntry(i, j int, x *Rat) *Mat {
+	C.fmpq_set(z.entry(i, j), (*C.fmpq)(x))
+	return z
+}
+
+// Set sets z = x and returns z.  The matrices must have the same
+// dimensions.
+func (z *Mat) Set(x *Mat) *Mat {
+	z.dims(x.Rows(), x.Cols())
+	C.fmpq_mat_set(z.mat(), x.mat())
+	return z
+}
+
+// Equal reports whether z and x are equal.
+func (z *Mat) Equal(x *Mat) bool {
+	if z.Rows() != x.Rows() || z.Cols() != x.Cols() {
+		return false
+	}
+	return C.fmpq_mat_equal(z.mat(), x.mat()) != 0
+}
+
+// String returns a string representation of z, one row per line.
+func (z *Mat) String() string {
+	var b strings.Builder
+	for i := 0; i < z.Rows(); i++ {
+		b.WriteString("[")
+		for j := 0; j < z.Cols(); j++ {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(z.Entry(i, j).String())
+		}
+		b.WriteString("]\n")
+	}
+	return b.String()
+}
+
+// Add sets z = x + y and returns z.
+func (z *Mat) Add(x, y *Mat) *Mat {
+	z.dims(x.Rows(), x.Cols())
+	y.dims(x.Rows(), x.Cols())
+	C.fmpq_mat_add(z.mat(), x.mat(), y.mat())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *Mat) Sub(x, y *Mat) *Mat {
+	z.dims(x.Rows(), x.Cols())
+	y.dims(x.Rows(), x.Cols())
+	C.fmpq_mat_sub(z.mat(), x.mat(), y.mat())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *Mat) Neg(x *Mat) *Mat {
+	z.dims(x.Rows(), x.Cols())
+	C.fmpq_mat_neg(z.mat(), x.mat())
+	return z
+}
+
+// ScalarMul sets z = c*x and returns z.
+func (z *Mat) ScalarMul(x *Mat, c *Rat) *Mat {
+	z.dims(x.Rows(), x.Cols())
+	C.fmpq_mat_scalar_mul_fmpq(z.mat(), x.mat(), (*C.fmpq)(c))
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *Mat) Mul(x, y *Mat) *Mat {
+	if x.Cols() != y.Rows() {
+		panic("fmpq: Mat dimension mismatch")
+	}
+	z.dims(x.Rows(), y.Cols())
+	t := NewMat(x.Rows(), y.Cols())
+	C.fmpq_mat_mul(t.mat(), x.mat(), y.mat())
+	return z.Set(t)
+}
+
+// Det returns the determinant of the square matrix z.
+func (z *Mat) Det() *Rat {
+	if z.Rows() != z.Cols() {
+		panic("fmpq: determinant of a non-square Mat")
+	}
+	d := NewRat(0, 1)
+	C.fmpq_mat_det((*C.fmpq)(d), z.mat())
+	return d
+}
+
+// Rank returns the rank of z.
+func (z *Mat) Rank() int {
+	t := NewMat(z.Rows(), z.Cols())
+	return int(C.fmpq_mat_rref(t.mat(), z.mat()))
+}
+
+// RREF sets z to the reduced row echelon form of x and returns z
+// and the rank of x.
+func (z *Mat) RREF(x *Mat) (*Mat, int) {
+	z.dims(x.Rows(), x.Cols())
+	r := int(C.fmpq_mat_rref(z.mat(), x.mat()))
+	return z, r
+}
+
+// Inv sets z to the inverse of x and returns (z, true).  If x is
+// singular, z is unchanged and Inv returns (z, false).
+func (z *Mat) Inv(x *Mat) (*Mat, bool) {
+	n := x.Rows()
+	if x.Cols() != n {
+		panic("fmpq: inverse of a non-square Mat")
+	}
+	z.dims(n, n)
+	t := NewMat(n, n)
+	if C.fmpq_mat_inv(t.mat(), x.mat()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Solve sets z to the solution X of a*X = b for a square matrix a
+// and returns (z, true), using Dixon's p-adic lifting algorithm.
+// If a is singular, z is unchanged and Solve returns (z, false).
+func (z *Mat) Solve(a, b *Mat) (*Mat, bool) {
+	n := a.Rows()
+	if a.Cols() != n || b.Rows() != n {
+		panic("fmpq: Mat dimension mismatch")
+	}
+	z.dims(n, b.Cols())
+	t := NewMat(n, b.Cols())
+	if C.fmpq_mat_solve_dixon(t.mat(), a.mat(), b.mat()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// SolveMultiMod is like Solve, but uses a multimodular algorithm,
+// which can be faster when the solution has small height.
+func (z *Mat) SolveMultiMod(a, b *Mat) (*Mat, bool) {
+	n := a.Rows()
+	if a.Cols() != n || b.Rows() != n {
+		panic("fmpq: Mat dimension mismatch")
+	}
+	z.dims(n, b.Cols())
+	t := NewMat(n, b.Cols())
+	if C.fmpq_mat_solve_multi_mod(t.mat(), a.mat(), b.mat()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Nullspace returns a matrix whose columns form a basis of the
+// right nullspace {v : z*v = 0} of z.  The result has z.Cols()
+// rows and as many columns as the nullity of z.
+func (z *Mat) Nullspace() *Mat {
+	// Scaling each row by its denominator does not change the
+	// nullspace, so compute it over the integers.
+	a := C.fmpz_mat_struct{}
+	C.fmpz_mat_init(&a, C.slong(z.Rows()), C.slong(z.Cols()))
+	defer C.fmpz_mat_clear(&a)
+	den := C._fmpz_vec_init(C.slong(z.Rows()))
+	defer C._fmpz_vec_clear(den, C.slong(z.Rows()))
+	C.fmpq_mat_get_fmpz_mat_rowwise(&a, den, z.mat())
+
+	b := C.fmpz_mat_struct{}
+	C.fmpz_mat_init(&b, C.slong(z.Cols()), C.slong(z.Cols()))
+	defer C.fmpz_mat_clear(&b)
+	k := int(C.fmpz_mat_nullspace(&b, &a))
+
+	n := NewMat(z.Cols(), k)
+	for i := 0; i < z.Cols(); i++ {
+		for j := 0; j < k; j++ {
+			e := n.entry(i, j)
+			C.fmpz_set(&e.num, C.goflint_fmpz_mat_entry(&b, C.slong(i), C.slong(j)))
+			C.fmpz_one(&e.den)
+		}
+	}
+	return n
+}
+
+// CharPoly returns the characteristic polynomial of the square
+// matrix z.
+func (z *Mat) CharPoly() *Poly {
+	if z.Rows() != z.Cols() {
+		panic("fmpq: characteristic polynomial of a non-square Mat")
+	}
+	p := NewPoly(0)
+	C.fmpq_mat_charpoly((*C.fmpq_poly_struct)(p), z.mat())
+	return p
+}
+
+// IntMat returns an integer matrix n and a positive integer d,
+// the least common denominator of the entries of z, such that
+// z = n/d.
+func (z *Mat) IntMat() (*fmpz.Mat, *fmpz.Int) {
+	n := fmpz.NewMat(z.Rows(), z.Cols())
+	d := fmpz.NewInt(0)
+	C.fmpq_mat_get_fmpz_mat_matwise((*C.fmpz_mat_struct)(unsafe.Pointer(n)), (*C.fmpz)(d), z.mat())
+	return n, d
+}
@@ -0,0 +1,112 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpq
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+func TestMat(t *testing.T) {
+	m := NewMatFrac([][]int64{{1, 1}, {1, 1}}, [][]int64{{1, 2}, {3, -4}})
+	if s := m.String(); s != "[1/1, 1/2]\n[1/3, -1/4]\n" {
+		t.Errorf("String = %q", s)
+	}
+	if m.Rows() != 2 || m.Cols() != 2 || m.Entry(1, 0).String() != "1/3" {
+		t.Errorf("m is %dx%d with m[1][0] = %v", m.Rows(), m.Cols(), m.Entry(1, 0))
+	}
+	if d := m.Det(); d.String() != "-5/12" {
+		t.Errorf("Det = %v, want -5/12", d)
+	}
+	if p := m.CharPoly(); p.String() != "x^2 - 3/4*x - 5/12" {
+		t.Errorf("CharPoly = %v, want x^2 - 3/4*x - 5/12", p)
+	}
+
+	two := NewMat(2, 2).ScalarMul(m, NewRat(2, 1))
+	if s := NewMat(2, 2).Add(m, m); !s.Equal(two) {
+		t.Errorf("m + m = %v, want %v", s, two)
+	}
+	if s := NewMat(2, 2).Sub(two, m); !s.Equal(m) {
+		t.Errorf("2m - m = %v, want %v", s, m)
+	}
+	if s := NewMat(2, 2).Add(m, NewMat(2, 2).Neg(m)); !s.Equal(NewMat(2, 2)) {
+		t.Errorf("m - m = %v, want 0", s)
+	}
+	if m.Equal(NewMat(2, 3)) {
+		t.Errorf("matrices of different shapes are equal")
+	}
+	mn := NewMatFrac([][]int64{{math.MinInt64, 1, math.MinInt64}}, [][]int64{{-1, math.MinInt64, math.MinInt64}})
+	if s := mn.String(); s != "[9223372036854775808/1, -1/9223372036854775808, 1/1]\n" {
+		t.Errorf("NewMatFrac with math.MinInt64 = %q", s)
+	}
+	if err := flint.Try(func() { NewMatFrac([][]int64{{1}}, [][]int64{{0}}) }); !errors.Is(err, flint.ErrDivisionByZero) {
+		t.Errorf("NewMatFrac with a zero denominator: err = %v, want ErrDivisionByZero", err)
+	}
+
+	n, d := m.IntMat()
+	if d.Int64() != 12 || n.Entry(0, 1).Int64() != 6 || n.Entry(1, 1).Int64() != -3 {
+		t.Errorf("IntMat = %v / %v", n, d)
+	}
+
+	r, rank := NewMat(2, 3).RREF(NewMatFrac([][]int64{{1, 2, 3}, {2, 4, 7}}, nil))
+	if rank != 2 || !r.Equal(NewMatFrac([][]int64{{1, 2, 0}, {0, 0, 1}}, nil)) {
+		t.Errorf("RREF = %v, %d", r, rank)
+	}
+}
+
+func TestMatSolve(t *testing.T) {
+	m := NewMatFrac([][]int64{{1, 1}, {1, 1}}, [][]int64{{1, 2}, {3, -4}})
+	id := NewMatFrac([][]int64{{1, 0}, {0, 1}}, nil)
+	inv, ok := NewMat(2, 2).Inv(m)
+	want := NewMatFrac([][]int64{{3, 6}, {4, -12}}, [][]int64{{5, 5}, {5, 5}})
+	if !ok || !inv.Equal(want) {
+		t.Errorf("Inv = %v, %v, want %v", inv, ok, want)
+	}
+	if p := NewMat(2, 2).Mul(m, inv); !p.Equal(id) {
+		t.Errorf("m * m^-1 = %v, want 1", p)
+	}
+
+	b := NewMatFrac([][]int64{{1}, {0}}, nil)
+	for name, solve := range map[string]func(z, a, b *Mat) (*Mat, bool){
+		"Solve":         (*Mat).Solve,
+		"SolveMultiMod": (*Mat).SolveMultiMod,
+	} {
+		x, ok := solve(NewMat(2, 1), m, b)
+		if !ok || !NewMat(2, 1).Mul(m, x).Equal(b) {
+			t.Errorf("%s = %v, %v", name, x, ok)
+		}
+	}
+
+	s := NewMatFrac([][]int64{{1, 2}, {2, 4}}, nil)
+	if s.Rank() != 1 || s.Det().String() != "0/1" {
+		t.Errorf("singular matrix has rank %d and det %v", s.Rank(), s.Det())
+	}
+	if z, ok := NewMat(2, 2).Inv(s); ok || !z.Equal(NewMat(2, 2)) {
+		t.Errorf("Inv of a singular matrix = %v, %v", z, ok)
+	}
+	if _, ok := NewMat(2, 1).Solve(s, b); ok {
+		t.Errorf("Solve with a singular matrix succeeded")
+	}
+}
+
+func TestMatNullspace(t *testing.T) {
+	// The second row is 3/2 times the first.
+	m := NewMatFrac([][]int64{{1, 1, 1}, {3, 1, 3}}, [][]int64{{2, 3, 1}, {4, 2, 2}})
+	n := m.Nullspace()
+	if n.Rows() != 3 || n.Cols() != 2 || n.Rank() != 2 {
+		t.Fatalf("Nullspace = %v, want a 3x2 matrix of rank 2", n)
+	}
+	if p := NewMat(2, 2).Mul(m, n); !p.Equal(NewMat(2, 2)) {
+		t.Errorf("m * Nullspace = %v, want 0", p)
+	}
+	if n := NewMatFrac([][]int64{{1, 0}, {0, 1}}, nil).Nullspace(); n.Rows() != 2 || n.Cols() != 0 {
+		t.Errorf("Nullspace of 1 is %dx%d, want 2x0", n.Rows(), n.Cols())
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+	"github.com/frithjof-schulze/go.flint/internal/testutil"
+)
+
+func mpoly(t *testing.T, c *MPolyCtx, s string) *MPoly {
+	t.Helper()
+	return mustMPoly(t)(NewMPoly(c).SetString(s))
+}
+
+var mustMPoly = testutil.Must[*MPoly]
+
+func TestMPolyArith(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, fmpz.Lex)
+	p := mpoly(t, c, "x^2 - 1/4*y^2")
+	a := mpoly(t, c, "x + 1/2*y")
+	b := mpoly(t, c, "x - 1/2*y")
+	for _, tc := range []struct {
+		name      string
+		got, want *MPoly
+	}{
+		{"Mul", NewMPoly(c).Mul(a, b), p},
+		{"Add", NewMPoly(c).Add(a, b), mpoly(t, c, "2*x")},
+		{"Sub", NewMPoly(c).Sub(a, b), mpoly(t, c, "y")},
+		{"ScalarMul", NewMPoly(c).ScalarMul(a, NewRat(2, 3)), mpoly(t, c, "2/3*x + 1/3*y")},
+		{"SetRat", NewMPoly(c).SetRat(NewRat(-1, 2)), mpoly(t, c, "-1/2")},
+		{"SetTerms", NewMPoly(c).SetTerms([]*Rat{NewRat(1, 1), NewRat(-1, 4)}, [][]uint64{{2, 0}, {0, 2}}), p},
+		{"SetMonomial", NewMPoly(c).SetMonomial([]uint64{1, 3}), mpoly(t, c, "x*y^3")},
+		{"MakeMonic", NewMPoly(c).MakeMonic(mpoly(t, c, "3*x - y")), mpoly(t, c, "x - 1/3*y")},
+		{"Derivative", NewMPoly(c).Derivative(p, 1), mpoly(t, c, "-1/2*y")},
+		{"EvaluateOne", mustMPoly(t)(NewMPoly(c).EvaluateOne(p, 1, NewRat(2, 1))), mpoly(t, c, "x^2 - 1")},
+		{"Compose", mustMPoly(t)(NewMPoly(c).Compose(a, []*MPoly{mpoly(t, c, "y"), mpoly(t, c, "2*x")})), mpoly(t, c, "x + y")},
+		{"Pow", mustMPoly(t)(NewMPoly(c).Pow(b, 2)), mpoly(t, c, "x^2 - x*y + 1/4*y^2")},
+		{"Div", mustMPoly(t)(NewMPoly(c).Div(p, a)), b},
+		{"GCD", mustMPoly(t)(NewMPoly(c).GCD(mpoly(t, c, "2*x^2 - 1/2*y^2"), mpoly(t, c, "3*x + 3/2*y"))), a},
+	} {
+		if !tc.got.Equal(tc.want) {
+			t.Errorf("%s = %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+
+	if v, ok := p.Evaluate([]*Rat{NewRat(1, 2), NewRat(1, 1)}); !ok || v.String() != "0/1" {
+		t.Errorf("p(1/2, 1) = %v, %v, want 0/1", v, ok)
+	}
+	if p.Degree(0) != 2 || a.Degree(1) != 1 || p.TotalDegree() != 2 {
+		t.Errorf("Degree and TotalDegree give wrong results")
+	}
+	if k, e := p.Term(1); k.String() != "-1/4" || e[0] != 0 || e[1] != 2 {
+		t.Errorf("Term(1) = %v, %v, want -1/4, [0 2]", k, e)
+	}
+	if e := p.LeadExp(); e[0] != 2 || e[1] != 0 {
+		t.Errorf("LeadExp = %v, want [2 0]", e)
+	}
+}
+
+func TestMPolyReduce(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, fmpz.Lex)
+	gs := []*MPoly{mpoly(t, c, "x - y"), mpoly(t, c, "y^2 - 2")}
+	for _, tc := range []struct {
+		x, want string
+	}{
+		{"x^2", "2"},
+		{"x*y + 1/2", "5/2"},
+		{"x^3 + y", "3*y"},
+	} {
+		if got := NewMPoly(c).Reduce(mpoly(t, c, tc.x), gs); !got.Equal(mpoly(t, c, tc.want)) {
+			t.Errorf("Reduce(%s) = %v, want %s", tc.x, got, tc.want)
+		}
+	}
+	x := mpoly(t, c, "x + 1")
+	if got := NewMPoly(c).Reduce(x, nil); !got.Equal(x) {
+		t.Errorf("Reduce(x + 1, nil) = %v, want x + 1", got)
+	}
+}
+
+func TestMPolyFactor(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, fmpz.DegLex)
+	p := mpoly(t, c, "1/2*x^3 - 1/2*x*y^2")
+	k, fs, es, ok := p.Factor()
+	if !ok || k.String() != "1/2" || len(fs) != 3 {
+		t.Fatalf("Factor = %v, %v, %v, %v", k, fs, es, ok)
+	}
+	prod := NewMPoly(c).SetRat(k)
+	for i := range fs {
+		if es[i] != 1 {
+			t.Errorf("factor %v has multiplicity %d, want 1", fs[i], es[i])
+		}
+		prod.Mul(prod, fs[i])
+	}
+	if !prod.Equal(p) {
+		t.Errorf("product of the factors = %v, want %v", prod, p)
+	}
+}
+
+func TestMPolyErrors(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, fmpz.DegRevLex)
+	if _, ok := NewMPoly(c).SetString("x + w"); ok {
+		t.Errorf("SetString(%q) succeeded", "x + w")
+	}
+	p := mpoly(t, c, "x*y + 1/3")
+	zero := NewMPoly(c)
+	for name, f := range map[string]func(){
+		"Div":       func() { NewMPoly(c).Div(p, zero) },
+		"DivMod":    func() { NewMPoly(c).DivMod(p, zero, NewMPoly(c)) },
+		"MakeMonic": func() { NewMPoly(c).MakeMonic(zero) },
+		"Reduce":    func() { NewMPoly(c).Reduce(p, []*MPoly{zero}) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDivisionByZero) {
+			t.Errorf("%s by zero: err = %v, want ErrDivisionByZero", name, err)
+		}
+	}
+	for name, f := range map[string]func(){
+		"Degree(-1)":     func() { p.Degree(-1) },
+		"Degree(2)":      func() { p.Degree(2) },
+		"Derivative(2)":  func() { NewMPoly(c).Derivative(p, 2) },
+		"EvaluateOne(2)": func() { NewMPoly(c).EvaluateOne(p, 2, NewRat(1, 1)) },
+		"SetMonomial":    func() { NewMPoly(c).SetMonomial([]uint64{1}) },
+		"LeadExp":        func() { zero.LeadExp() },
+	} {
+		if !testutil.Misuse("fmpq", f) {
+			t.Errorf("%s did not report misuse", name)
+		}
+	}
+}
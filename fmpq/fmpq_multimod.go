@@ -0,0 +1,135 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpq
+
+import (
+	"math/bits"
+	"runtime"
+	"sync"
+
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// A ModularFunc performs a computation modulo the word-size prime p
+// and returns its result as a vector of residues.  It returns false
+// if p is bad for the problem, for instance because p divides a
+// denominator of the input.  A ModularFunc is called concurrently
+// from several goroutines, so it must be safe for concurrent use.
+type ModularFunc func(p uint64) ([]uint64, bool)
+
+// Multimodular lifts the result of a computation over Q from its
+// images under f modulo a sequence of word-size primes, and returns
+// it and true.  The primes are processed in batches, one goroutine
+// per available CPU; after each batch the residues are combined by
+// Chinese remaindering and rational reconstruction.  Multimodular
+// returns the reconstruction as soon as it is the same after two
+// successive batches and, if check is not nil, check accepts it.
+//
+// As with rank or degree drops in linear algebra, a prime whose
+// result is shorter than the one being lifted is considered unlucky
+// and ignored, while a longer result restarts the lifting.
+//
+// maxBits bounds the work: once the primes tried, bad and unlucky
+// ones included, have more than maxBits bits in total, Multimodular
+// runs one more batch to confirm the result and otherwise gives up
+// and returns (nil, false).  It should exceed the size of the
+// modulus needed to reconstruct the result.
+func Multimodular(f ModularFunc, check func([]*Rat) bool, maxBits int) ([]*Rat, bool) {
+	var (
+		workers = runtime.GOMAXPROCS(0)
+		p       = uint64(1) << 62
+		started bool
+		cs      []*fmpz.Int // the result modulo m
+		m       = fmpz.NewInt(1)
+		prev    []*Rat
+		used    int // bits of the primes tried
+		last    bool
+	)
+	for {
+		if used > maxBits {
+			if last {
+				return nil, false
+			}
+			last = true
+		}
+		ps := make([]uint64, workers)
+		for i := range ps {
+			p = nextPrime(p)
+			ps[i] = p
+			used += bits.Len64(p)
+		}
+		rs := make([][]uint64, workers)
+		oks := make([]bool, workers)
+		var wg sync.WaitGroup
+		for i := range ps {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				rs[i], oks[i] = f(ps[i])
+			}(i)
+		}
+		wg.Wait()
+
+		t, q := fmpz.NewInt(0), fmpz.NewInt(0)
+		for i, r := range rs {
+			if !oks[i] || len(r) < len(cs) {
+				continue
+			}
+			if !started || len(r) > len(cs) {
+				started = true
+				cs = make([]*fmpz.Int, len(r))
+				for j := range cs {
+					cs[j] = fmpz.NewInt(0)
+				}
+				m.SetInt64(1)
+				prev = nil
+			}
+			q.SetUint64(ps[i])
+			for j, c := range r {
+				cs[j].CRT(cs[j], m, t.SetUint64(c), q, false)
+			}
+			m.Mul(m, q)
+		}
+		if !started {
+			continue
+		}
+
+		cur, ok := reconstruct(cs, m)
+		if !ok {
+			prev = nil
+			continue
+		}
+		if equalRats(cur, prev) && (check == nil || check(cur)) {
+			return cur, true
+		}
+		prev = cur
+	}
+}
+
+// reconstruct returns the rational reconstructions of cs modulo m.
+func reconstruct(cs []*fmpz.Int, m *fmpz.Int) ([]*Rat, bool) {
+	rs := make([]*Rat, len(cs))
+	for i, c := range cs {
+		var ok bool
+		if rs[i], ok = NewRat(0, 1).Reconstruct(c, m); !ok {
+			return nil, false
+		}
+	}
+	return rs, true
+}
+
+// equalRats reports whether xs and ys are equal; a nil slice equals
+// nothing.
+func equalRats(xs, ys []*Rat) bool {
+	if xs == nil || ys == nil || len(xs) != len(ys) {
+		return false
+	}
+	for i := range xs {
+		if xs[i].Cmp(ys[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpq
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// residues returns a ModularFunc computing xs mod p.
+func residues(xs []*Rat) ModularFunc {
+	return func(p uint64) ([]uint64, bool) {
+		q := fmpz.NewInt(0).SetUint64(p)
+		num, den := fmpz.NewInt(0), fmpz.NewInt(0)
+		rs := make([]uint64, len(xs))
+		for i, x := range xs {
+			x.Num(num)
+			if x.Denom(den).FRemUint64(p) == 0 {
+				return nil, false
+			}
+			den.Exp(den, fmpz.NewInt(-1), q)
+			rs[i] = num.Mul(num, den).FRemUint64(p)
+		}
+		return rs, true
+	}
+}
+
+func TestMultimodular(t *testing.T) {
+	n, _ := fmpz.NewInt(0).SetString("-123456789012345678901234567", 10)
+	d, _ := fmpz.NewInt(0).SetString("98765432109876543", 10)
+	want := []*Rat{NewRat(1, 3), NewRat(-22, 7), NewRat(0, 1), NewRat(0, 1).SetFrac(n, d)}
+
+	var calls atomic.Int64
+	f := residues(want)
+	counted := func(p uint64) ([]uint64, bool) {
+		calls.Add(1)
+		return f(p)
+	}
+	got, ok := Multimodular(counted, nil, 1000)
+	if !ok || !equalRats(got, want) {
+		t.Errorf("Multimodular = %v, %v, want %v", got, ok, want)
+	}
+	if calls.Load() == 0 {
+		t.Errorf("Multimodular did not call f")
+	}
+
+	// Primes with a shorter result are unlucky and skipped.
+	unlucky := func(p uint64) ([]uint64, bool) {
+		rs, ok := f(p)
+		if p%4 == 1 {
+			rs = rs[:2]
+		}
+		return rs, ok
+	}
+	checked := false
+	check := func(rs []*Rat) bool {
+		checked = true
+		return len(rs) == len(want)
+	}
+	if got, ok := Multimodular(unlucky, check, 1000); !ok || !equalRats(got, want) || !checked {
+		t.Errorf("Multimodular with unlucky primes = %v, %v, want %v", got, ok, want)
+	}
+}
+
+func TestMultimodularLimit(t *testing.T) {
+	xs := []*Rat{NewRat(1, 3)}
+	bad := func(p uint64) ([]uint64, bool) { return nil, false }
+	reject := func([]*Rat) bool { return false }
+	if got, ok := Multimodular(bad, nil, 1000); ok || got != nil {
+		t.Errorf("Multimodular with only bad primes = %v, %v", got, ok)
+	}
+	if got, ok := Multimodular(residues(xs), reject, 1000); ok || got != nil {
+		t.Errorf("Multimodular with a rejecting check = %v, %v", got, ok)
+	}
+
+	// Two batches cannot reconstruct a numerator of this size.
+	workers := runtime.GOMAXPROCS(0)
+	huge := NewRat(0, 1).SetFrac(fmpz.NewInt(0).Lsh(fmpz.NewInt(1), uint(300*workers)), fmpz.NewInt(3))
+	if got, ok := Multimodular(residues([]*Rat{huge}), nil, 0); ok || got != nil {
+		t.Errorf("Multimodular beyond its limit = %v, %v", got, ok)
+	}
+}
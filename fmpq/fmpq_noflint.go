@@ -0,0 +1,142 @@
+// Copyright 2010 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpq
+
+import (
+	"math/big"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// An Rat represents a multi-precision rational number.  The
+// zero value for an Rat represents the value 0.
+//
+// This is the pure Go backend, used when go.flint is built with
+// the noflint tag or without cgo.  It is implemented on math/big
+// and has the same methods and semantics as the FLINT backend.
+type Rat big.Rat
+
+// NewRat returns a new Rat initialized to x.
+func NewRat(a, b int64) *Rat { return new(Rat).SetRat64(a, b) }
+
+func (z *Rat) big() *big.Rat { return (*big.Rat)(z) }
+
+// Denom sets x to the denominator of z and returns x.
+func (z *Rat) Denom(x *fmpz.Int) *fmpz.Int {
+	(*big.Int)(x).Set(z.big().Denom())
+	return x
+}
+
+// Num sets x to the numerator of z and returns x.
+func (z *Rat) Num(x *fmpz.Int) *fmpz.Int {
+	(*big.Int)(x).Set(z.big().Num())
+	return x
+}
+
+// Set sets z = x and returns z.
+func (z *Rat) Set(x *Rat) *Rat {
+	z.big().Set(x.big())
+	return z
+}
+
+// SetRat64 sets z = p/q and returns z.
+func (z *Rat) SetRat64(p, q int64) *Rat {
+	if q == 0 {
+		flint.Panic("fmpq.Rat.SetRat64", flint.ErrDivisionByZero)
+	}
+	z.big().SetFrac64(p, q)
+	return z
+}
+
+// SetFrac sets z = a/b and returns z.  If b is zero, a
+// division-by-zero run-time panic occurs.
+func (z *Rat) SetFrac(a, b *fmpz.Int) *Rat {
+	if b.Sign() == 0 {
+		flint.Panic("fmpq.Rat.SetFrac", flint.ErrDivisionByZero)
+	}
+	z.big().SetFrac((*big.Int)(a), (*big.Int)(b))
+	return z
+}
+
+// String returns the decimal representation of z.
+func (z *Rat) String() string {
+	return z.big().String()
+}
+
+// Add sets z = x + y and returns z.
+func (z *Rat) Add(x, y *Rat) *Rat {
+	z.big().Add(x.big(), y.big())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *Rat) Sub(x, y *Rat) *Rat {
+	z.big().Sub(x.big(), y.big())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *Rat) Mul(x, y *Rat) *Rat {
+	z.big().Mul(x.big(), y.big())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *Rat) Neg(x *Rat) *Rat {
+	z.big().Neg(x.big())
+	return z
+}
+
+// Div sets z = x / y and returns z.  If y == 0, a division-by-zero
+// run-time panic occurs.
+func (z *Rat) Div(x, y *Rat) *Rat {
+	if y.big().Sign() == 0 {
+		flint.Panic("fmpq.Rat.Div", flint.ErrDivisionByZero)
+	}
+	z.big().Quo(x.big(), y.big())
+	return z
+}
+
+// TryDiv is like Div, but if y == 0 it leaves z unchanged and
+// returns an *flint.Error wrapping flint.ErrDivisionByZero instead
+// of panicking.
+func (z *Rat) TryDiv(x, y *Rat) (*Rat, error) {
+	if y.big().Sign() == 0 {
+		return z, &flint.Error{Op: "fmpq.Rat.TryDiv", Err: flint.ErrDivisionByZero}
+	}
+	return z.Div(x, y), nil
+}
+
+// Inv sets z = 1/x and returns z.  If x == 0, a division-by-zero
+// run-time panic occurs.
+func (z *Rat) Inv(x *Rat) *Rat {
+	if x.big().Sign() == 0 {
+		flint.Panic("fmpq.Rat.Inv", flint.ErrDivisionByZero)
+	}
+	z.big().Inv(x.big())
+	return z
+}
+
+// TryInv is like Inv, but if x == 0 it leaves z unchanged and
+// returns an *flint.Error wrapping flint.ErrDivisionByZero instead
+// of panicking.
+func (z *Rat) TryInv(x *Rat) (*Rat, error) {
+	if x.big().Sign() == 0 {
+		return z, &flint.Error{Op: "fmpq.Rat.TryInv", Err: flint.ErrDivisionByZero}
+	}
+	return z.Inv(x), nil
+}
+
+// Cmp compares z and y and returns:
+//
+//	-1 if z <  y
+//	 0 if z == y
+//	+1 if z >  y
+func (z *Rat) Cmp(y *Rat) (r int) {
+	return z.big().Cmp(y.big())
+}
@@ -2,17 +2,20 @@
 // Use of this source code is governed by the GNU General
 // Public License version 2 (or any later version).
 
+//go:build !noflint
+
 package fmpq
 
-//#cgo LDFLAGS: -lflint -lmpir -lmpfr -lm
 // #include <stdlib.h>
-// #include <flint.h>
-// #include <fmpq.h>
-// #include <fmpq_poly.h>
+// #include <flint/flint.h>
+// #include <flint/fmpq.h>
+// #include <flint/fmpq_poly.h>
 import "C"
 
 import (
 	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
 )
 
 type Poly C.fmpq_poly_struct
@@ -37,13 +40,13 @@ func (z *Poly) Set(x *Poly) *Poly {
 // SetInt64 sets z = x and returns z.
 func (z *Poly) SetInt64(x int64) *Poly {
 	// TODO(rsc): more work on 32-bit platforms
-	C.fmpq_poly_set_si((*C.fmpq_poly_struct)(z), C.long(x))
+	C.fmpq_poly_set_si((*C.fmpq_poly_struct)(z), C.slong(x))
 	return z
 }
 
 // SetCoeff64 
 func (z *Poly) SetCoeff64(n, c int64) *Poly {
-	C.fmpq_poly_set_coeff_si((*C.fmpq_poly_struct)(z), C.long(n), C.long(c))
+	C.fmpq_poly_set_coeff_si((*C.fmpq_poly_struct)(z), C.slong(n), C.slong(c))
 	return z
 }
 
@@ -108,7 +111,7 @@ func (z *Poly) Exp(x *Poly, n uint64) *Poly {
 
 // ScalarMul64 sets z = c*x and returns z.
 func (z *Poly) ScalarMul64(x *Poly, c int64) *Poly {
-	C.fmpq_poly_scalar_mul_si((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), C.long(c))
+	C.fmpq_poly_scalar_mul_si((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), C.slong(c))
 	return z
 }
 
@@ -122,17 +125,93 @@ func (z *Poly) Neg(x *Poly) *Poly {
  * functions without a clear receiver
  */
 
+// ExpSeries sets z to the first n terms of exp(x) and returns z.
+// If the constant term of x is not zero, ExpSeries panics with
+// flint.ErrDomain.
 func (z *Poly) ExpSeries(x *Poly, n int64) *Poly {
-	C.fmpq_poly_exp_series((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), C.long(n))
+	if c := x.Coeff(0); C.fmpq_is_zero((*C.fmpq)(c)) == 0 {
+		flint.Panic("fmpq.Poly.ExpSeries", flint.ErrDomain)
+	}
+	C.fmpq_poly_exp_series((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), C.slong(n))
 	return z
 }
 
 func (z *Poly) MulLow(x, y *Poly, n int64) *Poly {
-	C.fmpq_poly_mullow((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), (*C.fmpq_poly_struct)(y), C.long(n))
+	C.fmpq_poly_mullow((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), (*C.fmpq_poly_struct)(y), C.slong(n))
 	return z
 }
 
+// DivSeries sets z to the first n terms of the power series x/y
+// and returns z.  If the constant term of y is zero, a
+// division-by-zero run-time panic occurs.
 func (z *Poly) DivSeries(x, y *Poly, n int64) *Poly {
-	C.fmpq_poly_div_series((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), (*C.fmpq_poly_struct)(y), C.long(n))
+	if c := y.Coeff(0); C.fmpq_is_zero((*C.fmpq)(c)) != 0 {
+		flint.Panic("fmpq.Poly.DivSeries", flint.ErrDivisionByZero)
+	}
+	C.fmpq_poly_div_series((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), (*C.fmpq_poly_struct)(y), C.slong(n))
+	return z
+}
+
+// TryDivSeries is like DivSeries, but if the constant term of y
+// is zero it leaves z unchanged and returns an *flint.Error wrapping
+// flint.ErrDivisionByZero instead of panicking.
+func (z *Poly) TryDivSeries(x, y *Poly, n int64) (*Poly, error) {
+	if c := y.Coeff(0); C.fmpq_is_zero((*C.fmpq)(c)) != 0 {
+		return z, &flint.Error{Op: "fmpq.Poly.TryDivSeries", Err: flint.ErrDivisionByZero}
+	}
+	return z.DivSeries(x, y, n), nil
+}
+
+// DivMod sets z to the quotient and m to the remainder of x divided
+// by y and returns the pair (z, m).  If y is zero, a division-by-zero
+// run-time panic occurs.
+func (z *Poly) DivMod(x, y, m *Poly) (*Poly, *Poly) {
+	if y.Degree() < 0 {
+		flint.Panic("fmpq.Poly.DivMod", flint.ErrDivisionByZero)
+	}
+	C.fmpq_poly_divrem((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(m), (*C.fmpq_poly_struct)(x), (*C.fmpq_poly_struct)(y))
+	return z, m
+}
+
+// XGCD sets z to the monic greatest common divisor of x and y, and
+// s and t such that z = s*x + t*y, and returns z.
+func (z *Poly) XGCD(s, t, x, y *Poly) *Poly {
+	C.fmpq_poly_xgcd((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(s), (*C.fmpq_poly_struct)(t), (*C.fmpq_poly_struct)(x), (*C.fmpq_poly_struct)(y))
+	return z
+}
+
+// Coeff returns a new Rat equal to the coefficient of x^n in z.
+func (z *Poly) Coeff(n int) *Rat {
+	c := NewRat(0, 1)
+	C.fmpq_poly_get_coeff_fmpq((*C.fmpq)(c), (*C.fmpq_poly_struct)(z), C.slong(n))
+	return c
+}
+
+// SetCoeff sets the coefficient of x^n in z to c and returns z.
+func (z *Poly) SetCoeff(n int, c *Rat) *Poly {
+	C.fmpq_poly_set_coeff_fmpq((*C.fmpq_poly_struct)(z), C.slong(n), (*C.fmpq)(c))
+	return z
+}
+
+// Truncate sets z to x with all terms of degree n or more removed
+// and returns z.
+func (z *Poly) Truncate(x *Poly, n int) *Poly {
+	C.fmpq_poly_set((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x))
+	C.fmpq_poly_truncate((*C.fmpq_poly_struct)(z), C.slong(n))
 	return z
 }
+
+// Val returns the valuation of z, the exponent of its lowest
+// non-zero term.  The valuation of the zero polynomial is -1.
+func (z *Poly) Val() int {
+	p := (*C.fmpq_poly_struct)(z)
+	if p.length == 0 {
+		return -1
+	}
+	cs := unsafe.Slice(p.coeffs, p.length)
+	i := 0
+	for C.fmpz_is_zero(&cs[i]) != 0 {
+		i++
+	}
+	return i
+}
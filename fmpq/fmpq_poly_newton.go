@@ -0,0 +1,172 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpq
+
+import (
+	"fmt"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// A SeriesError reports that a power series function was called
+// with an argument outside its domain.  FLINT would abort the
+// process in that case.
+type SeriesError struct {
+	Func   string // the method that failed, e.g. "LogSeries"
+	Reason string
+}
+
+func (e *SeriesError) Error() string {
+	return fmt.Sprintf("fmpq: %s: %s", e.Func, e.Reason)
+}
+
+// Unwrap returns flint.ErrDomain.
+func (e *SeriesError) Unwrap() error { return flint.ErrDomain }
+
+// A constTerm is a condition on the constant term of the argument
+// of a power series function.
+type constTerm int
+
+const (
+	constZero    constTerm = iota // the constant term must be 0
+	constOne                      // the constant term must be 1
+	constNonzero                  // the constant term must not be 0
+)
+
+// checkSeries returns a *SeriesError unless n >= 1 and the constant
+// term of x satisfies want.
+func checkSeries(fn string, x *Poly, n int64, want constTerm) error {
+	if n < 1 {
+		return &SeriesError{fn, "series length must be positive"}
+	}
+	c := x.Coeff(0)
+	switch want {
+	case constZero:
+		if c.Cmp(NewRat(0, 1)) != 0 {
+			return &SeriesError{fn, "constant term must be 0, got " + c.String()}
+		}
+	case constOne:
+		if c.Cmp(NewRat(1, 1)) != 0 {
+			return &SeriesError{fn, "constant term must be 1, got " + c.String()}
+		}
+	case constNonzero:
+		if c.Cmp(NewRat(0, 1)) == 0 {
+			return &SeriesError{fn, "constant term must not be 0"}
+		}
+	}
+	return nil
+}
+
+// A SeriesFunc sets z to G(w) mod x^n for some fixed power series
+// function G and returns z.  It must not retain z or w.
+type SeriesFunc func(z, w *Poly, n int64) *Poly
+
+// NewtonSeries solves the functional equation F(w) = 0 in Q[[x]] to
+// n terms, sets z to the solution and returns (z, nil).  f evaluates
+// F and df its derivative with respect to w.  The initial value w0
+// must be correct modulo x; only its constant term is used.  Each
+// step w = w - F(w)/F'(w) doubles the number of correct terms.
+//
+// If n < 1, if F(w0) has a non-zero constant term, that is, w0 is
+// not a solution modulo x, or if F'(w0) has a zero constant term,
+// in which case the iteration does not converge, z is unchanged
+// and NewtonSeries returns a *SeriesError.
+func (z *Poly) NewtonSeries(w0 *Poly, f, df SeriesFunc, n int64) (*Poly, error) {
+	if n < 1 {
+		return z, &SeriesError{"NewtonSeries", "series length must be positive"}
+	}
+	w := NewPoly(0).Truncate(w0, 1)
+	u := NewPoly(0)
+	v := NewPoly(0)
+	if c := f(u, w, 1).Coeff(0); c.Cmp(NewRat(0, 1)) != 0 {
+		return z, &SeriesError{"NewtonSeries", "F(w0) must have constant term 0, got " + c.String()}
+	}
+	if err := checkSeries("NewtonSeries", df(v, w, 1), 1, constNonzero); err != nil {
+		return z, err
+	}
+
+	var precs []int64
+	for m := n; m > 1; m = (m + 1) / 2 {
+		precs = append(precs, m)
+	}
+	for i := len(precs) - 1; i >= 0; i-- {
+		m := precs[i]
+		f(u, w, m)
+		df(v, w, m)
+		u.DivSeries(u, v, m)
+		w.Sub(w, u)
+	}
+	return z.Set(w), nil
+}
+
+// newton is NewtonSeries for equations known to be regular.
+func (z *Poly) newton(w0 int64, f, df SeriesFunc, n int64) *Poly {
+	if n < 1 {
+		panic("fmpq: series length must be positive")
+	}
+	if _, err := z.NewtonSeries(NewPoly(w0), f, df, n); err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// LambertWSeries sets z to the first n terms of the Lambert W
+// function, the solution of W*exp(W) = x, and returns z.
+func (z *Poly) LambertWSeries(n int64) *Poly {
+	x := NewPoly(0).SetCoeff64(1, 1)
+	t := NewPoly(0)
+	f := func(r, w *Poly, m int64) *Poly {
+		t.ExpSeries(w, m)
+		r.MulLow(w, t, m)
+		return r.Sub(r, x)
+	}
+	df := func(r, w *Poly, m int64) *Poly {
+		t.ExpSeries(w, m)
+		r.MulLow(w, t, m)
+		return r.Add(r, t)
+	}
+	return z.newton(0, f, df, n)
+}
+
+// TreeSeries sets z to the first n terms of the tree function T,
+// the exponential generating function of rooted labelled trees,
+// which satisfies T = x*exp(T), and returns z.
+func (z *Poly) TreeSeries(n int64) *Poly {
+	x := NewPoly(0).SetCoeff64(1, 1)
+	one := NewPoly(1)
+	t := NewPoly(0)
+	f := func(r, w *Poly, m int64) *Poly {
+		t.ExpSeries(w, m)
+		r.MulLow(x, t, m)
+		return r.Sub(w, r)
+	}
+	df := func(r, w *Poly, m int64) *Poly {
+		t.ExpSeries(w, m)
+		r.MulLow(x, t, m)
+		return r.Sub(one, r)
+	}
+	return z.newton(0, f, df, n)
+}
+
+// CatalanSeries sets z to the first n terms of the ordinary
+// generating function of the Catalan numbers, which satisfies
+// C = 1 + x*C^2, and returns z.
+func (z *Poly) CatalanSeries(n int64) *Poly {
+	one := NewPoly(1)
+	x := NewPoly(0).SetCoeff64(1, 1)
+	x2 := NewPoly(0).SetCoeff64(1, 2)
+	t := NewPoly(0)
+	f := func(r, w *Poly, m int64) *Poly {
+		t.MulLow(w, w, m)
+		r.MulLow(x, t, m)
+		r.Sub(r, w)
+		return r.Add(r, one)
+	}
+	df := func(r, w *Poly, m int64) *Poly {
+		r.MulLow(x2, w, m)
+		return r.Sub(r, one)
+	}
+	return z.newton(1, f, df, n)
+}
@@ -0,0 +1,91 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// sqrtEq returns F(w) = w^2 - a and F'(w) = 2*w.
+func sqrtEq(a *Poly) (f, df SeriesFunc) {
+	f = func(z, w *Poly, n int64) *Poly {
+		z.MulLow(w, w, n)
+		return z.Sub(z, a)
+	}
+	df = func(z, w *Poly, n int64) *Poly {
+		return z.ScalarMul64(w, 2)
+	}
+	return f, df
+}
+
+func TestNewtonSeries(t *testing.T) {
+	f, df := sqrtEq(poly([2]int64{1, 1}, [2]int64{1, 1}))
+	for _, tc := range []struct {
+		w0   *Poly
+		n    int64
+		want string
+	}{
+		{NewPoly(1), 4, "1/16*x^3 - 1/8*x^2 + 1/2*x + 1"},
+		{NewPoly(-1), 3, "1/8*x^2 - 1/2*x - 1"},
+		// Only the constant term of w0 is used.
+		{poly([2]int64{1, 1}, [2]int64{5, 1}), 2, "1/2*x + 1"},
+	} {
+		z, err := NewPoly(0).NewtonSeries(tc.w0, f, df, tc.n)
+		if err != nil {
+			t.Errorf("NewtonSeries(%v, %d): %v", tc.w0, tc.n, err)
+		} else if s := z.String(); s != tc.want {
+			t.Errorf("NewtonSeries(%v, %d) = %s, want %s", tc.w0, tc.n, s, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		a, w0 *Poly
+		n     int64
+	}{
+		// sqrt(x) is not a power series: F'(0) = 0.
+		{poly([2]int64{0, 1}, [2]int64{1, 1}), NewPoly(0), 0},
+		{poly([2]int64{0, 1}, [2]int64{1, 1}), NewPoly(0), 4},
+		// F(3) = 8 != 0: w0 is not a root modulo x.
+		{poly([2]int64{1, 1}, [2]int64{1, 1}), poly([2]int64{3, 1}, [2]int64{1, 1}), 1},
+		{poly([2]int64{1, 1}, [2]int64{1, 1}), poly([2]int64{3, 1}, [2]int64{1, 1}), 4},
+	} {
+		f, df := sqrtEq(tc.a)
+		z := NewPoly(7)
+		_, err := z.NewtonSeries(tc.w0, f, df, tc.n)
+		var se *SeriesError
+		if !errors.As(err, &se) || se.Func != "NewtonSeries" || !errors.Is(err, flint.ErrDomain) {
+			t.Errorf("NewtonSeries(%v, %d) for a = %v: err = %v, want a *SeriesError", tc.w0, tc.n, tc.a, err)
+		}
+		if z.String() != "7" {
+			t.Errorf("NewtonSeries(%v, %d) for a = %v changed its receiver to %v", tc.w0, tc.n, tc.a, z)
+		}
+	}
+}
+
+func TestNewtonSeriesFunctions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		z    *Poly
+		want string
+	}{
+		{"LambertWSeries", NewPoly(0).LambertWSeries(6), "125/24*x^5 - 8/3*x^4 + 3/2*x^3 - x^2 + x"},
+		{"TreeSeries", NewPoly(0).TreeSeries(5), "8/3*x^4 + 3/2*x^3 + x^2 + x"},
+		{"CatalanSeries", NewPoly(0).CatalanSeries(6), "42*x^5 + 14*x^4 + 5*x^3 + 2*x^2 + x + 1"},
+		{"CatalanSeries(1)", NewPoly(0).CatalanSeries(1), "1"},
+	} {
+		if s := tc.z.String(); s != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, s, tc.want)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("TreeSeries(0) did not panic")
+		}
+	}()
+	NewPoly(0).TreeSeries(0)
+}
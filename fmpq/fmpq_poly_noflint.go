@@ -0,0 +1,381 @@
+// Copyright 2010 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpq
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// A Poly is a polynomial with rational coefficients.  The zero value
+// is the zero polynomial.
+//
+// This is the pure Go backend, which only provides the basic
+// operations; see Rat.
+type Poly struct {
+	cs []*big.Rat // cs[i] is the coefficient of x^i; no trailing zeros
+}
+
+func NewPoly(x int64) *Poly { return new(Poly).SetInt64(x) }
+
+// set sets z to the coefficients cs, which it takes ownership of,
+// and returns z.
+func (z *Poly) set(cs []*big.Rat) *Poly {
+	for len(cs) > 0 && cs[len(cs)-1].Sign() == 0 {
+		cs = cs[:len(cs)-1]
+	}
+	z.cs = cs
+	return z
+}
+
+// coeffs returns a copy of the first n coefficients of z, padded
+// with zeros.
+func (z *Poly) coeffs(n int) []*big.Rat {
+	cs := make([]*big.Rat, n)
+	for i := range cs {
+		cs[i] = new(big.Rat)
+		if i < len(z.cs) {
+			cs[i].Set(z.cs[i])
+		}
+	}
+	return cs
+}
+
+// Degree returns the degree of z.
+func (z *Poly) Degree() int {
+	return len(z.cs) - 1
+}
+
+// Set sets z = x and returns z.
+func (z *Poly) Set(x *Poly) *Poly {
+	return z.set(x.coeffs(len(x.cs)))
+}
+
+// SetInt64 sets z = x and returns z.
+func (z *Poly) SetInt64(x int64) *Poly {
+	return z.set([]*big.Rat{new(big.Rat).SetInt64(x)})
+}
+
+// SetCoeff64 sets the coefficient of x^n in z to c and returns z.
+func (z *Poly) SetCoeff64(n, c int64) *Poly {
+	return z.SetCoeff(int(n), NewRat(c, 1))
+}
+
+// StringRaw returns a raw string representation of z.
+func (z *Poly) StringRaw() string {
+	if len(z.cs) == 0 {
+		return "0"
+	}
+	s := []string{strconv.Itoa(len(z.cs)) + " "}
+	for _, c := range z.cs {
+		s = append(s, c.RatString())
+	}
+	return strings.Join(s, " ")
+}
+
+// String returns a string representation of z as a
+// polynomial in the variable 'x'.
+func (z *Poly) String() string {
+	n := len(z.cs)
+	switch n {
+	case 0:
+		return "0"
+	case 1:
+		return z.cs[0].RatString()
+	}
+
+	// The leading coefficient keeps its sign, the others are
+	// printed as " + |c|" or " - |c|".
+	var b strings.Builder
+	mono := func(i int) string {
+		switch i {
+		case 0:
+			return ""
+		case 1:
+			return "x"
+		}
+		return "x^" + strconv.Itoa(i)
+	}
+	one := big.NewRat(1, 1)
+	c := z.cs[n-1]
+	switch {
+	case c.Cmp(one) == 0:
+	case new(big.Rat).Neg(c).Cmp(one) == 0:
+		b.WriteString("-")
+	default:
+		b.WriteString(c.RatString() + "*")
+	}
+	b.WriteString(mono(n - 1))
+	for i := n - 2; i >= 0; i-- {
+		c := z.cs[i]
+		if c.Sign() == 0 {
+			continue
+		}
+		if c.Sign() > 0 {
+			b.WriteString(" + ")
+		} else {
+			b.WriteString(" - ")
+		}
+		a := new(big.Rat).Abs(c)
+		switch {
+		case i == 0:
+			b.WriteString(a.RatString())
+		case a.Cmp(one) == 0:
+			b.WriteString(mono(i))
+		default:
+			b.WriteString(a.RatString() + "*" + mono(i))
+		}
+	}
+	return b.String()
+}
+
+// Add sets z = x + y and returns z.
+func (z *Poly) Add(x, y *Poly) *Poly {
+	cs := x.coeffs(max(len(x.cs), len(y.cs)))
+	for i, c := range y.cs {
+		cs[i].Add(cs[i], c)
+	}
+	return z.set(cs)
+}
+
+// Sub sets z = x - y and returns z.
+func (z *Poly) Sub(x, y *Poly) *Poly {
+	cs := x.coeffs(max(len(x.cs), len(y.cs)))
+	for i, c := range y.cs {
+		cs[i].Sub(cs[i], c)
+	}
+	return z.set(cs)
+}
+
+// Mul sets z = x * y and returns z.
+func (z *Poly) Mul(x, y *Poly) *Poly {
+	return z.MulLow(x, y, int64(len(x.cs)+len(y.cs)))
+}
+
+// AddMul adds x * y to z and returns the new z.
+func (z *Poly) AddMul(x, y *Poly) *Poly {
+	return z.Add(z, new(Poly).Mul(x, y))
+}
+
+// SubMul subtracts x * y from z and returns the new z.
+func (z *Poly) SubMul(x, y *Poly) *Poly {
+	return z.Sub(z, new(Poly).Mul(x, y))
+}
+
+// Exp sets z = x^n and returns z.
+func (z *Poly) Exp(x *Poly, n uint64) *Poly {
+	r, b := NewPoly(1), new(Poly).Set(x)
+	for ; n > 0; n >>= 1 {
+		if n&1 != 0 {
+			r.Mul(r, b)
+		}
+		if n > 1 {
+			b.Mul(b, b)
+		}
+	}
+	return z.set(r.cs)
+}
+
+// ScalarMul64 sets z = c*x and returns z.
+func (z *Poly) ScalarMul64(x *Poly, c int64) *Poly {
+	cs := x.coeffs(len(x.cs))
+	t := new(big.Rat).SetInt64(c)
+	for _, a := range cs {
+		a.Mul(a, t)
+	}
+	return z.set(cs)
+}
+
+// Neg sets z = -x and returns z.
+func (z *Poly) Neg(x *Poly) *Poly {
+	cs := x.coeffs(len(x.cs))
+	for _, a := range cs {
+		a.Neg(a)
+	}
+	return z.set(cs)
+}
+
+/*
+ * functions without a clear receiver
+ */
+
+// ExpSeries sets z to the first n terms of exp(x) and returns z.
+// If the constant term of x is not zero, ExpSeries panics with
+// flint.ErrDomain.
+func (z *Poly) ExpSeries(x *Poly, n int64) *Poly {
+	if len(x.cs) > 0 && x.cs[0].Sign() != 0 {
+		flint.Panic("fmpq.Poly.ExpSeries", flint.ErrDomain)
+	}
+	if n <= 0 {
+		return z.set(nil)
+	}
+	// y = exp(x) satisfies y' = x'*y, so
+	// k*y_k = sum_{j=1}^k j*x_j*y_(k-j).
+	xs := x.coeffs(int(n))
+	ys := make([]*big.Rat, n)
+	ys[0] = big.NewRat(1, 1)
+	t := new(big.Rat)
+	for k := 1; k < int(n); k++ {
+		ys[k] = new(big.Rat)
+		for j := 1; j <= k; j++ {
+			if xs[j].Sign() == 0 {
+				continue
+			}
+			t.SetInt64(int64(j))
+			ys[k].Add(ys[k], t.Mul(t, xs[j]).Mul(t, ys[k-j]))
+		}
+		ys[k].Quo(ys[k], t.SetInt64(int64(k)))
+	}
+	return z.set(ys)
+}
+
+func (z *Poly) MulLow(x, y *Poly, n int64) *Poly {
+	m := min(int64(len(x.cs)+len(y.cs)-1), n)
+	if len(x.cs) == 0 || len(y.cs) == 0 || m <= 0 {
+		return z.set(nil)
+	}
+	cs := make([]*big.Rat, m)
+	for i := range cs {
+		cs[i] = new(big.Rat)
+	}
+	t := new(big.Rat)
+	for i, a := range x.cs {
+		for j, b := range y.cs {
+			if int64(i+j) >= m {
+				break
+			}
+			cs[i+j].Add(cs[i+j], t.Mul(a, b))
+		}
+	}
+	return z.set(cs)
+}
+
+// DivSeries sets z to the first n terms of the power series x/y
+// and returns z.  If the constant term of y is zero, a
+// division-by-zero run-time panic occurs.
+func (z *Poly) DivSeries(x, y *Poly, n int64) *Poly {
+	if len(y.cs) == 0 || y.cs[0].Sign() == 0 {
+		flint.Panic("fmpq.Poly.DivSeries", flint.ErrDivisionByZero)
+	}
+	if n <= 0 {
+		return z.set(nil)
+	}
+	// q_k = (x_k - sum_{j=1}^k y_j*q_(k-j)) / y_0
+	qs := x.coeffs(int(n))
+	t := new(big.Rat)
+	for k := range qs {
+		for j := 1; j <= k && j < len(y.cs); j++ {
+			qs[k].Sub(qs[k], t.Mul(y.cs[j], qs[k-j]))
+		}
+		qs[k].Quo(qs[k], y.cs[0])
+	}
+	return z.set(qs)
+}
+
+// TryDivSeries is like DivSeries, but if the constant term of y
+// is zero it leaves z unchanged and returns an *flint.Error wrapping
+// flint.ErrDivisionByZero instead of panicking.
+func (z *Poly) TryDivSeries(x, y *Poly, n int64) (*Poly, error) {
+	if len(y.cs) == 0 || y.cs[0].Sign() == 0 {
+		return z, &flint.Error{Op: "fmpq.Poly.TryDivSeries", Err: flint.ErrDivisionByZero}
+	}
+	return z.DivSeries(x, y, n), nil
+}
+
+// DivMod sets z to the quotient and m to the remainder of x divided
+// by y and returns the pair (z, m).  If y is zero, a division-by-zero
+// run-time panic occurs.
+func (z *Poly) DivMod(x, y, m *Poly) (*Poly, *Poly) {
+	if y.Degree() < 0 {
+		flint.Panic("fmpq.Poly.DivMod", flint.ErrDivisionByZero)
+	}
+	rs := x.coeffs(len(x.cs))
+	dy := y.Degree()
+	if len(rs) <= dy {
+		m.set(rs)
+		return z.set(nil), m
+	}
+	qs := make([]*big.Rat, len(rs)-dy)
+	lc := y.cs[dy]
+	t := new(big.Rat)
+	for k := len(qs) - 1; k >= 0; k-- {
+		q := new(big.Rat).Quo(rs[k+dy], lc)
+		qs[k] = q
+		for j, c := range y.cs {
+			rs[k+j].Sub(rs[k+j], t.Mul(q, c))
+		}
+	}
+	z.set(qs)
+	m.set(rs[:dy])
+	return z, m
+}
+
+// XGCD sets z to the monic greatest common divisor of x and y, and
+// s and t such that z = s*x + t*y, and returns z.
+func (z *Poly) XGCD(s, t, x, y *Poly) *Poly {
+	// Extended Euclid on (x, y), keeping s_i*x + t_i*y = r_i.
+	r0, r1 := new(Poly).Set(x), new(Poly).Set(y)
+	s0, s1 := NewPoly(1), new(Poly)
+	t0, t1 := new(Poly), NewPoly(1)
+	q, r, u := new(Poly), new(Poly), new(Poly)
+	for r1.Degree() >= 0 {
+		q.DivMod(r0, r1, r)
+		r0, r1, r = r1, r, r0
+		s0, s1 = s1, s0.Sub(s0, u.Mul(q, s1))
+		t0, t1 = t1, t0.Sub(t0, u.Mul(q, t1))
+	}
+	if r0.Degree() < 0 {
+		s.set(nil)
+		t.set(nil)
+		return z.set(nil)
+	}
+	lc := new(big.Rat).Inv(r0.cs[r0.Degree()])
+	for _, p := range []*Poly{r0, s0, t0} {
+		for _, c := range p.cs {
+			c.Mul(c, lc)
+		}
+	}
+	s.set(s0.cs)
+	t.set(t0.cs)
+	return z.set(r0.cs)
+}
+
+// Coeff returns a new Rat equal to the coefficient of x^n in z.
+func (z *Poly) Coeff(n int) *Rat {
+	c := NewRat(0, 1)
+	if n >= 0 && n < len(z.cs) {
+		c.big().Set(z.cs[n])
+	}
+	return c
+}
+
+// SetCoeff sets the coefficient of x^n in z to c and returns z.
+func (z *Poly) SetCoeff(n int, c *Rat) *Poly {
+	cs := z.coeffs(max(len(z.cs), n+1))
+	cs[n].Set(c.big())
+	return z.set(cs)
+}
+
+// Truncate sets z to x with all terms of degree n or more removed
+// and returns z.
+func (z *Poly) Truncate(x *Poly, n int) *Poly {
+	return z.set(x.coeffs(max(min(len(x.cs), n), 0)))
+}
+
+// Val returns the valuation of z, the exponent of its lowest
+// non-zero term.  The valuation of the zero polynomial is -1.
+func (z *Poly) Val() int {
+	for i, c := range z.cs {
+		if c.Sign() != 0 {
+			return i
+		}
+	}
+	return -1
+}
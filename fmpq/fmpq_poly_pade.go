@@ -0,0 +1,231 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpq.h>
+// #include <flint/fmpq_poly.h>
+import "C"
+
+// euclid runs the extended Euclidean algorithm on x^n and z mod x^n.
+// It returns the first remainder r, together with its cofactor t
+// satisfying z*t = r mod x^n, for which done(r, t) holds.
+func (z *Poly) euclid(n int, done func(r, t *Poly) bool) (r, t *Poly) {
+	r0 := NewPoly(0).SetCoeff64(int64(n), 1)
+	r1 := NewPoly(0).Truncate(z, n)
+	t0 := NewPoly(0)
+	t1 := NewPoly(1)
+	for !done(r1, t1) && r1.Degree() >= 0 {
+		q := NewPoly(0)
+		r := NewPoly(0)
+		q.DivMod(r0, r1, r)
+		r0, r1 = r1, r
+		q.Mul(q, t1)
+		t0, t1 = t1, q.Sub(t0, q)
+	}
+	return r1, t1
+}
+
+// normalize divides p and q by the constant term of q.  It returns
+// false if that constant term is zero.
+func normalize(p, q *Poly) (*Poly, *Poly, bool) {
+	c := q.Coeff(0)
+	if C.fmpq_is_zero((*C.fmpq)(c)) != 0 {
+		return p, q, false
+	}
+	C.fmpq_poly_scalar_div_fmpq((*C.fmpq_poly_struct)(p), (*C.fmpq_poly_struct)(p), (*C.fmpq)(c))
+	C.fmpq_poly_scalar_div_fmpq((*C.fmpq_poly_struct)(q), (*C.fmpq_poly_struct)(q), (*C.fmpq)(c))
+	return p, q, true
+}
+
+// Pade returns the Padé approximant [m/n] of the series z, the
+// rational function p/q with deg p <= m, deg q <= n and q(0) = 1
+// such that z*q - p = O(x^(m+n+1)).  Only the first m+n+1 terms
+// of z are used.  If the approximant does not exist, Pade returns
+// false.
+func (z *Poly) Pade(m, n int) (p, q *Poly, ok bool) {
+	if m < 0 || n < 0 {
+		panic("fmpq: negative Padé degree")
+	}
+	p, q = z.euclid(m+n+1, func(r, t *Poly) bool { return r.Degree() <= m })
+	return normalize(p, q)
+}
+
+// GuessRational looks for a rational function p/q with q(0) = 1
+// agreeing with the first n terms of z, where the n terms
+// determine p and q with at least half of them to spare.  This
+// detects rational generating functions from enough of their
+// expansion.  If no such function exists, GuessRational returns
+// false.
+func (z *Poly) GuessRational(n int) (p, q *Poly, ok bool) {
+	p, q = z.euclid(n, func(r, t *Poly) bool {
+		return 2*(r.Degree()+t.Degree()+1) <= n
+	})
+	if 2*(p.Degree()+q.Degree()+1) > n {
+		return p, q, false
+	}
+	return normalize(p, q)
+}
+
+// HermitePade returns polynomials p_i, not all zero, with deg p_i
+// <= ds[i] such that sum p_i*fs[i] = O(x^s) for s = sum (ds[i]+1)
+// - 1.  For fs = {f, -1} this is the Padé approximant of f.
+func HermitePade(fs []*Poly, ds []int) []*Poly {
+	if len(fs) != len(ds) {
+		panic("fmpq: HermitePade length mismatch")
+	}
+	cols := 0
+	for _, d := range ds {
+		if d < 0 {
+			panic("fmpq: negative Hermite-Padé degree")
+		}
+		cols += d + 1
+	}
+	rows := cols - 1
+
+	a := NewMat(rows, cols)
+	j := 0
+	for i, f := range fs {
+		for l := 0; l <= ds[i]; l++ {
+			for k := l; k < rows; k++ {
+				a.SetEntry(k, j, f.Coeff(k-l))
+			}
+			j++
+		}
+	}
+
+	v := a.Nullspace()
+	ps := make([]*Poly, len(fs))
+	j = 0
+	for i := range fs {
+		ps[i] = NewPoly(0)
+		for l := 0; l <= ds[i]; l++ {
+			ps[i].SetCoeff(l, v.Entry(j, 0))
+			j++
+		}
+	}
+	return ps
+}
+
+// StieltjesCF returns the coefficients a_0, ..., a_(k-1) of the
+// Stieltjes continued fraction
+//
+//	a_0/(1 + a_1*x/(1 + a_2*x/(1 + ...)))
+//
+// of the series z, computed from its first n terms.  Normally k =
+// n; the expansion stops early if it terminates within the known
+// precision.  If the expansion does not exist because some a_i
+// would be zero with the tail not yet zero, StieltjesCF returns
+// false.
+func (z *Poly) StieltjesCF(n int) ([]*Rat, bool) {
+	if n < 1 {
+		return nil, true
+	}
+	a := z.Coeff(0)
+	if C.fmpq_is_zero((*C.fmpq)(a)) != 0 {
+		return nil, false
+	}
+	as := []*Rat{a}
+
+	g := NewPoly(0).Truncate(z, n)
+	C.fmpq_poly_scalar_div_fmpq((*C.fmpq_poly_struct)(g), (*C.fmpq_poly_struct)(g), (*C.fmpq)(a))
+	h := NewPoly(0)
+	for p := n; p > 1; p-- {
+		// 1/g = 1 + a*x*g' with g'(0) = 1.
+		C.fmpq_poly_inv_series((*C.fmpq_poly_struct)(h), (*C.fmpq_poly_struct)(g), C.slong(p))
+		h.Sub(h, NewPoly(1)).shift(h, 1)
+		if h.Degree() < 0 {
+			break
+		}
+		a := h.Coeff(0)
+		if C.fmpq_is_zero((*C.fmpq)(a)) != 0 {
+			return as, false
+		}
+		as = append(as, a)
+		C.fmpq_poly_scalar_div_fmpq((*C.fmpq_poly_struct)(g), (*C.fmpq_poly_struct)(h), (*C.fmpq)(a))
+	}
+	return as, true
+}
+
+// StieltjesConvergent returns the rational function p/q, with
+// q(0) = 1, represented by the Stieltjes continued fraction with
+// coefficients as.
+func StieltjesConvergent(as []*Rat) (p, q *Poly) {
+	if len(as) == 0 {
+		return NewPoly(0), NewPoly(1)
+	}
+	// Evaluate from the innermost level outwards; the tail after
+	// the last coefficient is 1.
+	p, q = NewPoly(1), NewPoly(1)
+	t := NewPoly(0)
+	for i := len(as) - 1; i > 0; i-- {
+		t.SetCoeff(1, as[i])
+		t.Mul(t, q)
+		q.Set(p)
+		p.Add(p, t)
+		t.SetInt64(0)
+	}
+	C.fmpq_poly_scalar_mul_fmpq((*C.fmpq_poly_struct)(q), (*C.fmpq_poly_struct)(q), (*C.fmpq)(as[0]))
+	p, q, _ = normalize(q, p)
+	return p, q
+}
+
+// A CFTerm is one level of a Thiele continued fraction
+//
+//	b_0 + x^e_0/(b_1 + x^e_1/(b_2 + ...)).
+type CFTerm struct {
+	B   *Rat
+	Exp int
+}
+
+// ThieleCF returns the Thiele continued fraction of the series z,
+// computed from its first n terms.  Unlike the Stieltjes fraction
+// it always exists: every b_i other than b_0 is non-zero, and the
+// exponents e_i are positive except for that of the last term,
+// which is 0.
+func (z *Poly) ThieleCF(n int) []CFTerm {
+	var ts []CFTerm
+	g := NewPoly(0).Truncate(z, n)
+	h := NewPoly(0)
+	for p := n; p > 0; {
+		b := g.Coeff(0)
+		h.Sub(g, NewPoly(0).SetCoeff(0, b))
+		e := h.Val()
+		if e < 0 {
+			ts = append(ts, CFTerm{b, 0})
+			break
+		}
+		ts = append(ts, CFTerm{b, e})
+		p -= e
+		h.shift(h, e)
+		C.fmpq_poly_inv_series((*C.fmpq_poly_struct)(g), (*C.fmpq_poly_struct)(h), C.slong(p))
+	}
+	return ts
+}
+
+// ThieleConvergent returns the rational function p/q represented
+// by the Thiele continued fraction ts.  If b_1 is non-zero, q(0) =
+// 1.
+func ThieleConvergent(ts []CFTerm) (p, q *Poly) {
+	if len(ts) == 0 {
+		return NewPoly(0), NewPoly(1)
+	}
+	p = NewPoly(0).SetCoeff(0, ts[len(ts)-1].B)
+	q = NewPoly(1)
+	t := NewPoly(0)
+	for i := len(ts) - 2; i >= 0; i-- {
+		// b + x^e/(p/q) = (b*p + x^e*q)/p
+		t.Set(p)
+		C.fmpq_poly_scalar_mul_fmpq((*C.fmpq_poly_struct)(p), (*C.fmpq_poly_struct)(p), (*C.fmpq)(ts[i].B))
+		C.fmpq_poly_shift_left((*C.fmpq_poly_struct)(q), (*C.fmpq_poly_struct)(q), C.slong(ts[i].Exp))
+		p.Add(p, q)
+		q.Set(t)
+	}
+	normalize(p, q)
+	return p, q
+}
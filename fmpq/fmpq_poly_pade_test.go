@@ -0,0 +1,90 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpq
+
+import "testing"
+
+// approx holds the result of Pade or GuessRational.
+type approx struct {
+	p, q *Poly
+	ok   bool
+}
+
+func newApprox(p, q *Poly, ok bool) approx { return approx{p, q, ok} }
+
+func TestPade(t *testing.T) {
+	exp := NewPoly(0).ExpSeries(intPoly(0, 1), 8)
+	fib := intPoly(1, 1, 2, 3, 5, 8, 13, 21)
+	for _, tc := range []struct {
+		name         string
+		got          approx
+		wantP, wantQ string
+	}{
+		{"Pade(exp, 1, 1)", newApprox(exp.Pade(1, 1)), "1/2*x + 1", "-1/2*x + 1"},
+		{"Pade(exp, 2, 0)", newApprox(exp.Pade(2, 0)), "1/2*x^2 + x + 1", "1"},
+		{"Pade(fib, 0, 2)", newApprox(fib.Pade(0, 2)), "1", "-x^2 - x + 1"},
+		{"GuessRational(fib, 8)", newApprox(fib.GuessRational(8)), "1", "-x^2 - x + 1"},
+	} {
+		g := tc.got
+		if !g.ok || g.p.String() != tc.wantP || g.q.String() != tc.wantQ {
+			t.Errorf("%s = %v, %v, %v, want %s, %s", tc.name, g.p, g.q, g.ok, tc.wantP, tc.wantQ)
+		}
+	}
+
+	if p, q, ok := exp.GuessRational(6); ok {
+		t.Errorf("GuessRational(exp, 6) = %v, %v, want failure", p, q)
+	}
+	// [0/1] of x does not exist: x*(1 + d*x) - c is never O(x^2).
+	if p, q, ok := intPoly(0, 1).Pade(0, 1); ok {
+		t.Errorf("Pade(x, 0, 1) = %v, %v, want failure", p, q)
+	}
+}
+
+func TestHermitePade(t *testing.T) {
+	exp := NewPoly(0).ExpSeries(intPoly(0, 1), 8)
+	ps := HermitePade([]*Poly{exp, NewPoly(-1)}, []int{1, 1})
+	if len(ps) != 2 || ps[0].Degree() > 1 || ps[1].Degree() > 1 {
+		t.Fatalf("HermitePade = %v", ps)
+	}
+	// p_0*exp - p_1 = O(x^3), and p_1/p_0 is the [1/1] approximant.
+	s := NewPoly(0).MulLow(ps[0], exp, 3)
+	if s.Sub(s, ps[1]).Degree() >= 0 || ps[0].Degree() < 0 {
+		t.Errorf("HermitePade = %v gives p_0*exp - p_1 = %v + O(x^3)", ps, s)
+	}
+	p, q, _ := normalize(ps[1], ps[0])
+	if p.String() != "1/2*x + 1" || q.String() != "-1/2*x + 1" {
+		t.Errorf("HermitePade gives %v / %v, want (1/2*x + 1) / (-1/2*x + 1)", p, q)
+	}
+}
+
+func TestContinuedFractions(t *testing.T) {
+	geom := intPoly(1, 1, 1, 1, 1)
+	as, ok := geom.StieltjesCF(5)
+	if !ok || len(as) != 2 || as[0].String() != "1/1" || as[1].String() != "-1/1" {
+		t.Errorf("StieltjesCF(1/(1 - x)) = %v, %v, want [1/1 -1/1], true", as, ok)
+	}
+	if p, q := StieltjesConvergent(as); p.String() != "1" || q.String() != "-x + 1" {
+		t.Errorf("StieltjesConvergent(%v) = %v, %v, want 1, -x + 1", as, p, q)
+	}
+	if _, ok := intPoly(0, 1).StieltjesCF(3); ok {
+		t.Errorf("StieltjesCF(x) succeeded")
+	}
+
+	ts := geom.ThieleCF(4)
+	want := []CFTerm{{NewRat(1, 1), 1}, {NewRat(1, 1), 1}, {NewRat(-1, 1), 0}}
+	if len(ts) != len(want) {
+		t.Fatalf("ThieleCF(1/(1 - x)) = %v, want %v", ts, want)
+	}
+	for i := range ts {
+		if ts[i].B.Cmp(want[i].B) != 0 || ts[i].Exp != want[i].Exp {
+			t.Errorf("ThieleCF(1/(1 - x)) term %d = %v, want %v", i, ts[i], want[i])
+		}
+	}
+	if p, q := ThieleConvergent(ts); p.String() != "1" || q.String() != "-x + 1" {
+		t.Errorf("ThieleConvergent = %v, %v, want 1, -x + 1", p, q)
+	}
+}
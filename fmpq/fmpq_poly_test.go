@@ -1 +1,143 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
 package fmpq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// poly returns the polynomial with coefficients cs[i][0]/cs[i][1], lowest
+// degree first.
+func poly(cs ...[2]int64) *Poly {
+	z := NewPoly(0)
+	for i, c := range cs {
+		z.SetCoeff(i, NewRat(c[0], c[1]))
+	}
+	return z
+}
+
+func TestPolyString(t *testing.T) {
+	for _, tc := range []struct {
+		p         *Poly
+		raw, want string
+	}{
+		{NewPoly(0), "0", "0"},
+		{NewPoly(-3), "1  -3", "-3"},
+		{poly([2]int64{1, 2}), "1  1/2", "1/2"},
+		{poly([2]int64{0, 1}, [2]int64{1, 1}), "2  0 1", "x"},
+		{poly([2]int64{2, 1}, [2]int64{-1, 1}), "2  2 -1", "-x + 2"},
+		{poly([2]int64{-1, 3}, [2]int64{3, 2}), "2  -1/3 3/2", "3/2*x - 1/3"},
+		{poly([2]int64{1, 1}, [2]int64{0, 1}, [2]int64{1, 1}), "3  1 0 1", "x^2 + 1"},
+		{poly([2]int64{0, 1}, [2]int64{-1, 1}, [2]int64{0, 1}, [2]int64{-2, 5}), "4  0 -1 0 -2/5", "-2/5*x^3 - x"},
+		{poly([2]int64{-7, 1}, [2]int64{2, 1}, [2]int64{-1, 1}, [2]int64{1, 4}), "4  -7 2 -1 1/4", "1/4*x^3 - x^2 + 2*x - 7"},
+	} {
+		if s := tc.p.StringRaw(); s != tc.raw {
+			t.Errorf("StringRaw = %q, want %q", s, tc.raw)
+		}
+		if s := tc.p.String(); s != tc.want {
+			t.Errorf("String = %q, want %q", s, tc.want)
+		}
+	}
+}
+
+func TestPolyArith(t *testing.T) {
+	x := poly([2]int64{1, 1}, [2]int64{1, 2})                  // x/2 + 1
+	y := poly([2]int64{-1, 1}, [2]int64{0, 1}, [2]int64{1, 1}) // x^2 - 1
+	for _, tc := range []struct {
+		name string
+		got  *Poly
+		want string
+	}{
+		{"Add", NewPoly(0).Add(x, y), "x^2 + 1/2*x"},
+		{"Sub", NewPoly(0).Sub(x, y), "-x^2 + 1/2*x + 2"},
+		{"Mul", NewPoly(0).Mul(x, y), "1/2*x^3 + x^2 - 1/2*x - 1"},
+		{"AddMul", NewPoly(1).AddMul(x, y), "1/2*x^3 + x^2 - 1/2*x"},
+		{"SubMul", NewPoly(1).SubMul(x, y), "-1/2*x^3 - x^2 + 1/2*x + 2"},
+		{"Exp", NewPoly(0).Exp(x, 3), "1/8*x^3 + 3/4*x^2 + 3/2*x + 1"},
+		{"Exp0", NewPoly(0).Exp(NewPoly(0), 0), "1"},
+		{"ScalarMul64", NewPoly(0).ScalarMul64(x, -4), "-2*x - 4"},
+		{"Neg", NewPoly(0).Neg(y), "-x^2 + 1"},
+		{"MulLow", NewPoly(0).MulLow(x, y, 2), "-1/2*x - 1"},
+		{"Truncate", NewPoly(0).Truncate(y, 1), "-1"},
+		{"SetCoeff64", NewPoly(0).Set(y).SetCoeff64(2, 0), "-1"},
+	} {
+		if s := tc.got.String(); s != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, s, tc.want)
+		}
+	}
+	if y.Degree() != 2 || NewPoly(0).Degree() != -1 {
+		t.Errorf("Degree gives wrong results")
+	}
+	if c := y.Coeff(2); c.String() != "1/1" {
+		t.Errorf("Coeff(2) = %v, want 1/1", c)
+	}
+	if c := y.Coeff(5); c.String() != "0/1" {
+		t.Errorf("Coeff(5) = %v, want 0/1", c)
+	}
+	if v := NewPoly(0).Mul(y, poly([2]int64{0, 1}, [2]int64{0, 1}, [2]int64{1, 1})).Val(); v != 2 {
+		t.Errorf("Val = %d, want 2", v)
+	}
+	if v := NewPoly(0).Val(); v != -1 {
+		t.Errorf("Val(0) = %d, want -1", v)
+	}
+}
+
+func TestPolyDivision(t *testing.T) {
+	x := poly([2]int64{1, 1}, [2]int64{1, 2})
+	y := poly([2]int64{-1, 1}, [2]int64{0, 1}, [2]int64{1, 1})
+	q, r := NewPoly(0).DivMod(y, x, NewPoly(0))
+	if q.String() != "2*x - 4" || r.String() != "3" {
+		t.Errorf("DivMod = %v, %v, want 2*x - 4, 3", q, r)
+	}
+
+	// gcd(x^2 - 1, 2*x + 2) = x + 1
+	a := poly([2]int64{2, 1}, [2]int64{2, 1})
+	s, u := NewPoly(0), NewPoly(0)
+	g := NewPoly(0).XGCD(s, u, y, a)
+	if g.String() != "x + 1" {
+		t.Errorf("XGCD = %v, want x + 1", g)
+	}
+	c := NewPoly(0).Mul(s, y)
+	c.AddMul(u, a)
+	if c.String() != g.String() {
+		t.Errorf("s*x + t*y = %v, want %v", c, g)
+	}
+
+	if err := flint.Try(func() { NewPoly(0).DivMod(y, NewPoly(0), NewPoly(0)) }); !errors.Is(err, flint.ErrDivisionByZero) {
+		t.Errorf("DivMod by zero: err = %v, want ErrDivisionByZero", err)
+	}
+}
+
+func TestPolySeries(t *testing.T) {
+	x := poly([2]int64{0, 1}, [2]int64{1, 1})
+	if e := NewPoly(0).ExpSeries(x, 5); e.String() != "1/24*x^4 + 1/6*x^3 + 1/2*x^2 + x + 1" {
+		t.Errorf("ExpSeries(x) = %v", e)
+	}
+	// 1/(1 - x) = 1 + x + x^2 + ...
+	d := poly([2]int64{1, 1}, [2]int64{-1, 1})
+	if q := NewPoly(0).DivSeries(NewPoly(1), d, 4); q.String() != "x^3 + x^2 + x + 1" {
+		t.Errorf("DivSeries(1, 1 - x) = %v", q)
+	}
+	if w := NewPoly(0).LambertWSeries(5); w.String() != "-8/3*x^4 + 3/2*x^3 - x^2 + x" {
+		t.Errorf("LambertWSeries = %v", w)
+	}
+
+	if err := flint.Try(func() { NewPoly(0).ExpSeries(NewPoly(1), 3) }); !errors.Is(err, flint.ErrDomain) {
+		t.Errorf("ExpSeries(1): err = %v, want ErrDomain", err)
+	}
+	if err := flint.Try(func() { NewPoly(0).DivSeries(NewPoly(1), x, 3) }); !errors.Is(err, flint.ErrDivisionByZero) {
+		t.Errorf("DivSeries by x: err = %v, want ErrDivisionByZero", err)
+	}
+	z := NewPoly(7)
+	if r, err := z.TryDivSeries(NewPoly(1), x, 3); !errors.Is(err, flint.ErrDivisionByZero) || r != z || z.String() != "7" {
+		t.Errorf("TryDivSeries by x = %v, %v, want 7, ErrDivisionByZero", r, err)
+	}
+	if r, err := z.TryDivSeries(NewPoly(1), d, 3); err != nil || r.String() != "x^2 + x + 1" {
+		t.Errorf("TryDivSeries(1, 1 - x) = %v, %v, want x^2 + x + 1, nil", r, err)
+	}
+}
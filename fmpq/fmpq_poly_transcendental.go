@@ -0,0 +1,165 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpq.h>
+// #include <flint/fmpq_poly.h>
+import "C"
+
+// series1 is the signature shared by FLINT's series functions.
+type series1 func(*C.fmpq_poly_struct, *C.fmpq_poly_struct, C.slong)
+
+// apply sets z = f(x) to n terms after checking the precondition.
+func (z *Poly) apply(fn string, f series1, x *Poly, n int64, want constTerm) (*Poly, error) {
+	if err := checkSeries(fn, x, n, want); err != nil {
+		return z, err
+	}
+	f((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), C.slong(n))
+	return z, nil
+}
+
+// LogSeries sets z to the first n terms of log(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 1, z is
+// unchanged and LogSeries returns a *SeriesError.
+func (z *Poly) LogSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("LogSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_log_series(r, a, n) }, x, n, constOne)
+}
+
+// InvSeries sets z to the first n terms of 1/x and returns
+// (z, nil).  If n < 1 or the constant term of x is 0, z is
+// unchanged and InvSeries returns a *SeriesError.
+func (z *Poly) InvSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("InvSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_inv_series(r, a, n) }, x, n, constNonzero)
+}
+
+// SqrtSeries sets z to the first n terms of sqrt(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 1, z is
+// unchanged and SqrtSeries returns a *SeriesError.
+func (z *Poly) SqrtSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("SqrtSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_sqrt_series(r, a, n) }, x, n, constOne)
+}
+
+// InvSqrtSeries sets z to the first n terms of 1/sqrt(x) and
+// returns (z, nil).  If n < 1 or the constant term of x is not 1, z
+// is unchanged and InvSqrtSeries returns a *SeriesError.
+func (z *Poly) InvSqrtSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("InvSqrtSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_invsqrt_series(r, a, n) }, x, n, constOne)
+}
+
+// AtanSeries sets z to the first n terms of atan(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and AtanSeries returns a *SeriesError.
+func (z *Poly) AtanSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("AtanSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_atan_series(r, a, n) }, x, n, constZero)
+}
+
+// AtanhSeries sets z to the first n terms of atanh(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and AtanhSeries returns a *SeriesError.
+func (z *Poly) AtanhSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("AtanhSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_atanh_series(r, a, n) }, x, n, constZero)
+}
+
+// AsinSeries sets z to the first n terms of asin(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and AsinSeries returns a *SeriesError.
+func (z *Poly) AsinSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("AsinSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_asin_series(r, a, n) }, x, n, constZero)
+}
+
+// AsinhSeries sets z to the first n terms of asinh(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and AsinhSeries returns a *SeriesError.
+func (z *Poly) AsinhSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("AsinhSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_asinh_series(r, a, n) }, x, n, constZero)
+}
+
+// SinSeries sets z to the first n terms of sin(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and SinSeries returns a *SeriesError.
+func (z *Poly) SinSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("SinSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_sin_series(r, a, n) }, x, n, constZero)
+}
+
+// CosSeries sets z to the first n terms of cos(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and CosSeries returns a *SeriesError.
+func (z *Poly) CosSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("CosSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_cos_series(r, a, n) }, x, n, constZero)
+}
+
+// TanSeries sets z to the first n terms of tan(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and TanSeries returns a *SeriesError.
+func (z *Poly) TanSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("TanSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_tan_series(r, a, n) }, x, n, constZero)
+}
+
+// SinhSeries sets z to the first n terms of sinh(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and SinhSeries returns a *SeriesError.
+func (z *Poly) SinhSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("SinhSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_sinh_series(r, a, n) }, x, n, constZero)
+}
+
+// CoshSeries sets z to the first n terms of cosh(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and CoshSeries returns a *SeriesError.
+func (z *Poly) CoshSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("CoshSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_cosh_series(r, a, n) }, x, n, constZero)
+}
+
+// TanhSeries sets z to the first n terms of tanh(x) and returns
+// (z, nil).  If n < 1 or the constant term of x is not 0, z is
+// unchanged and TanhSeries returns a *SeriesError.
+func (z *Poly) TanhSeries(x *Poly, n int64) (*Poly, error) {
+	return z.apply("TanhSeries", func(r, a *C.fmpq_poly_struct, n C.slong) { C.fmpq_poly_tanh_series(r, a, n) }, x, n, constZero)
+}
+
+// PowSeries sets z to the first n terms of x^r, computed as
+// exp(r*log(x)), and returns (z, nil).  If n < 1 or the constant
+// term of x is not 1, z is unchanged and PowSeries returns a
+// *SeriesError.
+func (z *Poly) PowSeries(x *Poly, r *Rat, n int64) (*Poly, error) {
+	if err := checkSeries("PowSeries", x, n, constOne); err != nil {
+		return z, err
+	}
+	t := NewPoly(0)
+	C.fmpq_poly_log_series((*C.fmpq_poly_struct)(t), (*C.fmpq_poly_struct)(x), C.slong(n))
+	C.fmpq_poly_scalar_mul_fmpq((*C.fmpq_poly_struct)(t), (*C.fmpq_poly_struct)(t), (*C.fmpq)(r))
+	C.fmpq_poly_exp_series((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(t), C.slong(n))
+	return z, nil
+}
+
+// ComposeSeries sets z to the first n terms of x(y) and returns
+// (z, nil).  If n < 1 or the constant term of y is not 0, z is
+// unchanged and ComposeSeries returns a *SeriesError.
+func (z *Poly) ComposeSeries(x, y *Poly, n int64) (*Poly, error) {
+	if err := checkSeries("ComposeSeries", y, n, constZero); err != nil {
+		return z, err
+	}
+	C.fmpq_poly_compose_series((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), (*C.fmpq_poly_struct)(y), C.slong(n))
+	return z, nil
+}
+
+// RevertSeries sets z to the first n terms of the compositional
+// inverse of x, the series g with x(g(t)) = g(x(t)) = t, using
+// Lagrange inversion, and returns (z, nil).  If n < 1, the constant
+// term of x is not 0 or its linear term is 0, z is unchanged and
+// RevertSeries returns a *SeriesError.
+func (z *Poly) RevertSeries(x *Poly, n int64) (*Poly, error) {
+	if err := checkSeries("RevertSeries", x, n, constZero); err != nil {
+		return z, err
+	}
+	if c := x.Coeff(1); C.fmpq_is_zero((*C.fmpq)(c)) != 0 {
+		return z, &SeriesError{"RevertSeries", "linear term must not be 0"}
+	}
+	C.fmpq_poly_revert_series((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), C.slong(n))
+	return z, nil
+}
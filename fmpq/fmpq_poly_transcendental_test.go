@@ -0,0 +1,87 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// intPoly returns the polynomial with integer coefficients cs,
+// lowest degree first.
+func intPoly(cs ...int64) *Poly {
+	z := NewPoly(0)
+	for i, c := range cs {
+		z.SetCoeff64(int64(i), c)
+	}
+	return z
+}
+
+func TestTranscendentalSeries(t *testing.T) {
+	x := intPoly(0, 1)
+	onePlusX := intPoly(1, 1)
+	for _, tc := range []struct {
+		name string
+		f    func(z *Poly) (*Poly, error)
+		want string
+	}{
+		{"LogSeries", func(z *Poly) (*Poly, error) { return z.LogSeries(onePlusX, 5) }, "-1/4*x^4 + 1/3*x^3 - 1/2*x^2 + x"},
+		{"InvSeries", func(z *Poly) (*Poly, error) { return z.InvSeries(intPoly(1, -1), 4) }, "x^3 + x^2 + x + 1"},
+		{"SqrtSeries", func(z *Poly) (*Poly, error) { return z.SqrtSeries(onePlusX, 3) }, "-1/8*x^2 + 1/2*x + 1"},
+		{"InvSqrtSeries", func(z *Poly) (*Poly, error) { return z.InvSqrtSeries(onePlusX, 3) }, "3/8*x^2 - 1/2*x + 1"},
+		{"AtanSeries", func(z *Poly) (*Poly, error) { return z.AtanSeries(x, 6) }, "1/5*x^5 - 1/3*x^3 + x"},
+		{"AtanhSeries", func(z *Poly) (*Poly, error) { return z.AtanhSeries(x, 6) }, "1/5*x^5 + 1/3*x^3 + x"},
+		{"AsinSeries", func(z *Poly) (*Poly, error) { return z.AsinSeries(x, 6) }, "3/40*x^5 + 1/6*x^3 + x"},
+		{"AsinhSeries", func(z *Poly) (*Poly, error) { return z.AsinhSeries(x, 6) }, "3/40*x^5 - 1/6*x^3 + x"},
+		{"SinSeries", func(z *Poly) (*Poly, error) { return z.SinSeries(x, 6) }, "1/120*x^5 - 1/6*x^3 + x"},
+		{"CosSeries", func(z *Poly) (*Poly, error) { return z.CosSeries(x, 5) }, "1/24*x^4 - 1/2*x^2 + 1"},
+		{"TanSeries", func(z *Poly) (*Poly, error) { return z.TanSeries(x, 6) }, "2/15*x^5 + 1/3*x^3 + x"},
+		{"SinhSeries", func(z *Poly) (*Poly, error) { return z.SinhSeries(x, 6) }, "1/120*x^5 + 1/6*x^3 + x"},
+		{"CoshSeries", func(z *Poly) (*Poly, error) { return z.CoshSeries(x, 5) }, "1/24*x^4 + 1/2*x^2 + 1"},
+		{"TanhSeries", func(z *Poly) (*Poly, error) { return z.TanhSeries(x, 6) }, "2/15*x^5 - 1/3*x^3 + x"},
+		{"PowSeries", func(z *Poly) (*Poly, error) { return z.PowSeries(onePlusX, NewRat(-1, 2), 3) }, "3/8*x^2 - 1/2*x + 1"},
+		{"ComposeSeries", func(z *Poly) (*Poly, error) { return z.ComposeSeries(intPoly(1, 1, 1), intPoly(0, 2), 3) }, "4*x^2 + 2*x + 1"},
+		// The inverse g of x + x^2 satisfies g + g^2 = x.
+		{"RevertSeries", func(z *Poly) (*Poly, error) { return z.RevertSeries(intPoly(0, 1, 1), 4) }, "2*x^3 - x^2 + x"},
+	} {
+		z, err := tc.f(NewPoly(0))
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		} else if s := z.String(); s != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, s, tc.want)
+		}
+	}
+}
+
+func TestTranscendentalSeriesErrors(t *testing.T) {
+	x := intPoly(0, 1)
+	for _, tc := range []struct {
+		name string
+		f    func(z *Poly) (*Poly, error)
+	}{
+		{"LogSeries", func(z *Poly) (*Poly, error) { return z.LogSeries(x, 5) }},
+		{"SqrtSeries", func(z *Poly) (*Poly, error) { return z.SqrtSeries(intPoly(4, 1), 5) }},
+		{"InvSeries", func(z *Poly) (*Poly, error) { return z.InvSeries(x, 5) }},
+		{"SinSeries", func(z *Poly) (*Poly, error) { return z.SinSeries(intPoly(1, 1), 5) }},
+		{"CosSeries", func(z *Poly) (*Poly, error) { return z.CosSeries(x, 0) }},
+		{"PowSeries", func(z *Poly) (*Poly, error) { return z.PowSeries(x, NewRat(1, 2), 5) }},
+		{"ComposeSeries", func(z *Poly) (*Poly, error) { return z.ComposeSeries(x, intPoly(1, 1), 5) }},
+		{"RevertSeries", func(z *Poly) (*Poly, error) { return z.RevertSeries(intPoly(0, 0, 1), 5) }},
+	} {
+		z := NewPoly(7)
+		r, err := tc.f(z)
+		var se *SeriesError
+		if !errors.As(err, &se) || se.Func != tc.name || !errors.Is(err, flint.ErrDomain) {
+			t.Errorf("%s: err = %v, want a *SeriesError wrapping ErrDomain", tc.name, err)
+		}
+		if r != z || z.String() != "7" {
+			t.Errorf("%s changed its receiver to %v", tc.name, z)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/ulong_extras.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpq.h>
+import "C"
+
+import (
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// Reconstruct sets z to the rational n/d with |n|, d <= sqrt(m/2)
+// congruent to a modulo m, and returns z.  Such an n/d is unique
+// if it exists; otherwise Reconstruct returns false.  If m <= 0,
+// Reconstruct panics with flint.ErrDomain.
+func (z *Rat) Reconstruct(a, m *fmpz.Int) (*Rat, bool) {
+	if m.Sign() <= 0 {
+		flint.Panic("fmpq.Rat.Reconstruct", flint.ErrDomain)
+	}
+	ok := C.fmpq_reconstruct_fmpz((*C.fmpq)(z), (*C.fmpz)(a), (*C.fmpz)(m)) != 0
+	return z, ok
+}
+
+// ReconstructBounds sets z to the rational n/d with |n| <= nb and 0
+// < d <= db congruent to a modulo m, and returns z.  The bounds must
+// satisfy 2*nb*db < m, which makes n/d unique if it exists;
+// otherwise ReconstructBounds returns false.  Bounds that violate
+// 2*nb*db < m panic with flint.ErrDomain.
+func (z *Rat) ReconstructBounds(a, m, nb, db *fmpz.Int) (*Rat, bool) {
+	t := fmpz.NewInt(0).Mul(nb, db)
+	if nb.Sign() < 0 || db.Sign() <= 0 || t.Add(t, t).Cmp(m) >= 0 {
+		flint.Panic("fmpq.Rat.ReconstructBounds", flint.ErrDomain)
+	}
+	ok := C.fmpq_reconstruct_fmpz_2((*C.fmpq)(z), (*C.fmpz)(a), (*C.fmpz)(m), (*C.fmpz)(nb), (*C.fmpz)(db)) != 0
+	return z, ok
+}
+
+// nextPrime returns the smallest prime greater than p.
+func nextPrime(p uint64) uint64 {
+	return uint64(C.n_nextprime(C.mp_limb_t(p), 0))
+}
@@ -0,0 +1,80 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpq
+
+import (
+	"math/big"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// Reconstruct sets z to the rational n/d with |n|, d <= sqrt(m/2)
+// congruent to a modulo m, and returns z.  Such an n/d is unique
+// if it exists; otherwise Reconstruct returns false.  If m <= 0,
+// Reconstruct panics with flint.ErrDomain.
+func (z *Rat) Reconstruct(a, m *fmpz.Int) (*Rat, bool) {
+	if m.Sign() <= 0 {
+		flint.Panic("fmpq.Rat.Reconstruct", flint.ErrDomain)
+	}
+	// N = floor(sqrt((m-1)/2)), so that 2*N*N < m.
+	n := new(big.Int).Sub((*big.Int)(m), big.NewInt(1))
+	n.Rsh(n, 1).Sqrt(n)
+	return z.reconstruct((*big.Int)(a), (*big.Int)(m), n, n)
+}
+
+// ReconstructBounds sets z to the rational n/d with |n| <= nb and 0
+// < d <= db congruent to a modulo m, and returns z.  The bounds must
+// satisfy 2*nb*db < m, which makes n/d unique if it exists;
+// otherwise ReconstructBounds returns false.  Bounds that violate
+// 2*nb*db < m panic with flint.ErrDomain.
+func (z *Rat) ReconstructBounds(a, m, nb, db *fmpz.Int) (*Rat, bool) {
+	t := fmpz.NewInt(0).Mul(nb, db)
+	if nb.Sign() < 0 || db.Sign() <= 0 || t.Add(t, t).Cmp(m) >= 0 {
+		flint.Panic("fmpq.Rat.ReconstructBounds", flint.ErrDomain)
+	}
+	return z.reconstruct((*big.Int)(a), (*big.Int)(m), (*big.Int)(nb), (*big.Int)(db))
+}
+
+// reconstruct runs the extended Euclidean algorithm on m and a mod m
+// until the remainder is at most nb; the remainder and its cofactor
+// are then the only candidate for n and d.
+func (z *Rat) reconstruct(a, m, nb, db *big.Int) (*Rat, bool) {
+	r0, r1 := new(big.Int).Set(m), new(big.Int).Mod(a, m)
+	t0, t1 := big.NewInt(0), big.NewInt(1)
+	q, t := new(big.Int), new(big.Int)
+	for r1.Cmp(nb) > 0 {
+		q.Quo(r0, r1)
+		r0.Sub(r0, t.Mul(q, r1))
+		t0.Sub(t0, t.Mul(q, t1))
+		r0, r1 = r1, r0
+		t0, t1 = t1, t0
+	}
+	if t1.Sign() < 0 {
+		r1.Neg(r1)
+		t1.Neg(t1)
+	}
+	one := big.NewInt(1)
+	if t1.Cmp(db) > 0 || t.GCD(nil, nil, t1, m).Cmp(one) != 0 || t.GCD(nil, nil, r1, t1).Cmp(one) != 0 {
+		return z, false
+	}
+	z.big().SetFrac(r1, t1)
+	return z, true
+}
+
+// nextPrime returns the smallest prime greater than p.
+func nextPrime(p uint64) uint64 {
+	x := new(big.Int).SetUint64(p)
+	one := big.NewInt(1)
+	for {
+		x.Add(x, one)
+		// ProbablyPrime is exact below 2^64.
+		if x.ProbablyPrime(0) {
+			return x.Uint64()
+		}
+	}
+}
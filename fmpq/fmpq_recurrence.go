@@ -0,0 +1,159 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/ulong_extras.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpq.h>
+// #include <flint/fmpq_poly.h>
+import "C"
+
+import (
+	"math/bits"
+
+	"github.com/frithjof-schulze/go.flint/fmpz"
+	"github.com/frithjof-schulze/go.flint/nmod"
+)
+
+// BerlekampMassey returns the minimal polynomial
+//
+//	v = c_0 + c_1*x + ... + c_(L-1)*x^(L-1) + x^L
+//
+// of the sequence xs over Q, the monic polynomial of least degree
+// such that c_0*xs[j] + ... + c_L*xs[j+L] = 0 for 0 <= j <
+// len(xs)-L, together with the initial terms xs[:L] that fix the
+// sequence.  The recurrence is computed modulo word-size primes
+// and lifted with Multimodular until it holds for xs.  If 2*L >=
+// len(xs), the data does not determine the recurrence and
+// BerlekampMassey returns false; it returns (nil, nil, false) if
+// the lifting exceeds the bound on the size of the recurrence
+// implied by xs.
+func BerlekampMassey(xs []*Rat) (v *Poly, init []*Rat, ok bool) {
+	f := func(p uint64) ([]uint64, bool) {
+		ys := make([]uint64, len(xs))
+		if !reduceRats(ys, xs, p) {
+			return nil, false
+		}
+		return nmod.BerlekampMassey(ys, p), true
+	}
+	cs, ok := Multimodular(f, func(cs []*Rat) bool { return isRecurrence(cs, xs) }, recurrenceBits(xs))
+	if !ok {
+		return nil, nil, false
+	}
+
+	v = NewPoly(0)
+	for i, c := range cs {
+		v.SetCoeff(i, c)
+	}
+	l := len(cs) - 1
+	init = make([]*Rat, l)
+	for i := range init {
+		init[i] = NewRat(0, 1).Set(xs[i])
+	}
+	return v, init, 2*l < len(xs)
+}
+
+// recurrenceBits bounds the size of the modulus needed to lift the
+// minimal polynomial of xs.  Its coefficients are quotients of
+// Hankel determinants of order k <= len(xs)/2 + 1 in xs; clearing
+// the denominators of each row and applying Hadamard's inequality
+// bounds their numerators and denominators by 2^b, and rational
+// reconstruction of a quotient of two of them needs 4*b bits.
+func recurrenceBits(xs []*Rat) int {
+	k := len(xs)/2 + 1
+	h, d := 0, 0
+	num, den := fmpz.NewInt(0), fmpz.NewInt(0)
+	for _, x := range xs {
+		h = max(h, x.Num(num).BitLen())
+		d += x.Denom(den).BitLen()
+	}
+	b := k*(h+d) + k*bits.Len(uint(k))
+	return 4*b + 64
+}
+
+// reduceRats sets ys[i] = xs[i] mod p.  It returns false if p
+// divides a denominator.
+func reduceRats(ys []uint64, xs []*Rat, p uint64) bool {
+	for i, x := range xs {
+		q := (*C.fmpq)(x)
+		d := C.fmpz_fdiv_ui(&q.den, C.ulong(p))
+		if d == 0 {
+			return false
+		}
+		n := C.fmpz_fdiv_ui(&q.num, C.ulong(p))
+		d = C.ulong(C.n_invmod(C.mp_limb_t(d), C.mp_limb_t(p)))
+		ys[i] = uint64(C.n_mulmod2(C.mp_limb_t(n), C.mp_limb_t(d), C.mp_limb_t(p)))
+	}
+	return true
+}
+
+// isRecurrence reports whether xs satisfies the recurrence
+// cs[0]*xs[j] + ... + cs[l]*xs[j+l] = 0.
+func isRecurrence(cs, xs []*Rat) bool {
+	l := len(cs) - 1
+	s, t := NewRat(0, 1), NewRat(0, 1)
+	for j := 0; j+l < len(xs); j++ {
+		s.SetRat64(0, 1)
+		for i, c := range cs {
+			s.Add(s, t.Mul(c, xs[j+i]))
+		}
+		if C.fmpq_is_zero((*C.fmpq)(s)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// GuessPRecurrence looks for a P-recursive (holonomic) recurrence
+// of order r and degree d satisfied by xs: polynomials p_0, ...,
+// p_r of degree at most d, not all zero, such that
+//
+//	p_0(n)*xs[n] + p_1(n)*xs[n+1] + ... + p_r(n)*xs[n+r] = 0
+//
+// for 0 <= n < len(xs)-r.  To make a spurious solution unlikely,
+// xs must provide at least two more equations than there are
+// unknown coefficients.  If there are too few terms or no such
+// recurrence exists, GuessPRecurrence returns false.  If the
+// recurrence is not unique, one of them is returned.
+func GuessPRecurrence(xs []*fmpz.Int, r, d int) ([]*Poly, bool) {
+	if r < 0 || d < 0 {
+		panic("fmpq: negative recurrence order or degree")
+	}
+	rows, cols := len(xs)-r, (r+1)*(d+1)
+	if rows < cols+2 {
+		return nil, false
+	}
+
+	a := NewMat(rows, cols)
+	one := fmpz.NewInt(1)
+	e, t := NewRat(0, 1), fmpz.NewInt(0)
+	for n := 0; n < rows; n++ {
+		nn := fmpz.NewInt(int64(n))
+		for i := 0; i <= r; i++ {
+			t.Set(xs[n+i])
+			for k := 0; k <= d; k++ {
+				a.SetEntry(n, i*(d+1)+k, e.SetFrac(t, one))
+				t.Mul(t, nn)
+			}
+		}
+	}
+
+	v := a.Nullspace()
+	if v.Cols() == 0 {
+		return nil, false
+	}
+	ps := make([]*Poly, r+1)
+	for i := range ps {
+		ps[i] = NewPoly(0)
+		for k := 0; k <= d; k++ {
+			ps[i].SetCoeff(k, v.Entry(i*(d+1)+k, 0))
+		}
+	}
+	return ps, true
+}
@@ -0,0 +1,63 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpq
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBerlekampMassey(t *testing.T) {
+	half := make([]*Rat, 6)
+	for i := range half {
+		half[i] = NewRat(1, 1<<i)
+	}
+	for _, tc := range []struct {
+		xs   []*Rat
+		v    string
+		init string
+		ok   bool
+	}{
+		{[]*Rat{NewRat(1, 1), NewRat(1, 1), NewRat(2, 1), NewRat(3, 1), NewRat(5, 1), NewRat(8, 1), NewRat(13, 1)}, "x^2 - x - 1", "[1/1 1/1]", true},
+		{half, "x - 1/2", "[1/1]", true},
+		// A single step does not determine a recurrence of order 1.
+		{[]*Rat{NewRat(1, 1), NewRat(2, 1)}, "x - 2", "[1/1]", false},
+	} {
+		v, init, ok := BerlekampMassey(tc.xs)
+		if v.String() != tc.v || fmt.Sprint(init) != tc.init || ok != tc.ok {
+			t.Errorf("BerlekampMassey(%v) = %v, %v, %v, want %s, %s, %v", tc.xs, v, init, ok, tc.v, tc.init, tc.ok)
+		}
+	}
+}
+
+func TestGuessPRecurrence(t *testing.T) {
+	// n! satisfies (n + 1)*x_n - x_(n+1) = 0.
+	fac := ints(1, 1, 2, 6, 24, 120, 720, 5040)
+	ps, ok := GuessPRecurrence(fac, 1, 1)
+	if !ok || len(ps) != 2 || ps[1].Degree() != 0 {
+		t.Fatalf("GuessPRecurrence(n!, 1, 1) = %v, %v", ps, ok)
+	}
+	s := NewPoly(0).Mul(ps[1], intPoly(1, 1))
+	if s.Add(s, ps[0]).Degree() >= 0 {
+		t.Errorf("GuessPRecurrence(n!, 1, 1) = %v, want a multiple of [x + 1, -1]", ps)
+	}
+
+	if _, ok := GuessPRecurrence(fac[:6], 1, 1); ok {
+		t.Errorf("GuessPRecurrence succeeded with too few terms")
+	}
+	// 2^n + n! satisfies no recurrence of order 1 and degree 1.
+	mixed := ints(2, 3, 6, 14, 40, 152, 784, 5168)
+	if ps, ok := GuessPRecurrence(mixed, 1, 1); ok {
+		t.Errorf("GuessPRecurrence(2^n + n!, 1, 1) = %v, want failure", ps)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("GuessPRecurrence with negative order did not panic")
+		}
+	}()
+	GuessPRecurrence(fac, -1, 1)
+}
@@ -0,0 +1,195 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpq.h>
+// #include <flint/fmpq_poly.h>
+import "C"
+
+import (
+	"strconv"
+)
+
+// A Series represents a truncated power series
+//
+//	c_0 + c_1*x + ... + c_(n-1)*x^(n-1) + O(x^n)
+//
+// with rational coefficients.  The precision n records how many
+// coefficients are known; every operation computes the precision
+// of its result from the precisions of its operands, so a Series
+// never claims more correct coefficients than it has.  Operations
+// whose result would not be a power series return false instead.
+type Series struct {
+	p    *Poly
+	prec int
+}
+
+// NewSeries returns the series p + O(x^n).  Terms of p of degree
+// n or more are dropped.
+func NewSeries(p *Poly, n int) *Series {
+	if n < 0 {
+		panic("fmpq: negative Series precision")
+	}
+	return &Series{p: NewPoly(0).Truncate(p, n), prec: n}
+}
+
+// NewSeriesX returns the series x + O(x^n).
+func NewSeriesX(n int) *Series {
+	return NewSeries(NewPoly(0).SetCoeff64(1, 1), n)
+}
+
+// Prec returns the precision n of z = ... + O(x^n).
+func (z *Series) Prec() int {
+	return z.prec
+}
+
+// Poly returns the known part of z as a polynomial of degree
+// less than z.Prec().
+func (z *Series) Poly() *Poly {
+	return NewPoly(0).Set(z.p)
+}
+
+// Val returns the valuation of z.  If all known coefficients of z
+// are zero, Val returns z.Prec().
+func (z *Series) Val() int {
+	if v := z.p.Val(); v >= 0 {
+		return v
+	}
+	return z.prec
+}
+
+// Coeff returns the coefficient of x^i in z and true.  If i is not
+// below the precision of z the coefficient is unknown and Coeff
+// returns (nil, false).
+func (z *Series) Coeff(i int) (*Rat, bool) {
+	if i < 0 || i >= z.prec {
+		return nil, false
+	}
+	return z.p.Coeff(i), true
+}
+
+// Set sets z = x and returns z.
+func (z *Series) Set(x *Series) *Series {
+	z.p = NewPoly(0).Set(x.p)
+	z.prec = x.prec
+	return z
+}
+
+// SetPrec lowers the precision of z to n and returns (z, true).
+// Raising the precision would invent coefficients, so if n is larger
+// than z.Prec(), z is unchanged and SetPrec returns (z, false).
+func (z *Series) SetPrec(n int) (*Series, bool) {
+	if n < 0 || n > z.prec {
+		return z, false
+	}
+	z.p.Truncate(z.p, n)
+	z.prec = n
+	return z, true
+}
+
+// String returns a string representation of z in the variable
+// 'x' with a trailing O(x^n) term.
+func (z *Series) String() string {
+	o := "O(x^" + strconv.Itoa(z.prec) + ")"
+	if z.p.Degree() < 0 {
+		return o
+	}
+	return z.p.String() + " + " + o
+}
+
+// result stores p truncated to n terms in z and returns z.
+func (z *Series) result(p *Poly, n int) *Series {
+	if z.p == nil {
+		z.p = NewPoly(0)
+	}
+	z.p.Truncate(p, n)
+	z.prec = n
+	return z
+}
+
+// Add sets z = x + y and returns z.
+func (z *Series) Add(x, y *Series) *Series {
+	return z.result(NewPoly(0).Add(x.p, y.p), min(x.prec, y.prec))
+}
+
+// Sub sets z = x - y and returns z.
+func (z *Series) Sub(x, y *Series) *Series {
+	return z.result(NewPoly(0).Sub(x.p, y.p), min(x.prec, y.prec))
+}
+
+// Neg sets z = -x and returns z.
+func (z *Series) Neg(x *Series) *Series {
+	return z.result(NewPoly(0).Neg(x.p), x.prec)
+}
+
+// Mul sets z = x * y and returns z.  If x = x^a*u + O(x^m) and
+// y = x^b*w + O(x^n), the product is known to O(x^min(m+b, n+a)).
+func (z *Series) Mul(x, y *Series) *Series {
+	n := min(x.prec+y.Val(), y.prec+x.Val())
+	return z.result(NewPoly(0).MulLow(x.p, y.p, int64(n)), n)
+}
+
+// Div sets z = x / y and returns (z, true).  Writing x = x^a*u and
+// y = x^b*w with units u and w, the quotient is a power series only
+// if a >= b; otherwise, or if y is zero to its precision, z is
+// unchanged and Div returns (z, false).
+func (z *Series) Div(x, y *Series) (*Series, bool) {
+	a, b := x.Val(), y.Val()
+	if b >= y.prec || a < b {
+		return z, false
+	}
+	// Divide x^(a-b)*u by w, both shifted down by b.
+	n := min(x.prec-b, y.prec-b)
+	if n == 0 {
+		return z.result(NewPoly(0), 0), true
+	}
+	u := NewPoly(0).shift(x.p, b)
+	w := NewPoly(0).shift(y.p, b)
+	return z.result(NewPoly(0).DivSeries(u, w, int64(n)), n), true
+}
+
+// Inv sets z = 1/x and returns (z, true).  If the constant term of
+// x is zero, z is unchanged and Inv returns (z, false).
+func (z *Series) Inv(x *Series) (*Series, bool) {
+	one := &Series{p: NewPoly(1), prec: x.prec}
+	return z.Div(one, x)
+}
+
+// Exp sets z = exp(x) and returns (z, true).  If the constant term
+// of x is not zero, exp(x) is not a rational power series; then z
+// is unchanged and Exp returns (z, false).  This includes x = O(1),
+// whose constant term is unknown.
+func (z *Series) Exp(x *Series) (*Series, bool) {
+	if x.Val() == 0 {
+		return z, false
+	}
+	return z.result(NewPoly(0).ExpSeries(x.p, int64(x.prec)), x.prec), true
+}
+
+// Compose sets z = x(y) and returns (z, true).  The constant term
+// of y must be zero; otherwise z is unchanged and Compose returns
+// (z, false).  If y has valuation v, the result is known to
+// O(x^min(v*x.Prec(), y.Prec())).
+func (z *Series) Compose(x, y *Series) (*Series, bool) {
+	v := y.Val()
+	if v == 0 {
+		return z, false
+	}
+	n := min(v*x.prec, y.prec)
+	t := NewPoly(0)
+	C.fmpq_poly_compose_series((*C.fmpq_poly_struct)(t), (*C.fmpq_poly_struct)(x.p), (*C.fmpq_poly_struct)(y.p), C.slong(n))
+	return z.result(t, n), true
+}
+
+// shift sets z = x / t^n, dropping terms of degree below n, and
+// returns z.
+func (z *Poly) shift(x *Poly, n int) *Poly {
+	C.fmpq_poly_shift_right((*C.fmpq_poly_struct)(z), (*C.fmpq_poly_struct)(x), C.slong(n))
+	return z
+}
@@ -0,0 +1,92 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpq
+
+import "testing"
+
+// series returns the series with coefficients cs[i][0]/cs[i][1],
+// lowest degree first, plus O(x^n).
+func series(n int, cs ...[2]int64) *Series {
+	return NewSeries(poly(cs...), n)
+}
+
+func TestSeries(t *testing.T) {
+	x := NewSeriesX(4)
+	u := series(4, [2]int64{1, 1}, [2]int64{-1, 1}) // 1 - x
+	mustSeries := func(z *Series, ok bool) *Series {
+		t.Helper()
+		if !ok {
+			t.Fatalf("operation failed")
+		}
+		return z
+	}
+	for _, tc := range []struct {
+		name string
+		z    *Series
+		want string
+	}{
+		{"NewSeriesX", x, "x + O(x^4)"},
+		{"NewSeries", series(2, [2]int64{1, 1}, [2]int64{2, 1}, [2]int64{3, 1}), "2*x + 1 + O(x^2)"},
+		{"zero", NewSeries(NewPoly(0), 3), "O(x^3)"},
+		{"Add", new(Series).Add(x, series(2, [2]int64{1, 2})), "x + 1/2 + O(x^2)"},
+		{"Sub", new(Series).Sub(u, x), "-2*x + 1 + O(x^4)"},
+		{"Neg", new(Series).Neg(u), "x - 1 + O(x^4)"},
+		// (x + O(x^3)) * (x^2 + O(x^4)) is known to O(x^5).
+		{"Mul", new(Series).Mul(series(3, [2]int64{0, 1}, [2]int64{1, 1}), series(4, [2]int64{0, 1}, [2]int64{0, 1}, [2]int64{1, 1})), "x^3 + O(x^5)"},
+		{"Inv", mustSeries(new(Series).Inv(u)), "x^3 + x^2 + x + 1 + O(x^4)"},
+		// x^2 / (x + x^2) = x / (1 + x).
+		{"Div", mustSeries(new(Series).Div(series(5, [2]int64{0, 1}, [2]int64{0, 1}, [2]int64{1, 1}), series(4, [2]int64{0, 1}, [2]int64{1, 1}, [2]int64{1, 1}))), "-x^2 + x + O(x^3)"},
+		{"Div zero", mustSeries(new(Series).Div(NewSeries(NewPoly(0), 1), series(3, [2]int64{0, 1}, [2]int64{1, 1}))), "O(x^0)"},
+		{"Exp", mustSeries(new(Series).Exp(x)), "1/6*x^3 + 1/2*x^2 + x + 1 + O(x^4)"},
+		{"Exp zero", mustSeries(new(Series).Exp(NewSeries(NewPoly(0), 3))), "1 + O(x^3)"},
+		{"Compose", mustSeries(new(Series).Compose(series(3, [2]int64{1, 1}, [2]int64{1, 1}, [2]int64{1, 1}), series(4, [2]int64{0, 1}, [2]int64{2, 1}))), "4*x^2 + 2*x + 1 + O(x^3)"},
+	} {
+		if s := tc.z.String(); s != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, s, tc.want)
+		}
+	}
+
+	if _, ok := new(Series).Inv(x); ok {
+		t.Errorf("Inv(%v) succeeded", x)
+	}
+	if _, ok := new(Series).Div(u, x); ok {
+		t.Errorf("Div(%v, %v) succeeded", u, x)
+	}
+	if _, ok := new(Series).Compose(x, u); ok {
+		t.Errorf("Compose(%v, %v) succeeded", x, u)
+	}
+	for _, s := range []*Series{u, NewSeries(NewPoly(0), 0)} {
+		if _, ok := new(Series).Exp(s); ok {
+			t.Errorf("Exp(%v) succeeded", s)
+		}
+	}
+}
+
+func TestSeriesPrec(t *testing.T) {
+	z := series(5, [2]int64{0, 1}, [2]int64{0, 1}, [2]int64{3, 2})
+	if z.Prec() != 5 || z.Val() != 2 || NewSeries(NewPoly(0), 3).Val() != 3 {
+		t.Errorf("Prec = %d, Val = %d", z.Prec(), z.Val())
+	}
+	if c, ok := z.Coeff(2); !ok || c.String() != "3/2" {
+		t.Errorf("Coeff(2) = %v, %v, want 3/2", c, ok)
+	}
+	if _, ok := z.Coeff(5); ok {
+		t.Errorf("Coeff(5) of %v succeeded", z)
+	}
+	if _, ok := z.SetPrec(6); ok || z.Prec() != 5 {
+		t.Errorf("SetPrec(6) of %v succeeded", z)
+	}
+	if _, ok := z.SetPrec(2); !ok || z.String() != "O(x^2)" {
+		t.Errorf("SetPrec(2) = %v, %v, want O(x^2)", z, ok)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewSeries with negative precision did not panic")
+		}
+	}()
+	NewSeries(NewPoly(1), -1)
+}
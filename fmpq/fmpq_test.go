@@ -1 +1,264 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+// These tests do not depend on the backend; they are run against
+// both the FLINT backend and the pure Go one (go test -tags noflint).
+
 package fmpq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+func TestRat(t *testing.T) {
+	x, y := NewRat(3, 4), NewRat(-5, 6)
+	for _, tc := range []struct {
+		name string
+		got  *Rat
+		want string
+	}{
+		{"NewRat", NewRat(6, 4), "3/2"},
+		{"Integer", NewRat(-7, 1), "-7/1"},
+		{"Zero", NewRat(0, 5), "0/1"},
+		{"Add", NewRat(0, 1).Add(x, y), "-1/12"},
+		{"Sub", NewRat(0, 1).Sub(x, y), "19/12"},
+		{"Mul", NewRat(0, 1).Mul(x, y), "-5/8"},
+		{"Div", NewRat(0, 1).Div(x, y), "-9/10"},
+		{"Inv", NewRat(0, 1).Inv(y), "-6/5"},
+		{"Neg", NewRat(0, 1).Neg(y), "5/6"},
+		{"SetFrac", NewRat(0, 1).SetFrac(fmpz.NewInt(10), fmpz.NewInt(-4)), "-5/2"},
+	} {
+		if s := tc.got.String(); s != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, s, tc.want)
+		}
+	}
+
+	n, d := fmpz.NewInt(0), fmpz.NewInt(0)
+	if y.Num(n).Int64() != -5 || y.Denom(d).Int64() != 6 {
+		t.Errorf("Num, Denom of -5/6 = %v, %v", n, d)
+	}
+	if x.Cmp(y) != 1 || y.Cmp(x) != -1 || x.Cmp(NewRat(6, 8)) != 0 {
+		t.Errorf("Cmp gives wrong results")
+	}
+
+	for name, f := range map[string]func(){
+		"Div":     func() { NewRat(0, 1).Div(x, NewRat(0, 1)) },
+		"Inv":     func() { NewRat(0, 1).Inv(NewRat(0, 1)) },
+		"SetFrac": func() { NewRat(0, 1).SetFrac(fmpz.NewInt(1), fmpz.NewInt(0)) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDivisionByZero) {
+			t.Errorf("%s by zero: err = %v, want ErrDivisionByZero", name, err)
+		}
+	}
+
+	z := NewRat(7, 1)
+	if r, err := z.TryDiv(x, NewRat(0, 1)); !errors.Is(err, flint.ErrDivisionByZero) || r != z || z.String() != "7/1" {
+		t.Errorf("TryDiv(%v, 0) = %v, %v, want 7/1, ErrDivisionByZero", x, r, err)
+	}
+	if r, err := z.TryInv(NewRat(0, 1)); !errors.Is(err, flint.ErrDivisionByZero) || r != z || z.String() != "7/1" {
+		t.Errorf("TryInv(0) = %v, %v, want 7/1, ErrDivisionByZero", r, err)
+	}
+	if r, err := z.TryDiv(x, y); err != nil || r.String() != "-9/10" {
+		t.Errorf("TryDiv(%v, %v) = %v, %v, want -9/10, nil", x, y, r, err)
+	}
+	if r, err := z.TryInv(y); err != nil || r.String() != "-6/5" {
+		t.Errorf("TryInv(%v) = %v, %v, want -6/5, nil", y, r, err)
+	}
+}
+
+func TestNumDenom(t *testing.T) {
+	x := NewRat(-6, 4)
+	if n := x.Num(fmpz.NewInt(0)); n.String() != "-3" {
+		t.Errorf("Num(-6/4) = %v, want -3", n)
+	}
+	if d := x.Denom(fmpz.NewInt(0)); d.String() != "2" {
+		t.Errorf("Denom(-6/4) = %v, want 2", d)
+	}
+	if s := x.String(); s != "-3/2" {
+		t.Errorf("String(-6/4) = %q, want -3/2", s)
+	}
+}
+
+func ints(xs ...int64) []*fmpz.Int {
+	zs := make([]*fmpz.Int, len(xs))
+	for i, x := range xs {
+		zs[i] = fmpz.NewInt(x)
+	}
+	return zs
+}
+
+func TestCFrac(t *testing.T) {
+	for _, tc := range []struct {
+		p, q int64
+		cs   []int64
+	}{
+		{0, 1, []int64{0}},
+		{5, 1, []int64{5}},
+		{415, 93, []int64{4, 2, 6, 7}},
+		{-415, 93, []int64{-5, 1, 1, 6, 7}},
+		{1, 2, []int64{0, 2}},
+	} {
+		x := NewRat(tc.p, tc.q)
+		cs := x.CFrac()
+		if len(cs) != len(tc.cs) {
+			t.Errorf("CFrac(%v) = %v, want %v", x, cs, tc.cs)
+			continue
+		}
+		for i := range cs {
+			if cs[i].Int64() != tc.cs[i] {
+				t.Errorf("CFrac(%v) = %v, want %v", x, cs, tc.cs)
+				break
+			}
+		}
+		if y := NewRat(0, 1).SetCFrac(cs); y.Cmp(x) != 0 {
+			t.Errorf("SetCFrac(CFrac(%v)) = %v", x, y)
+		}
+	}
+
+	rs := Convergents(ints(3, 7, 15, 1))
+	want := []string{"3/1", "22/7", "333/106", "355/113"}
+	for i := range rs {
+		if rs[i].String() != want[i] {
+			t.Errorf("Convergents = %v, want %v", rs, want)
+			break
+		}
+	}
+}
+
+func TestBestApprox(t *testing.T) {
+	pi := NewRat(0, 1).SetCFrac(ints(3, 7, 15, 1, 292, 1, 1, 1, 2))
+	for _, tc := range []struct {
+		q    int64
+		want string
+	}{
+		{1, "3/1"},
+		{7, "22/7"},
+		{100, "311/99"},
+		{113, "355/113"},
+		{1000, "355/113"},
+	} {
+		if r := NewRat(0, 1).BestApprox(pi, fmpz.NewInt(tc.q)); r.String() != tc.want {
+			t.Errorf("BestApprox(pi, %d) = %v, want %s", tc.q, r, tc.want)
+		}
+	}
+	// 11/30 lies halfway between 1/3 and 2/5; the smaller
+	// denominator wins.
+	if r := NewRat(0, 1).BestApprox(NewRat(11, 30), fmpz.NewInt(5)); r.String() != "1/3" {
+		t.Errorf("BestApprox(11/30, 5) = %v, want 1/3", r)
+	}
+}
+
+func TestFareyNeighbors(t *testing.T) {
+	for _, tc := range []struct {
+		p, q, n int64
+		l, r    string
+	}{
+		{1, 2, 5, "2/5", "3/5"},
+		{1, 3, 7, "2/7", "2/5"},
+		{0, 1, 4, "-1/4", "1/4"},
+		{2, 1, 3, "5/3", "7/3"},
+	} {
+		l, r := NewRat(tc.p, tc.q).FareyNeighbors(fmpz.NewInt(tc.n))
+		if l.String() != tc.l || r.String() != tc.r {
+			t.Errorf("FareyNeighbors(%d/%d, %d) = %v, %v, want %s, %s", tc.p, tc.q, tc.n, l, r, tc.l, tc.r)
+		}
+	}
+}
+
+func TestCalkinWilf(t *testing.T) {
+	want := []string{"0/1", "1/1", "1/2", "2/1", "1/3", "3/2", "2/3", "3/1", "1/4"}
+	x := NewRat(0, 1)
+	for i, w := range want {
+		if x.String() != w {
+			t.Fatalf("Calkin-Wilf term %d = %v, want %s", i, x, w)
+		}
+		x.NextCalkinWilf(x)
+	}
+
+	want = []string{"0/1", "1/1", "-1/1", "1/2", "-1/2", "2/1", "-2/1", "1/3"}
+	x = NewRat(0, 1)
+	for i, w := range want {
+		if x.String() != w {
+			t.Fatalf("signed Calkin-Wilf term %d = %v, want %s", i, x, w)
+		}
+		x.NextSignedCalkinWilf(x)
+	}
+}
+
+func TestSimplestBetween(t *testing.T) {
+	for _, tc := range []struct {
+		l, r [2]int64
+		want string
+	}{
+		{[2]int64{1, 3}, [2]int64{1, 2}, "1/2"},
+		{[2]int64{3, 10}, [2]int64{2, 5}, "1/3"},
+		{[2]int64{-1, 2}, [2]int64{3, 1}, "0/1"},
+		{[2]int64{7, 2}, [2]int64{11, 3}, "7/2"},
+		{[2]int64{-11, 3}, [2]int64{-7, 2}, "-7/2"},
+		{[2]int64{5, 2}, [2]int64{9, 2}, "3/1"},
+		{[2]int64{355, 113}, [2]int64{22, 7}, "22/7"},
+		{[2]int64{22, 7}, [2]int64{355, 113}, "22/7"},
+	} {
+		l, r := NewRat(tc.l[0], tc.l[1]), NewRat(tc.r[0], tc.r[1])
+		if z := NewRat(0, 1).SimplestBetween(l, r); z.String() != tc.want {
+			t.Errorf("SimplestBetween(%v, %v) = %v, want %s", l, r, z, tc.want)
+		}
+	}
+}
+
+func TestCFracMisuse(t *testing.T) {
+	for name, f := range map[string]func(){
+		"SetCFrac(nil)":         func() { NewRat(0, 1).SetCFrac(nil) },
+		"SetCFrac([1 0])":       func() { NewRat(0, 1).SetCFrac(ints(1, 0)) },
+		"SetCFrac([1 2 -3])":    func() { NewRat(0, 1).SetCFrac(ints(1, 2, -3)) },
+		"FareyNeighbors":        func() { NewRat(1, 7).FareyNeighbors(fmpz.NewInt(6)) },
+		"NextCalkinWilf(-1/2)":  func() { NewRat(0, 1).NextCalkinWilf(NewRat(-1, 2)) },
+		"BestApprox(1/3, 0)":    func() { NewRat(0, 1).BestApprox(NewRat(1, 3), fmpz.NewInt(0)) },
+		"BestApprox(1/3, -100)": func() { NewRat(0, 1).BestApprox(NewRat(1, 3), fmpz.NewInt(-100)) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+	m := fmpz.NewInt(1000003)
+	for _, x := range []*Rat{NewRat(-22, 7), NewRat(3, 4), NewRat(0, 1), NewRat(700, 1)} {
+		// a = p * q^-1 mod m
+		p, q := x.Num(fmpz.NewInt(0)), x.Denom(fmpz.NewInt(0))
+		a := fmpz.NewInt(0).Exp(q, fmpz.NewInt(1000001), m)
+		a.Mul(a, p).Mod(a, m)
+		if z, ok := NewRat(0, 1).Reconstruct(a, m); !ok || z.Cmp(x) != 0 {
+			t.Errorf("Reconstruct(%v mod %v) = %v, %v, want %v", a, m, z, ok, x)
+		}
+	}
+	// 500002 = 1/2 mod 1000003 is not reconstructible with |n| <= 10
+	// and d <= 1, but is with d <= 2.
+	a := fmpz.NewInt(500002)
+	if _, ok := NewRat(0, 1).ReconstructBounds(a, m, fmpz.NewInt(10), fmpz.NewInt(1)); ok {
+		t.Errorf("ReconstructBounds with d <= 1 succeeded")
+	}
+	if z, ok := NewRat(0, 1).ReconstructBounds(a, m, fmpz.NewInt(10), fmpz.NewInt(2)); !ok || z.String() != "1/2" {
+		t.Errorf("ReconstructBounds = %v, %v, want 1/2", z, ok)
+	}
+
+	for name, f := range map[string]func(){
+		"Reconstruct mod 0":       func() { NewRat(0, 1).Reconstruct(a, fmpz.NewInt(0)) },
+		"ReconstructBounds large": func() { NewRat(0, 1).ReconstructBounds(a, m, m, fmpz.NewInt(1)) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDomain) {
+			t.Errorf("%s: err = %v, want ErrDomain", name, err)
+		}
+	}
+}
@@ -0,0 +1,459 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_poly.h>
+// #include <flint/fmpz_poly_factor.h>
+// #include <flint/fmpq.h>
+// #include <flint/fmpq_poly.h>
+// #include <flint/fmpz_poly_q.h>
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// A RatFunc represents a rational function p/q in Q(x), where p and
+// q are integer polynomials.  It is always kept in canonical form:
+// p and q are coprime and q has positive leading coefficient.
+type RatFunc C.fmpz_poly_q_struct
+
+// NewRatFunc returns a new RatFunc initialized to 0.
+func NewRatFunc() *RatFunc {
+	z := new(RatFunc)
+	C.fmpz_poly_q_init(z.q())
+	runtime.SetFinalizer(z, (*RatFunc).destroy)
+	return z
+}
+
+// NewRatFuncPoly returns the rational function num/den.  If den is
+// zero, NewRatFuncPoly panics with flint.ErrDivisionByZero.
+func NewRatFuncPoly(num, den *Poly) *RatFunc {
+	if den.Degree() < 0 {
+		flint.Panic("fmpq.NewRatFuncPoly", flint.ErrDivisionByZero)
+	}
+	z := NewRatFunc()
+	// num = n/a and den = d/b with integer polynomials n and d, so
+	// num/den = (n*b)/(d*a).
+	C.fmpq_poly_get_numerator(z.num, (*C.fmpq_poly_struct)(num))
+	C.fmpz_poly_scalar_mul_fmpz(z.num, z.num, &(*C.fmpq_poly_struct)(den).den[0])
+	C.fmpq_poly_get_numerator(z.den, (*C.fmpq_poly_struct)(den))
+	C.fmpz_poly_scalar_mul_fmpz(z.den, z.den, &(*C.fmpq_poly_struct)(num).den[0])
+	C.fmpz_poly_q_canonicalise(z.q())
+	return z
+}
+
+func (z *RatFunc) destroy() {
+	C.fmpz_poly_q_clear(z.q())
+}
+
+func (z *RatFunc) q() *C.fmpz_poly_q_struct {
+	return (*C.fmpz_poly_q_struct)(z)
+}
+
+// Num returns the numerator of z.
+func (z *RatFunc) Num() *Poly {
+	p := NewPoly(0)
+	C.fmpq_poly_set_fmpz_poly((*C.fmpq_poly_struct)(p), z.num)
+	return p
+}
+
+// Den returns the denominator of z.
+func (z *RatFunc) Den() *Poly {
+	p := NewPoly(0)
+	C.fmpq_poly_set_fmpz_poly((*C.fmpq_poly_struct)(p), z.den)
+	return p
+}
+
+// Set sets z = x and returns z.
+func (z *RatFunc) Set(x *RatFunc) *RatFunc {
+	C.fmpz_poly_q_set(z.q(), x.q())
+	return z
+}
+
+// SetInt64 sets z = x and returns z.
+func (z *RatFunc) SetInt64(x int64) *RatFunc {
+	C.fmpz_poly_q_set_si(z.q(), C.slong(x))
+	return z
+}
+
+// SetInt sets z = x and returns z.
+func (z *RatFunc) SetInt(x *fmpz.Int) *RatFunc {
+	C.fmpz_poly_set_fmpz(z.num, (*C.fmpz)(x))
+	C.fmpz_poly_one(z.den)
+	return z
+}
+
+// SetX sets z to the variable x and returns z.
+func (z *RatFunc) SetX() *RatFunc {
+	C.fmpz_poly_zero(z.num)
+	C.fmpz_poly_set_coeff_si(z.num, 1, 1)
+	C.fmpz_poly_one(z.den)
+	return z
+}
+
+// IsZero reports whether z is zero.
+func (z *RatFunc) IsZero() bool {
+	return C.fmpz_poly_q_is_zero(z.q()) != 0
+}
+
+// Equal reports whether z and x are equal.
+func (z *RatFunc) Equal(x *RatFunc) bool {
+	return C.fmpz_poly_q_equal(z.q(), x.q()) != 0
+}
+
+// String returns a string representation of z as a rational
+// function in the variable 'x'.
+func (z *RatFunc) String() string {
+	v := C.CString("x")
+	defer C.free(unsafe.Pointer(v))
+	p := C.fmpz_poly_q_get_str_pretty(z.q(), v)
+	defer C.free(unsafe.Pointer(p))
+	return C.GoString(p)
+}
+
+// Add sets z = x + y and returns z.
+func (z *RatFunc) Add(x, y *RatFunc) *RatFunc {
+	C.fmpz_poly_q_add(z.q(), x.q(), y.q())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *RatFunc) Sub(x, y *RatFunc) *RatFunc {
+	C.fmpz_poly_q_sub(z.q(), x.q(), y.q())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *RatFunc) Neg(x *RatFunc) *RatFunc {
+	C.fmpz_poly_q_neg(z.q(), x.q())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *RatFunc) Mul(x, y *RatFunc) *RatFunc {
+	C.fmpz_poly_q_mul(z.q(), x.q(), y.q())
+	return z
+}
+
+// Div sets z = x / y and returns (z, true).  If y is zero, z is
+// unchanged and Div returns (z, false).
+func (z *RatFunc) Div(x, y *RatFunc) (*RatFunc, bool) {
+	if y.IsZero() {
+		return z, false
+	}
+	C.fmpz_poly_q_div(z.q(), x.q(), y.q())
+	return z, true
+}
+
+// Inv sets z = 1/x and returns (z, true).  If x is zero, z is
+// unchanged and Inv returns (z, false).
+func (z *RatFunc) Inv(x *RatFunc) (*RatFunc, bool) {
+	if x.IsZero() {
+		return z, false
+	}
+	C.fmpz_poly_q_inv(z.q(), x.q())
+	return z, true
+}
+
+// Pow sets z = x^n and returns (z, true).  A negative exponent is
+// allowed for non-zero x; for x = 0 and n < 0, z is unchanged and
+// Pow returns (z, false).
+func (z *RatFunc) Pow(x *RatFunc, n int64) (*RatFunc, bool) {
+	if n < 0 {
+		t := NewRatFunc()
+		if _, ok := t.Inv(x); !ok {
+			return z, false
+		}
+		C.fmpz_poly_q_pow(z.q(), t.q(), C.ulong(-n))
+		return z, true
+	}
+	C.fmpz_poly_q_pow(z.q(), x.q(), C.ulong(n))
+	return z, true
+}
+
+// Derivative sets z to the derivative of x and returns z.
+func (z *RatFunc) Derivative(x *RatFunc) *RatFunc {
+	C.fmpz_poly_q_derivative(z.q(), x.q())
+	return z
+}
+
+// Evaluate returns the value of z at a and true.  If a is a pole
+// of z, Evaluate returns (nil, false).
+func (z *RatFunc) Evaluate(a *Rat) (*Rat, bool) {
+	n, d := NewRat(0, 1), NewRat(0, 1)
+	C.fmpz_poly_evaluate_fmpq((*C.fmpq)(d), z.den, (*C.fmpq)(a))
+	if C.fmpq_is_zero((*C.fmpq)(d)) != 0 {
+		return nil, false
+	}
+	C.fmpz_poly_evaluate_fmpq((*C.fmpq)(n), z.num, (*C.fmpq)(a))
+	C.fmpq_div((*C.fmpq)(n), (*C.fmpq)(n), (*C.fmpq)(d))
+	return n, true
+}
+
+// A PartialFraction is a term Num/Den^Exp of a partial fraction
+// decomposition, with Den irreducible over Q and the degree of Num
+// less than the degree of Den.
+type PartialFraction struct {
+	Num *Poly
+	Den *Poly
+	Exp int
+}
+
+// PartialFractions returns the partial fraction decomposition of z
+// over Q: a polynomial part and a list of terms whose sum is z.
+func (z *RatFunc) PartialFractions() (*Poly, []PartialFraction) {
+	num, den := z.Num(), z.Den()
+	poly, rem := NewPoly(0), NewPoly(0)
+	poly.DivMod(num, den, rem)
+	if rem.Degree() < 0 {
+		return poly, nil
+	}
+
+	var f C.fmpz_poly_factor_struct
+	C.fmpz_poly_factor_init(&f)
+	defer C.fmpz_poly_factor_clear(&f)
+	C.fmpz_poly_factor(&f, z.den)
+
+	n := int(f.num)
+	ps := unsafe.Slice(f.p, n)
+	exps := unsafe.Slice(f.exp, n)
+	var terms []PartialFraction
+	for i := 0; i < n; i++ {
+		q := NewPoly(0)
+		C.fmpq_poly_set_fmpz_poly((*C.fmpq_poly_struct)(q), &ps[i])
+		e := int(exps[i])
+
+		// den = qe * c with qe = q^e coprime to c; the part of
+		// rem/den belonging to q is r/qe with r = rem * c^-1 mod qe.
+		qe := NewPoly(0).Exp(q, uint64(e))
+		c, t := NewPoly(0), NewPoly(0)
+		c.DivMod(den, qe, t)
+		g, s := NewPoly(0), NewPoly(0)
+		g.XGCD(s, t, c, qe)
+		r := NewPoly(0).Mul(rem, s)
+		t.DivMod(r, qe, r)
+
+		// Expand r = a_0 + a_1 q + ... + a_(e-1) q^(e-1) and emit
+		// a_j / q^(e-j).
+		for j := 0; j < e && r.Degree() >= 0; j++ {
+			a := NewPoly(0)
+			r.DivMod(r, q, a)
+			if a.Degree() >= 0 {
+				terms = append(terms, PartialFraction{Num: a, Den: NewPoly(0).Set(q), Exp: e - j})
+			}
+		}
+	}
+	return poly, terms
+}
+
+// maxPowSize bounds the degree and the coefficient size in bits of
+// the powers computed by SetString, and maxPowBits bounds their
+// product, the size of the dense representation.
+const (
+	maxPowSize = 1 << 20
+	maxPowBits = 1 << 26
+)
+
+// powFits reports whether z^n, n >= 0, is within the bounds of
+// SetString.  The coefficients of p^n are bounded by |p|^n, where
+// |p| is the sum of the absolute values of the coefficients of p.
+func (z *RatFunc) powFits(n int64) bool {
+	var s C.fmpz
+	C.fmpz_init(&s)
+	defer C.fmpz_clear(&s)
+	for _, p := range []*C.fmpz_poly_struct{z.num, z.den} {
+		if p.length == 0 {
+			continue
+		}
+		C.fmpz_zero(&s)
+		cs := unsafe.Slice(p.coeffs, p.length)
+		for i := range cs {
+			if C.fmpz_sgn(&cs[i]) < 0 {
+				C.fmpz_sub(&s, &s, &cs[i])
+			} else {
+				C.fmpz_add(&s, &s, &cs[i])
+			}
+		}
+		deg, bits := int64(p.length-1), int64(C.fmpz_clog_ui(&s, 2))
+		if deg > maxPowSize/max(n, 1) || bits > maxPowSize/max(n, 1) ||
+			(n*deg+1)*(n*bits+1) > maxPowBits {
+			return false
+		}
+	}
+	return true
+}
+
+// SetString sets z to the value of s, an expression in the
+// variable 'x' built from integers, +, -, *, /, ^ with integer
+// exponents and parentheses, such as "(x^2 - 1)/(2*x + 3)", and
+// returns (z, true).  If s cannot be parsed or divides by zero,
+// z is unchanged and SetString returns (nil, false).  So that a
+// short string cannot exhaust memory, a power whose degree or
+// coefficient size in bits may exceed 2^20, or whose degree times
+// coefficient size may exceed 2^26, is rejected as well.
+func (z *RatFunc) SetString(s string) (*RatFunc, bool) {
+	p := &ratFuncParser{s: s}
+	r, ok := p.expr()
+	p.skipSpace()
+	if !ok || p.i != len(p.s) {
+		return nil, false
+	}
+	return z.Set(r), true
+}
+
+// ratFuncParser is a recursive descent parser for RatFunc.SetString.
+type ratFuncParser struct {
+	s string
+	i int
+}
+
+func (p *ratFuncParser) skipSpace() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t' || p.s[p.i] == '\n') {
+		p.i++
+	}
+}
+
+// peek returns the next non-space byte, or 0 at the end of input.
+func (p *ratFuncParser) peek() byte {
+	p.skipSpace()
+	if p.i == len(p.s) {
+		return 0
+	}
+	return p.s[p.i]
+}
+
+// expr = term { ("+" | "-") term }
+func (p *ratFuncParser) expr() (*RatFunc, bool) {
+	z, ok := p.term()
+	for ok {
+		switch p.peek() {
+		case '+':
+			p.i++
+			var t *RatFunc
+			if t, ok = p.term(); ok {
+				z.Add(z, t)
+			}
+		case '-':
+			p.i++
+			var t *RatFunc
+			if t, ok = p.term(); ok {
+				z.Sub(z, t)
+			}
+		default:
+			return z, true
+		}
+	}
+	return nil, false
+}
+
+// term = unary { ("*" | "/") unary }
+func (p *ratFuncParser) term() (*RatFunc, bool) {
+	z, ok := p.unary()
+	for ok {
+		switch p.peek() {
+		case '*':
+			p.i++
+			var t *RatFunc
+			if t, ok = p.unary(); ok {
+				z.Mul(z, t)
+			}
+		case '/':
+			p.i++
+			var t *RatFunc
+			if t, ok = p.unary(); ok {
+				_, ok = z.Div(z, t)
+			}
+		default:
+			return z, true
+		}
+	}
+	return nil, false
+}
+
+// unary = ("-" | "+") unary | power
+func (p *ratFuncParser) unary() (*RatFunc, bool) {
+	switch p.peek() {
+	case '-':
+		p.i++
+		z, ok := p.unary()
+		if !ok {
+			return nil, false
+		}
+		return z.Neg(z), true
+	case '+':
+		p.i++
+		return p.unary()
+	}
+	return p.power()
+}
+
+// power = atom [ "^" ["-"] integer ]
+func (p *ratFuncParser) power() (*RatFunc, bool) {
+	z, ok := p.atom()
+	if !ok || p.peek() != '^' {
+		return z, ok
+	}
+	p.i++
+	neg := false
+	if p.peek() == '-' {
+		neg = true
+		p.i++
+	}
+	n, ok := p.integer()
+	if !ok || n.BitLen() > 62 || !z.powFits(n.Int64()) {
+		return nil, false
+	}
+	e := n.Int64()
+	if neg {
+		e = -e
+	}
+	return z.Pow(z, e)
+}
+
+// atom = integer | "x" | "(" expr ")"
+func (p *ratFuncParser) atom() (*RatFunc, bool) {
+	switch c := p.peek(); {
+	case c == 'x':
+		p.i++
+		return NewRatFunc().SetX(), true
+	case c == '(':
+		p.i++
+		z, ok := p.expr()
+		if !ok || p.peek() != ')' {
+			return nil, false
+		}
+		p.i++
+		return z, true
+	case '0' <= c && c <= '9':
+		n, ok := p.integer()
+		if !ok {
+			return nil, false
+		}
+		return NewRatFunc().SetInt(n), true
+	}
+	return nil, false
+}
+
+func (p *ratFuncParser) integer() (*fmpz.Int, bool) {
+	p.skipSpace()
+	j := p.i
+	for p.i < len(p.s) && '0' <= p.s[p.i] && p.s[p.i] <= '9' {
+		p.i++
+	}
+	if j == p.i {
+		return nil, false
+	}
+	return fmpz.NewInt(0).SetString(p.s[j:p.i], 10)
+}
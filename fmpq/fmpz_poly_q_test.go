@@ -0,0 +1,150 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+func ratFunc(t *testing.T, s string) *RatFunc {
+	t.Helper()
+	z, ok := NewRatFunc().SetString(s)
+	if !ok {
+		t.Fatalf("SetString(%q) failed", s)
+	}
+	return z
+}
+
+// checkRatFunc reports an error unless z has numerator num and
+// denominator den.
+func checkRatFunc(t *testing.T, name string, z *RatFunc, num, den string) {
+	t.Helper()
+	if n, d := z.Num().String(), z.Den().String(); n != num || d != den {
+		t.Errorf("%s = (%s)/(%s), want (%s)/(%s)", name, n, d, num, den)
+	}
+}
+
+func TestRatFunc(t *testing.T) {
+	x := NewRatFunc().SetX()
+	y := ratFunc(t, "x + 1")
+	checkRatFunc(t, "canonical", ratFunc(t, "(x^2 - 1)/(x - 1)"), "x + 1", "1")
+	checkRatFunc(t, "sign", ratFunc(t, "1/(-2*x)"), "-1", "2*x")
+	checkRatFunc(t, "NewRatFuncPoly",
+		NewRatFuncPoly(poly([2]int64{1, 1}, [2]int64{1, 2}), poly([2]int64{-1, 1}, [2]int64{0, 1}, [2]int64{1, 1})),
+		"x + 2", "2*x^2 - 2")
+	if err := flint.Try(func() { NewRatFuncPoly(NewPoly(1), NewPoly(0)) }); !errors.Is(err, flint.ErrDivisionByZero) {
+		t.Errorf("NewRatFuncPoly(1, 0): err = %v, want ErrDivisionByZero", err)
+	}
+
+	inv := mustInv(t, x)
+	checkRatFunc(t, "Add", NewRatFunc().Add(inv, mustInv(t, y)), "2*x + 1", "x^2 + x")
+	checkRatFunc(t, "Sub", NewRatFunc().Sub(y, x), "1", "1")
+	checkRatFunc(t, "Mul", NewRatFunc().Mul(y, inv), "x + 1", "x")
+	checkRatFunc(t, "Neg", NewRatFunc().Neg(inv), "-1", "x")
+	checkRatFunc(t, "Derivative", NewRatFunc().Derivative(inv), "-1", "x^2")
+	if z, ok := NewRatFunc().Div(x, y); !ok {
+		t.Errorf("x/(x+1) failed")
+	} else {
+		checkRatFunc(t, "Div", z, "x", "x + 1")
+	}
+	if z, ok := NewRatFunc().Pow(y, -2); !ok {
+		t.Errorf("(x+1)^-2 failed")
+	} else {
+		checkRatFunc(t, "Pow", z, "1", "x^2 + 2*x + 1")
+	}
+
+	zero := NewRatFunc()
+	if !zero.IsZero() || x.IsZero() {
+		t.Errorf("IsZero gives wrong results")
+	}
+	if _, ok := NewRatFunc().Div(x, zero); ok {
+		t.Errorf("x/0 succeeded")
+	}
+	if _, ok := NewRatFunc().Inv(zero); ok {
+		t.Errorf("Inv(0) succeeded")
+	}
+	if _, ok := NewRatFunc().Pow(zero, -1); ok {
+		t.Errorf("0^-1 succeeded")
+	}
+
+	f := ratFunc(t, "(x + 2)/(2*x^2 - 2)")
+	if v, ok := f.Evaluate(NewRat(0, 1)); !ok || v.String() != "-1/1" {
+		t.Errorf("f(0) = %v, %v, want -1", v, ok)
+	}
+	if _, ok := f.Evaluate(NewRat(-1, 1)); ok {
+		t.Errorf("f(-1) succeeded at a pole")
+	}
+	if !f.Equal(NewRatFunc().Set(f)) || f.Equal(y) {
+		t.Errorf("Equal gives wrong results")
+	}
+}
+
+func mustInv(t *testing.T, x *RatFunc) *RatFunc {
+	t.Helper()
+	z, ok := NewRatFunc().Inv(x)
+	if !ok {
+		t.Fatalf("Inv(%v) failed", x)
+	}
+	return z
+}
+
+func TestRatFuncSetString(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"x +",
+		"(x + 1",
+		"y",
+		"1/0",
+		"1/(x - x)",
+		"0^-1",
+		"x^",
+		"x^99999999999999999999",
+		"x^1048577",
+		"(x^2 + 1)^600000",
+		"(x + 1)^1048576",
+		"(2*x + 1)^524288",
+		"1000^200000",
+	} {
+		if _, ok := NewRatFunc().SetString(s); ok {
+			t.Errorf("SetString(%q) succeeded", s)
+		}
+	}
+	checkRatFunc(t, "x^-2", ratFunc(t, " x ^ - 2 "), "1", "x^2")
+	checkRatFunc(t, "unary", ratFunc(t, "-(x - 3) * +2"), "-2*x + 6", "1")
+	if z := ratFunc(t, "x^1048576"); z.Num().Degree() != 1<<20 {
+		t.Errorf("x^(2^20) has degree %d", z.Num().Degree())
+	}
+}
+
+func TestPartialFractions(t *testing.T) {
+	for _, s := range []string{
+		"(x + 2)/(x^2 - 1)",
+		"1/(x^3 + x^2)",
+		"(x^4 + 1)/((x^2 + 1)^2 * (2*x - 3))",
+		"x^2 + 1",
+	} {
+		z := ratFunc(t, s)
+		p, terms := z.PartialFractions()
+		sum := NewRatFuncPoly(p, NewPoly(1))
+		for _, f := range terms {
+			if f.Num.Degree() >= f.Den.Degree() || f.Exp < 1 {
+				t.Errorf("%s: term (%v)/(%v)^%d is not proper", s, f.Num, f.Den, f.Exp)
+			}
+			sum.Add(sum, NewRatFuncPoly(f.Num, NewPoly(0).Exp(f.Den, uint64(f.Exp))))
+		}
+		if !sum.Equal(z) {
+			t.Errorf("%s: partial fractions sum to %v", s, sum)
+		}
+	}
+	_, terms := ratFunc(t, "1/(x^3 + x^2)").PartialFractions()
+	if len(terms) != 3 {
+		t.Errorf("1/(x^3 + x^2) has %d terms, want 3", len(terms))
+	}
+}
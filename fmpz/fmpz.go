@@ -2,20 +2,24 @@
 // Use of this source code is governed by the GNU General
 // Public License version 2 (or any later version).
 
+//go:build !noflint
+
 package fmpz
 
 import (
 	"unsafe"
+	"errors"
 	"fmt"
 	"runtime"
+	"strconv"
+
+	"github.com/frithjof-schulze/go.flint/flint"
 )
 
 /*
-#cgo LDFLAGS: -lflint -lmpir -lmpfr -lm
-#cgo CFLAGS: -I /usr/local/include/flint
 #include <stdlib.h>
-#include <flint.h>
-#include <fmpz.h>
+#include <flint/flint.h>
+#include <flint/fmpz.h>
 */
 import "C"
 
@@ -68,9 +72,16 @@ func (z *Int) SetInt64(x int64) *Int {
 	return z
 }
 
+// SetUint64 sets z = x and returns z.
+func (z *Int) SetUint64(x uint64) *Int {
+	C.fmpz_set_ui((*C.fmpz)(z), C.ulong(x))
+	return z
+}
+
 // SetString interprets s as a number in the given base
 // and sets z to that value.  The base must be in the range [2,36].
-// SetString returns an error if s cannot be parsed or the base is invalid.
+// SetString returns false if s cannot be parsed or the base is invalid;
+// use ParseInt to get an error instead.
 func (z *Int) SetString(s string, base int) (*Int, bool) {
 	if base != 0 && (base < 2 || base > 36) {
 		return nil, false
@@ -91,6 +102,21 @@ func (z *Int) SetString(s string, base int) (*Int, bool) {
 	return z, true
 }
 
+// ParseInt returns the Int represented by s in the given base, as
+// SetString does.  If s cannot be parsed or the base is invalid,
+// the error is a *strconv.NumError.
+func ParseInt(s string, base int) (*Int, error) {
+	z, ok := NewInt(0).SetString(s, base)
+	if !ok {
+		err := strconv.ErrSyntax
+		if base != 0 && (base < 2 || base > 36) {
+			err = errors.New("invalid base " + strconv.Itoa(base))
+		}
+		return nil, &strconv.NumError{Func: "ParseInt", Num: s, Err: err}
+	}
+	return z, nil
+}
+
 // String returns the decimal representation of z.
 func (z *Int) String() string {
 	if z == nil {
@@ -104,45 +130,92 @@ func (z *Int) String() string {
 
 // Add sets z = x + y and returns z.
 func (z *Int) Add(x, y *Int) *Int {
-	C.fmpz_add((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
-	return z
+	if z.addSmall(x, y) {
+		return z
+	}
+	return z.addFLINT(x, y)
 }
 
 // Sub sets z = x - y and returns z.
 func (z *Int) Sub(x, y *Int) *Int {
-	C.fmpz_sub((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
-	return z
+	if z.subSmall(x, y) {
+		return z
+	}
+	return z.subFLINT(x, y)
 }
 
 // Mul sets z = x * y and returns z.
 func (z *Int) Mul(x, y *Int) *Int {
-	C.fmpz_mul((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
-	return z
+	if z.mulSmall(x, y) {
+		return z
+	}
+	return z.mulFLINT(x, y)
 }
 
 // Div sets z = x / y, rounding toward zero, and returns z.
+// If y == 0, a division-by-zero run-time panic occurs.
 func (z *Int) Div(x, y *Int) *Int {
+	if y.Sign() == 0 {
+		flint.Panic("fmpz.Int.Div", flint.ErrDivisionByZero)
+	}
 	C.fmpz_tdiv_q((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
 	return z
 }
 
+// TryDiv is like Div, but if y == 0 it leaves z unchanged and
+// returns an *flint.Error wrapping flint.ErrDivisionByZero instead
+// of panicking.
+func (z *Int) TryDiv(x, y *Int) (*Int, error) {
+	if y.Sign() == 0 {
+		return z, &flint.Error{Op: "fmpz.Int.TryDiv", Err: flint.ErrDivisionByZero}
+	}
+	return z.Div(x, y), nil
+}
+
 // Lsh sets z = x << s and returns z.
 func (z *Int) Lsh(x *Int, s uint) *Int {
 	C.fmpz_mul_2exp((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(s))
 	return z
 }
 
-// Exp sets z = x^y % m and returns z.
-// If m == nil, Exp sets z = x^y.
-func (z *Int) Exp(x, y, m *Int) *Int {
-	if m == nil {
-		C.fmpz_pow_ui((*C.fmpz)(z), (*C.fmpz)(x), C.fmpz_get_ui((*C.fmpz)(y)))
-	} else {
-		C.fmpz_powm((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y), (*C.fmpz)(m))
-	}
+// maxPowBits bounds the size in bits of the powers Exp computes
+// without a modulus.  It is the size of the largest GMP integer,
+// 2^31-1 limbs; larger powers make GMP abort the process.
+const maxPowBits = (1<<31 - 1) * C.GMP_NUMB_BITS
+
+// pow sets z = x^y and returns z.
+func (z *Int) pow(x *Int, y uint64) *Int {
+	C.fmpz_pow_ui((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(y))
+	return z
+}
+
+// powMod sets z = x^y mod m for y >= 0 and m > 0 and returns z.
+func (z *Int) powMod(x, y, m *Int) *Int {
+	C.fmpz_powm((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y), (*C.fmpz)(m))
 	return z
 }
 
+// invMod sets z to the inverse of x mod m > 0 and returns (z, true).
+// If x is not invertible, z is unchanged and invMod returns
+// (z, false).
+func (z *Int) invMod(x, m *Int) (*Int, bool) {
+	t := NewInt(0)
+	if C.fmpz_invmod((*C.fmpz)(t), (*C.fmpz)(x), (*C.fmpz)(m)) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// word returns the low 64 bits of z >= 0.
+func (z *Int) word() uint64 {
+	return uint64(C.fmpz_get_ui((*C.fmpz)(z)))
+}
+
+// bit returns the value of bit i of z >= 0.
+func (z *Int) bit(i int) uint {
+	return uint(C.fmpz_tstbit((*C.fmpz)(z), C.ulong(i)))
+}
+
 // Int64 returns the value of z as a int64.
 // TODO(What happens if this is not possible?)
 func (z *Int) Int64() int64 {
@@ -168,7 +241,16 @@ func (z *Int) Abs(x *Int) *Int {
 //	+1 if x >  0
 //
 func (z *Int) Sign() int {
-	return int(C.fmpz_sgn((*C.fmpz)(z)))
+	if v, ok := z.small(); ok {
+		switch {
+		case v < 0:
+			return -1
+		case v > 0:
+			return 1
+		}
+		return 0
+	}
+	return z.signFLINT()
 }
 
 // Cmp compares z and y and returns:
@@ -177,14 +259,11 @@ func (z *Int) Sign() int {
 //    0 if z == y
 //   +1 if z >  y
 //
-func (z *Int) Cmp(y *Int) (r int) {
-	r = int(C.fmpz_cmp((*C.fmpz)(z), (*C.fmpz)(y)))
-	if r < 0 {
-		r = -1
-	} else if r > 0 {
-		r = 1
+func (z *Int) Cmp(y *Int) int {
+	if r, ok := cmpSmall(z, y); ok {
+		return r
 	}
-	return
+	return z.cmpFLINT(y)
 }
 
 // Rsh sets z = x >> n and returns z.
@@ -216,6 +295,9 @@ func Jacobi(x, y *Int) int {
 // If y == 0, a division-by-zero run-time panic occurs.
 // Mod implements Euclidean modulus (unlike Go); see DivMod for more details.
 func (z *Int) Mod(x, y *Int) *Int {
+	if y.Sign() == 0 {
+		flint.Panic("fmpz.Int.Mod", flint.ErrDivisionByZero)
+	}
 	y0 := y // save y
 	if z == y {
 		y0 = new(Int).Set(y)
@@ -249,6 +331,9 @@ func (z *Int) Mod(x, y *Int) *Int {
 // See QuoRem for T-division and modulus (like Go).
 //
 func (z *Int) DivMod(x, y, m *Int) (*Int, *Int) {
+	if y.Sign() == 0 {
+		flint.Panic("fmpz.Int.DivMod", flint.ErrDivisionByZero)
+	}
 	C.fmpz_fdiv_qr((*C.fmpz)(z), (*C.fmpz)(m), (*C.fmpz)(x), (*C.fmpz)(y))
 	return z, m
 }
@@ -291,6 +376,9 @@ func (z *Int) GCD(x, y, a, b *Int) *Int {
 //   +1 if x >  y
 //
 func CmpInt(x, y *Int) int {
+	if r, ok := cmpSmall(x, y); ok {
+		return r
+	}
 	switch cmp := C.fmpz_cmp((*C.fmpz)(x), (*C.fmpz)(y)); {
 	case cmp < 0:
 		return -1
@@ -0,0 +1,88 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpz
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// With FLINT, BenchmarkMixed and the benchmarks ending in Small have
+// baselines in fmpz_small_bench_test.go, with FLINT appended to the
+// name, that always call into FLINT as the methods did before the
+// inline fast paths.  Compare them with
+//
+//	go test -run NONE -bench 'Small|Mixed' ./fmpz
+
+// benchVec returns n pseudo-random Ints below 2^20; if large is
+// not zero, every large-th of them is replaced by a 100-bit value.
+func benchVec(n, large int, seed int64) []*Int {
+	r := rand.New(rand.NewSource(seed))
+	xs := make([]*Int, n)
+	for i := range xs {
+		xs[i] = NewInt(r.Int63n(1<<21) - 1<<20)
+		if large != 0 && i%large == 0 {
+			xs[i].Lsh(xs[i], 80)
+		}
+	}
+	return xs
+}
+
+func benchmarkBinary(b *testing.B, large int, op func(z, x, y *Int) *Int) {
+	xs, ys := benchVec(1024, large, 1), benchVec(1024, large, 2)
+	z := NewInt(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		op(z, xs[i%1024], ys[i%1024])
+	}
+}
+
+func BenchmarkAddSmall(b *testing.B) { benchmarkBinary(b, 0, (*Int).Add) }
+func BenchmarkAddLarge(b *testing.B) { benchmarkBinary(b, 1, (*Int).Add) }
+func BenchmarkSubSmall(b *testing.B) { benchmarkBinary(b, 0, (*Int).Sub) }
+func BenchmarkMulSmall(b *testing.B) { benchmarkBinary(b, 0, (*Int).Mul) }
+func BenchmarkMulLarge(b *testing.B) { benchmarkBinary(b, 1, (*Int).Mul) }
+
+func benchmarkCmp(b *testing.B, cmp func(x, y *Int) int) {
+	xs, ys := benchVec(1024, 0, 1), benchVec(1024, 0, 2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cmp(xs[i%1024], ys[i%1024])
+	}
+}
+
+func benchmarkSign(b *testing.B, sign func(x *Int) int) {
+	xs := benchVec(1024, 0, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sign(xs[i%1024])
+	}
+}
+
+func BenchmarkCmpSmall(b *testing.B)  { benchmarkCmp(b, (*Int).Cmp) }
+func BenchmarkSignSmall(b *testing.B) { benchmarkSign(b, (*Int).Sign) }
+
+// benchmarkMixed is a typical inner loop: dot products of vectors
+// with mostly small entries and occasional large ones, counting the
+// positive partial sums.
+func benchmarkMixed(b *testing.B, add, mul func(z, x, y *Int) *Int, sign func(x *Int) int, cmp func(x, y *Int) int) {
+	xs, ys := benchVec(1024, 64, 1), benchVec(1024, 64, 2)
+	s, t := NewInt(0), NewInt(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.SetInt64(0)
+		n := 0
+		for j := range xs {
+			add(s, s, mul(t, xs[j], ys[j]))
+			if sign(s) > 0 && cmp(s, xs[j]) > 0 {
+				n++
+			}
+		}
+	}
+}
+
+func BenchmarkMixed(b *testing.B) {
+	benchmarkMixed(b, (*Int).Add, (*Int).Mul, (*Int).Sign, (*Int).Cmp)
+}
@@ -0,0 +1,106 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpz
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// DoubleFactorial sets z = n!!, the product of the positive integers
+// up to n with the same parity as n, and returns z.  0!! = 1.
+func (z *Int) DoubleFactorial(n uint64) *Int {
+	if n%2 == 0 {
+		// (2m)!! = 2^m m!
+		m := n / 2
+		z.Factorial(m)
+		return z.Mul2Exp(z, uint(m))
+	}
+	// (2m+1)!! = 1*3*...*(2m+1)
+	return z.oddProduct(1, n)
+}
+
+// oddProduct sets z to the product of the odd numbers a, a+2, ...,
+// b, for odd a <= b, by binary splitting, and returns z.
+func (z *Int) oddProduct(a, b uint64) *Int {
+	if b-a >= 64 {
+		m := a + (b-a)/4*2
+		t := new(Int).oddProduct(m+2, b)
+		return z.Mul(z.oddProduct(a, m), t)
+	}
+	// Collect factors in a word as long as they fit.
+	z.SetInt64(1)
+	t := new(Int)
+	acc := uint64(1)
+	for i := uint64(0); i <= (b-a)/2; i++ {
+		k := a + 2*i
+		if hi, lo := bits.Mul64(acc, k); hi == 0 {
+			acc = lo
+			continue
+		}
+		z.Mul(z, t.SetUint64(acc))
+		acc = k
+	}
+	return z.Mul(z, t.SetUint64(acc))
+}
+
+// FallingFactorial sets z to x(x-1)...(x-n+1) and returns z.  It is
+// 1 if n = 0.
+func (z *Int) FallingFactorial(x *Int, n uint64) *Int {
+	if n == 0 {
+		return z.SetInt64(1)
+	}
+	// x(x-1)...(x-n+1) = y(y+1)...(y+n-1) for y = x-n+1
+	y := new(Int).SetUint64(n - 1)
+	y.Sub(x, y)
+	return z.RisingFactorial(y, n)
+}
+
+// Binomial sets z to the binomial coefficient C(n, k) =
+// n(n-1)...(n-k+1) / k! and returns z.  n may be any integer; for
+// 0 <= n < k the result is 0.
+func (z *Int) Binomial(n *Int, k uint64) *Int {
+	f := new(Int).Factorial(k)
+	z.FallingFactorial(n, k)
+	return z.DivExact(z, f)
+}
+
+// Lucas sets z to the n-th Lucas number L(n), with L(0) = 2 and
+// L(1) = 1, and returns z.  For n = math.MaxUint64, Lucas panics
+// with flint.ErrOverflow.
+func (z *Int) Lucas(n uint64) *Int {
+	if n == math.MaxUint64 {
+		flint.Panic("fmpz.Int.Lucas", flint.ErrOverflow)
+	}
+	// L(n) = 2F(n+1) - F(n)
+	f := new(Int).Fibonacci(n)
+	z.Fibonacci(n + 1)
+	z.Mul2Exp(z, 1)
+	return z.Sub(z, f)
+}
+
+// Multinomial sets z to the multinomial coefficient
+// (k1+...+kr)! / (k1!...kr!) and returns z.  It is 1 if ks is
+// empty.  If k1+...+kr overflows a uint64, Multinomial panics with
+// flint.ErrOverflow.
+func (z *Int) Multinomial(ks ...uint64) *Int {
+	var s, c uint64
+	for _, k := range ks {
+		if s, c = bits.Add64(s, k, 0); c != 0 {
+			flint.Panic("fmpz.Int.Multinomial", flint.ErrOverflow)
+		}
+	}
+	// The product of C(k1+...+ki, ki) for i = 1, ..., r.
+	z.SetInt64(1)
+	t := new(Int)
+	s = 0
+	for _, k := range ks {
+		s += k
+		z.Mul(z, t.BinomialUint64(s, k))
+	}
+	return z
+}
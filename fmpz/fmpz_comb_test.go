@@ -0,0 +1,101 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpz
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+func TestCombinatorial(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		got  *Int
+		want string
+	}{
+		{"Factorial(0)", NewInt(-1).Factorial(0), "1"},
+		{"Factorial(10)", NewInt(0).Factorial(10), "3628800"},
+		{"Factorial(25)", NewInt(0).Factorial(25), "15511210043330985984000000"},
+		{"DoubleFactorial(0)", NewInt(0).DoubleFactorial(0), "1"},
+		{"DoubleFactorial(1)", NewInt(0).DoubleFactorial(1), "1"},
+		{"DoubleFactorial(7)", NewInt(0).DoubleFactorial(7), "105"},
+		{"DoubleFactorial(8)", NewInt(0).DoubleFactorial(8), "384"},
+		{"DoubleFactorial(31)", NewInt(0).DoubleFactorial(31), "191898783962510625"},
+		{"DoubleFactorial(51)", NewInt(0).DoubleFactorial(51), "2980227913743310874726229193921875"},
+		{"BinomialUint64(52, 5)", NewInt(0).BinomialUint64(52, 5), "2598960"},
+		{"BinomialUint64(100, 50)", NewInt(0).BinomialUint64(100, 50), "100891344545564193334812497256"},
+		{"BinomialUint64(3, 5)", NewInt(0).BinomialUint64(3, 5), "0"},
+		{"BinomialUint64(2^63+5, 1)", NewInt(0).BinomialUint64(1<<63+5, 1), "9223372036854775813"},
+		{"BinomialUint64(2^63+5, 2^63+4)", NewInt(0).BinomialUint64(1<<63+5, 1<<63+4), "9223372036854775813"},
+		{"BinomialUint64(2^64-1, 2)", NewInt(0).BinomialUint64(math.MaxUint64, 2), "170141183460469231704017187605319778305"},
+		{"BinomialUint64(2^64-1, 2^64-1)", NewInt(0).BinomialUint64(math.MaxUint64, math.MaxUint64), "1"},
+		{"Binomial(52, 5)", NewInt(0).Binomial(NewInt(52), 5), "2598960"},
+		{"Binomial(3, 5)", NewInt(0).Binomial(NewInt(3), 5), "0"},
+		{"Binomial(-1, 2)", NewInt(0).Binomial(NewInt(-1), 2), "1"},
+		{"Binomial(-5, 3)", NewInt(0).Binomial(NewInt(-5), 3), "-35"},
+		{"Binomial(7, 0)", NewInt(0).Binomial(NewInt(7), 0), "1"},
+		{"RisingFactorial(3, 4)", NewInt(0).RisingFactorial(NewInt(3), 4), "360"},
+		{"RisingFactorial(-3, 3)", NewInt(0).RisingFactorial(NewInt(-3), 3), "-6"},
+		{"RisingFactorial(-3, 4)", NewInt(0).RisingFactorial(NewInt(-3), 4), "0"},
+		{"RisingFactorial(9, 0)", NewInt(0).RisingFactorial(NewInt(9), 0), "1"},
+		{"FallingFactorial(5, 3)", NewInt(0).FallingFactorial(NewInt(5), 3), "60"},
+		{"FallingFactorial(-2, 3)", NewInt(0).FallingFactorial(NewInt(-2), 3), "-24"},
+		{"FallingFactorial(9, 0)", NewInt(0).FallingFactorial(NewInt(9), 0), "1"},
+		{"Fibonacci(0)", NewInt(0).Fibonacci(0), "0"},
+		{"Fibonacci(1)", NewInt(0).Fibonacci(1), "1"},
+		{"Fibonacci(10)", NewInt(0).Fibonacci(10), "55"},
+		{"Fibonacci(100)", NewInt(0).Fibonacci(100), "354224848179261915075"},
+		{"Lucas(0)", NewInt(0).Lucas(0), "2"},
+		{"Lucas(1)", NewInt(0).Lucas(1), "1"},
+		{"Lucas(10)", NewInt(0).Lucas(10), "123"},
+		{"Lucas(100)", NewInt(0).Lucas(100), "792070839848372253127"},
+		{"Primorial(0)", NewInt(0).Primorial(0), "1"},
+		{"Primorial(2)", NewInt(0).Primorial(2), "2"},
+		{"Primorial(10)", NewInt(0).Primorial(10), "210"},
+		{"Primorial(30)", NewInt(0).Primorial(30), "6469693230"},
+		{"Multinomial()", NewInt(0).Multinomial(), "1"},
+		{"Multinomial(5)", NewInt(0).Multinomial(5), "1"},
+		{"Multinomial(1, 2^63)", NewInt(0).Multinomial(1, 1<<63), "9223372036854775809"},
+		{"Multinomial(2, 3, 4)", NewInt(0).Multinomial(2, 3, 4), "1260"},
+		{"Multinomial(1, 0, 1)", NewInt(0).Multinomial(1, 0, 1), "2"},
+	} {
+		if s := tc.got.String(); s != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, s, tc.want)
+		}
+	}
+
+	// Pascal's rule for a negative upper index.
+	n := NewInt(-7)
+	for k := uint64(1); k < 10; k++ {
+		a := NewInt(0).Binomial(n, k)
+		b := NewInt(0).Binomial(NewInt(0).SubInt64(n, 1), k-1)
+		c := NewInt(0).Binomial(NewInt(0).SubInt64(n, 1), k)
+		if a.Cmp(b.Add(b, c)) != 0 {
+			t.Errorf("Binomial(%v, %d) = %v, want %v", n, k, a, b)
+		}
+	}
+
+	// (2m+1)!! = (2m+1)! / (2^m m!), across several binary splits.
+	for _, n := range []uint64{63, 65, 127, 129, 1001} {
+		d := NewInt(0).Factorial(n / 2)
+		d.Mul2Exp(d, uint(n/2))
+		want := NewInt(0).DivExact(NewInt(0).Factorial(n), d)
+		if z := NewInt(0).DoubleFactorial(n); z.Cmp(want) != 0 {
+			t.Errorf("DoubleFactorial(%d) = %v, want %v", n, z, want)
+		}
+	}
+
+	for name, f := range map[string]func(){
+		"Lucas(MaxUint64)":          func() { NewInt(0).Lucas(math.MaxUint64) },
+		"Multinomial(1, MaxUint64)": func() { NewInt(0).Multinomial(1, math.MaxUint64) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrOverflow) {
+			t.Errorf("%s: err = %v, want ErrOverflow", name, err)
+		}
+	}
+}
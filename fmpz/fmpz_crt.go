@@ -0,0 +1,150 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpz
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_vec.h>
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/internal/util"
+)
+
+// SymMod sets z to the symmetric remainder of x modulo m, the
+// unique value congruent to x with -|m|/2 < z <= |m|/2, and returns
+// z.  If m == 0, a division-by-zero run-time panic occurs.
+func (z *Int) SymMod(x, m *Int) *Int {
+	if m.Sign() == 0 {
+		flint.Panic("fmpz.Int.SymMod", flint.ErrDivisionByZero)
+	}
+	C.fmpz_smod((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(m))
+	return z
+}
+
+// CRT sets z to the unique integer congruent to r1 modulo m1 and to
+// r2 modulo m2, taken from [0, m1*m2), or from the symmetric range
+// (-m1*m2/2, m1*m2/2] if symmetric is true, and returns z.  The
+// moduli must be positive and coprime; otherwise CRT returns false.
+func (z *Int) CRT(r1, m1, r2, m2 *Int, symmetric bool) (*Int, bool) {
+	if m1.Sign() <= 0 || m2.Sign() <= 0 {
+		return z, false
+	}
+	if NewInt(0).GCD(nil, nil, m1, m2).Cmp(NewInt(1)) != 0 {
+		return z, false
+	}
+	a := NewInt(0).Mod(r1, m1)
+	b := NewInt(0).Mod(r2, m2)
+	m := NewInt(0).Mul(m1, m2)
+	// fmpz_CRT requires moduli greater than 1.
+	switch {
+	case C.fmpz_is_one((*C.fmpz)(m1)) != 0:
+		z.Set(b)
+	case C.fmpz_is_one((*C.fmpz)(m2)) != 0:
+		z.Set(a)
+	default:
+		t := NewInt(0)
+		C.fmpz_CRT((*C.fmpz)(t), (*C.fmpz)(a), (*C.fmpz)(m1), (*C.fmpz)(b), (*C.fmpz)(m2), 0)
+		z.Set(t)
+	}
+	if symmetric {
+		z.SymMod(z, m)
+	}
+	return z, true
+}
+
+// A CRTPlan holds the data for combining residues modulo a fixed
+// list of pairwise coprime moduli, so that many residue vectors can
+// be lifted without repeating the precomputation.
+type CRTPlan struct {
+	p C.fmpz_multi_CRT_struct
+	n int
+	m *Int
+}
+
+// NewCRTPlan returns a plan for the moduli ms.  The moduli must be
+// positive and pairwise coprime; otherwise NewCRTPlan returns
+// false.
+func NewCRTPlan(ms []*Int) (*CRTPlan, bool) {
+	if len(ms) == 0 {
+		return nil, false
+	}
+	m := NewInt(1)
+	for _, x := range ms {
+		if x.Sign() <= 0 {
+			return nil, false
+		}
+		m.Mul(m, x)
+	}
+
+	c := &CRTPlan{n: len(ms), m: m}
+	C.fmpz_multi_CRT_init(&c.p)
+	runtime.SetFinalizer(c, (*CRTPlan).destroy)
+	v := newVec(ms)
+	defer C._fmpz_vec_clear(v, C.slong(len(ms)))
+	if C.fmpz_multi_CRT_precompute(&c.p, v, C.slong(len(ms))) == 0 {
+		return nil, false
+	}
+	return c, true
+}
+
+func (c *CRTPlan) destroy() {
+	C.fmpz_multi_CRT_clear(&c.p)
+}
+
+// Modulus returns the product of the moduli of c.
+func (c *CRTPlan) Modulus() *Int {
+	return NewInt(0).Set(c.m)
+}
+
+// Lift sets z to the unique integer congruent to xs[i] modulo the
+// i-th modulus of c for every i, taken from [0, M) or, if symmetric
+// is true, from (-M/2, M/2], where M is c.Modulus(), and returns z.
+func (c *CRTPlan) Lift(z *Int, xs []*Int, symmetric bool) *Int {
+	util.CheckLen("fmpz", c.n, len(xs))
+	v := newVec(xs)
+	defer C._fmpz_vec_clear(v, C.slong(len(xs)))
+	sign := C.int(0)
+	if symmetric {
+		sign = 1
+	}
+	C.fmpz_multi_CRT_precomp((*C.fmpz)(z), &c.p, v, sign)
+	if !symmetric {
+		// Normalise whatever representative the plan chose.
+		z.Mod(z, c.m)
+	}
+	return z
+}
+
+// MultiCRT returns the unique integer congruent to xs[i] modulo
+// ms[i] for every i, as CRT does for two moduli.  The moduli must
+// be positive and pairwise coprime; otherwise MultiCRT returns
+// false.
+func MultiCRT(ms, xs []*Int, symmetric bool) (*Int, bool) {
+	util.CheckLen("fmpz", len(ms), len(xs))
+	c, ok := NewCRTPlan(ms)
+	if !ok {
+		return nil, false
+	}
+	return c.Lift(NewInt(0), xs, symmetric), true
+}
+
+// newVec returns a FLINT vector holding copies of xs.  It must be
+// released with _fmpz_vec_clear.
+func newVec(xs []*Int) *C.fmpz {
+	v := C._fmpz_vec_init(C.slong(len(xs)))
+	a := unsafe.Slice(v, len(xs))
+	for i, x := range xs {
+		C.fmpz_set(&a[i], (*C.fmpz)(x))
+	}
+	return v
+}
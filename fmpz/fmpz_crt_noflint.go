@@ -0,0 +1,123 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpz
+
+import (
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/internal/util"
+)
+
+// SymMod sets z to the symmetric remainder of x modulo m, the
+// unique value congruent to x with -|m|/2 < z <= |m|/2, and returns
+// z.  If m == 0, a division-by-zero run-time panic occurs.
+func (z *Int) SymMod(x, m *Int) *Int {
+	if m.Sign() == 0 {
+		flint.Panic("fmpz.Int.SymMod", flint.ErrDivisionByZero)
+	}
+	a := new(Int).Abs(m)
+	z.Mod(x, a)
+	if h := new(Int).Rsh(a, 1); z.Cmp(h) > 0 {
+		z.Sub(z, a)
+	}
+	return z
+}
+
+// CRT sets z to the unique integer congruent to r1 modulo m1 and to
+// r2 modulo m2, taken from [0, m1*m2), or from the symmetric range
+// (-m1*m2/2, m1*m2/2] if symmetric is true, and returns z.  The
+// moduli must be positive and coprime; otherwise CRT returns false.
+func (z *Int) CRT(r1, m1, r2, m2 *Int, symmetric bool) (*Int, bool) {
+	if m1.Sign() <= 0 || m2.Sign() <= 0 {
+		return z, false
+	}
+	u := NewInt(0)
+	if NewInt(0).GCD(u, nil, m1, m2).Cmp(NewInt(1)) != 0 {
+		return z, false
+	}
+	// z = r1 + m1*((r2 - r1)*m1^-1 mod m2)
+	a := NewInt(0).Mod(r1, m1)
+	t := NewInt(0).Sub(r2, a)
+	t.Mul(t, u).Mod(t, m2)
+	z.Mul(t, m1).Add(z, a)
+	if symmetric {
+		z.SymMod(z, NewInt(0).Mul(m1, m2))
+	}
+	return z, true
+}
+
+// A CRTPlan holds the data for combining residues modulo a fixed
+// list of pairwise coprime moduli, so that many residue vectors can
+// be lifted without repeating the precomputation.
+type CRTPlan struct {
+	ms []*Int
+	cs []*Int // cs[i] = 1 mod ms[i], 0 mod ms[j] for j != i
+	m  *Int
+}
+
+// NewCRTPlan returns a plan for the moduli ms.  The moduli must be
+// positive and pairwise coprime; otherwise NewCRTPlan returns
+// false.
+func NewCRTPlan(ms []*Int) (*CRTPlan, bool) {
+	if len(ms) == 0 {
+		return nil, false
+	}
+	m := NewInt(1)
+	for _, x := range ms {
+		if x.Sign() <= 0 {
+			return nil, false
+		}
+		m.Mul(m, x)
+	}
+
+	c := &CRTPlan{m: m}
+	one := NewInt(1)
+	for _, x := range ms {
+		// The cofactor m/x must be invertible modulo x.
+		e, u := NewInt(0).Div(m, x), NewInt(0)
+		if x.Cmp(one) != 0 && NewInt(0).GCD(u, nil, NewInt(0).Mod(e, x), x).Cmp(one) != 0 {
+			return nil, false
+		}
+		c.ms = append(c.ms, NewInt(0).Set(x))
+		c.cs = append(c.cs, e.Mul(e, u.Mod(u, x)))
+	}
+	return c, true
+}
+
+// Modulus returns the product of the moduli of c.
+func (c *CRTPlan) Modulus() *Int {
+	return NewInt(0).Set(c.m)
+}
+
+// Lift sets z to the unique integer congruent to xs[i] modulo the
+// i-th modulus of c for every i, taken from [0, M) or, if symmetric
+// is true, from (-M/2, M/2], where M is c.Modulus(), and returns z.
+func (c *CRTPlan) Lift(z *Int, xs []*Int, symmetric bool) *Int {
+	util.CheckLen("fmpz", len(c.ms), len(xs))
+	s, t := NewInt(0), NewInt(0)
+	for i, x := range xs {
+		t.Mod(x, c.ms[i])
+		s.Add(s, t.Mul(t, c.cs[i]))
+	}
+	z.Mod(s, c.m)
+	if symmetric {
+		z.SymMod(z, c.m)
+	}
+	return z
+}
+
+// MultiCRT returns the unique integer congruent to xs[i] modulo
+// ms[i] for every i, as CRT does for two moduli.  The moduli must
+// be positive and pairwise coprime; otherwise MultiCRT returns
+// false.
+func MultiCRT(ms, xs []*Int, symmetric bool) (*Int, bool) {
+	util.CheckLen("fmpz", len(ms), len(xs))
+	c, ok := NewCRTPlan(ms)
+	if !ok {
+		return nil, false
+	}
+	return c.Lift(NewInt(0), xs, symmetric), true
+}
@@ -0,0 +1,395 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpz
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+//
+// // goflint_fmpz_divrem sets q and r to the quotient and remainder
+// // of x by y, with the quotient rounded down (mode 'f'), up ('c') or
+// // toward zero ('t').  Either q or r may be NULL.
+// static void goflint_fmpz_divrem(fmpz_t q, fmpz_t r, const fmpz_t x, const fmpz_t y, char mode)
+// {
+// 	fmpz_t t, u;
+// 	fmpz_init(t);
+// 	fmpz_init(u);
+// 	switch (mode) {
+// 	case 'f':
+// 		fmpz_fdiv_qr(t, u, x, y);
+// 		break;
+// 	case 't':
+// 		fmpz_tdiv_qr(t, u, x, y);
+// 		break;
+// 	default:
+// 		fmpz_cdiv_q(t, x, y);
+// 		fmpz_mul(u, t, y);
+// 		fmpz_sub(u, x, u);
+// 	}
+// 	if (q != NULL)
+// 		fmpz_swap(q, t);
+// 	if (r != NULL)
+// 		fmpz_swap(r, u);
+// 	fmpz_clear(t);
+// 	fmpz_clear(u);
+// }
+//
+// static slong goflint_fmpz_rem_si(const fmpz_t x, slong h, char mode)
+// {
+// 	fmpz_t y, r;
+// 	slong s;
+// 	fmpz_init(y);
+// 	fmpz_init(r);
+// 	fmpz_set_si(y, h);
+// 	goflint_fmpz_divrem(NULL, r, x, y, mode);
+// 	s = fmpz_get_si(r);
+// 	fmpz_clear(y);
+// 	fmpz_clear(r);
+// 	return s;
+// }
+//
+// // goflint_fmpz_rem_2exp sets r = x - q*2^n for the quotient q of
+// // x by 2^n rounded as in goflint_fmpz_divrem.
+// static void goflint_fmpz_rem_2exp(fmpz_t r, const fmpz_t x, ulong n, char mode)
+// {
+// 	fmpz_t q;
+// 	fmpz_init(q);
+// 	switch (mode) {
+// 	case 'f':
+// 		fmpz_fdiv_q_2exp(q, x, n);
+// 		break;
+// 	case 't':
+// 		fmpz_tdiv_q_2exp(q, x, n);
+// 		break;
+// 	default:
+// 		fmpz_cdiv_q_2exp(q, x, n);
+// 	}
+// 	fmpz_mul_2exp(q, q, n);
+// 	fmpz_sub(r, x, q);
+// 	fmpz_clear(q);
+// }
+import "C"
+
+import (
+	"math"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// The division functions come in three rounding modes, named after
+// FLINT's: F rounds the quotient toward minus infinity (floor), C
+// toward plus infinity (ceiling) and T toward zero (truncation).
+// A non-zero remainder r = x - q*y has the sign of y for F, of -y
+// for C and of x for T.  Div is TDiv; Mod and DivMod are Euclidean
+// and floor division, see there.
+//
+// If the divisor is 0, a division-by-zero run-time panic occurs.
+
+func checkDivisor(op string, zero bool) {
+	if zero {
+		flint.Panic("fmpz.Int."+op, flint.ErrDivisionByZero)
+	}
+}
+
+// FDiv sets z to the quotient x/y rounded toward minus infinity and
+// returns z.
+func (z *Int) FDiv(x, y *Int) *Int {
+	checkDivisor("FDiv", y.Sign() == 0)
+	C.fmpz_fdiv_q((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+// CDiv sets z to the quotient x/y rounded toward plus infinity and
+// returns z.
+func (z *Int) CDiv(x, y *Int) *Int {
+	checkDivisor("CDiv", y.Sign() == 0)
+	C.fmpz_cdiv_q((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+// TDiv sets z to the quotient x/y rounded toward zero and returns z.
+func (z *Int) TDiv(x, y *Int) *Int {
+	checkDivisor("TDiv", y.Sign() == 0)
+	C.fmpz_tdiv_q((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+// FRem sets z to the remainder of FDiv(x, y) and returns z.
+func (z *Int) FRem(x, y *Int) *Int {
+	checkDivisor("FRem", y.Sign() == 0)
+	C.fmpz_fdiv_r((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+// CRem sets z to the remainder of CDiv(x, y) and returns z.
+func (z *Int) CRem(x, y *Int) *Int {
+	checkDivisor("CRem", y.Sign() == 0)
+	C.goflint_fmpz_divrem(nil, (*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y), 'c')
+	return z
+}
+
+// TRem sets z to the remainder of TDiv(x, y) and returns z.
+func (z *Int) TRem(x, y *Int) *Int {
+	checkDivisor("TRem", y.Sign() == 0)
+	C.goflint_fmpz_divrem(nil, (*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y), 't')
+	return z
+}
+
+// FDivRem sets z to FDiv(x, y) and r to FRem(x, y) and returns the
+// pair (z, r).  z and r must be distinct.
+func (z *Int) FDivRem(x, y, r *Int) (*Int, *Int) {
+	checkDivisor("FDivRem", y.Sign() == 0)
+	C.goflint_fmpz_divrem((*C.fmpz)(z), (*C.fmpz)(r), (*C.fmpz)(x), (*C.fmpz)(y), 'f')
+	return z, r
+}
+
+// CDivRem sets z to CDiv(x, y) and r to CRem(x, y) and returns the
+// pair (z, r).  z and r must be distinct.
+func (z *Int) CDivRem(x, y, r *Int) (*Int, *Int) {
+	checkDivisor("CDivRem", y.Sign() == 0)
+	C.goflint_fmpz_divrem((*C.fmpz)(z), (*C.fmpz)(r), (*C.fmpz)(x), (*C.fmpz)(y), 'c')
+	return z, r
+}
+
+// TDivRem sets z to TDiv(x, y) and r to TRem(x, y) and returns the
+// pair (z, r).  z and r must be distinct.
+func (z *Int) TDivRem(x, y, r *Int) (*Int, *Int) {
+	checkDivisor("TDivRem", y.Sign() == 0)
+	C.goflint_fmpz_divrem((*C.fmpz)(z), (*C.fmpz)(r), (*C.fmpz)(x), (*C.fmpz)(y), 't')
+	return z, r
+}
+
+// FDivInt64 sets z to the quotient x/y rounded toward minus infinity
+// and returns z.
+func (z *Int) FDivInt64(x *Int, y int64) *Int {
+	checkDivisor("FDivInt64", y == 0)
+	if a, ok := x.small(); ok {
+		q, _ := divInline(a, y, 'f')
+		return z.setSmall(q)
+	}
+	C.fmpz_fdiv_q_si((*C.fmpz)(z), (*C.fmpz)(x), C.slong(y))
+	return z
+}
+
+// CDivInt64 sets z to the quotient x/y rounded toward plus infinity
+// and returns z.
+func (z *Int) CDivInt64(x *Int, y int64) *Int {
+	checkDivisor("CDivInt64", y == 0)
+	if a, ok := x.small(); ok {
+		q, _ := divInline(a, y, 'c')
+		return z.setSmall(q)
+	}
+	C.fmpz_cdiv_q_si((*C.fmpz)(z), (*C.fmpz)(x), C.slong(y))
+	return z
+}
+
+// TDivInt64 sets z to the quotient x/y rounded toward zero and
+// returns z.
+func (z *Int) TDivInt64(x *Int, y int64) *Int {
+	checkDivisor("TDivInt64", y == 0)
+	if a, ok := x.small(); ok {
+		q, _ := divInline(a, y, 't')
+		return z.setSmall(q)
+	}
+	C.fmpz_tdiv_q_si((*C.fmpz)(z), (*C.fmpz)(x), C.slong(y))
+	return z
+}
+
+// FRemInt64 returns the remainder of z divided by y, with the
+// quotient rounded toward minus infinity.
+func (z *Int) FRemInt64(y int64) int64 {
+	return z.remInt64("FRemInt64", y, 'f')
+}
+
+// CRemInt64 returns the remainder of z divided by y, with the
+// quotient rounded toward plus infinity.
+func (z *Int) CRemInt64(y int64) int64 {
+	return z.remInt64("CRemInt64", y, 'c')
+}
+
+// TRemInt64 returns the remainder of z divided by y, with the
+// quotient rounded toward zero.
+func (z *Int) TRemInt64(y int64) int64 {
+	return z.remInt64("TRemInt64", y, 't')
+}
+
+func (z *Int) remInt64(op string, y int64, mode byte) int64 {
+	checkDivisor(op, y == 0)
+	if a, ok := z.small(); ok {
+		_, r := divInline(a, y, mode)
+		return r
+	}
+	return int64(C.goflint_fmpz_rem_si((*C.fmpz)(z), C.slong(y), C.char(mode)))
+}
+
+// FDivUint64 sets z to the quotient x/y rounded toward minus
+// infinity and returns z.
+func (z *Int) FDivUint64(x *Int, y uint64) *Int {
+	checkDivisor("FDivUint64", y == 0)
+	if a, ok := x.small(); ok && y <= math.MaxInt64 {
+		q, _ := divInline(a, int64(y), 'f')
+		return z.setSmall(q)
+	}
+	C.fmpz_fdiv_q_ui((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(y))
+	return z
+}
+
+// CDivUint64 sets z to the quotient x/y rounded toward plus infinity
+// and returns z.
+func (z *Int) CDivUint64(x *Int, y uint64) *Int {
+	checkDivisor("CDivUint64", y == 0)
+	if a, ok := x.small(); ok && y <= math.MaxInt64 {
+		q, _ := divInline(a, int64(y), 'c')
+		return z.setSmall(q)
+	}
+	C.fmpz_cdiv_q_ui((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(y))
+	return z
+}
+
+// TDivUint64 sets z to the quotient x/y rounded toward zero and
+// returns z.
+func (z *Int) TDivUint64(x *Int, y uint64) *Int {
+	checkDivisor("TDivUint64", y == 0)
+	if a, ok := x.small(); ok && y <= math.MaxInt64 {
+		q, _ := divInline(a, int64(y), 't')
+		return z.setSmall(q)
+	}
+	C.fmpz_tdiv_q_ui((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(y))
+	return z
+}
+
+// FRemUint64 returns z mod y, the remainder of z divided by y with
+// the quotient rounded toward minus infinity, which lies in [0, y).
+func (z *Int) FRemUint64(y uint64) uint64 {
+	checkDivisor("FRemUint64", y == 0)
+	if a, ok := z.small(); ok && y <= math.MaxInt64 {
+		_, r := divInline(a, int64(y), 'f')
+		return uint64(r)
+	}
+	return uint64(C.fmpz_fdiv_ui((*C.fmpz)(z), C.ulong(y)))
+}
+
+// DivExact sets z = x/y and returns z.  y must divide x; otherwise
+// the result is undefined.  DivExact is faster than the other
+// divisions; see DivExactChecked for a safe variant.
+func (z *Int) DivExact(x, y *Int) *Int {
+	checkDivisor("DivExact", y.Sign() == 0)
+	C.fmpz_divexact((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+// DivExactChecked sets z = x/y and returns z and true if y divides
+// x.  Otherwise it sets z to 0 and returns false.
+func (z *Int) DivExactChecked(x, y *Int) (*Int, bool) {
+	checkDivisor("DivExactChecked", y.Sign() == 0)
+	ok := C.fmpz_divides((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y)) != 0
+	return z, ok
+}
+
+// DivExactInt64 sets z = x/y and returns z.  y must divide x;
+// otherwise the result is undefined.
+func (z *Int) DivExactInt64(x *Int, y int64) *Int {
+	checkDivisor("DivExactInt64", y == 0)
+	C.fmpz_divexact_si((*C.fmpz)(z), (*C.fmpz)(x), C.slong(y))
+	return z
+}
+
+// DivExactUint64 sets z = x/y and returns z.  y must divide x;
+// otherwise the result is undefined.
+func (z *Int) DivExactUint64(x *Int, y uint64) *Int {
+	checkDivisor("DivExactUint64", y == 0)
+	C.fmpz_divexact_ui((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(y))
+	return z
+}
+
+// AddMul sets z = z + x*y and returns z.
+func (z *Int) AddMul(x, y *Int) *Int {
+	C.fmpz_addmul((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+// SubMul sets z = z - x*y and returns z.
+func (z *Int) SubMul(x, y *Int) *Int {
+	C.fmpz_submul((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+// MulInt64 sets z = x*y and returns z.
+func (z *Int) MulInt64(x *Int, y int64) *Int {
+	if a, ok := x.small(); ok {
+		if r, ok := mulInline(a, y); ok {
+			return z.setSmall(r)
+		}
+	}
+	C.fmpz_mul_si((*C.fmpz)(z), (*C.fmpz)(x), C.slong(y))
+	return z
+}
+
+// AddInt64 sets z = x + y and returns z.
+func (z *Int) AddInt64(x *Int, y int64) *Int {
+	if a, ok := x.small(); ok && -coeffMax <= y && y <= coeffMax {
+		return z.setSmall(a + y)
+	}
+	C.fmpz_add_si((*C.fmpz)(z), (*C.fmpz)(x), C.slong(y))
+	return z
+}
+
+// SubInt64 sets z = x - y and returns z.
+func (z *Int) SubInt64(x *Int, y int64) *Int {
+	if a, ok := x.small(); ok && -coeffMax <= y && y <= coeffMax {
+		return z.setSmall(a - y)
+	}
+	C.fmpz_sub_si((*C.fmpz)(z), (*C.fmpz)(x), C.slong(y))
+	return z
+}
+
+// Mul2Exp sets z = x * 2^n and returns z.  It is the same as Lsh.
+func (z *Int) Mul2Exp(x *Int, n uint) *Int {
+	C.fmpz_mul_2exp((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(n))
+	return z
+}
+
+// FDiv2Exp sets z to x / 2^n rounded toward minus infinity and
+// returns z.  It is the same as Rsh.
+func (z *Int) FDiv2Exp(x *Int, n uint) *Int {
+	C.fmpz_fdiv_q_2exp((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(n))
+	return z
+}
+
+// CDiv2Exp sets z to x / 2^n rounded toward plus infinity and
+// returns z.
+func (z *Int) CDiv2Exp(x *Int, n uint) *Int {
+	C.fmpz_cdiv_q_2exp((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(n))
+	return z
+}
+
+// TDiv2Exp sets z to x / 2^n rounded toward zero and returns z.
+func (z *Int) TDiv2Exp(x *Int, n uint) *Int {
+	C.fmpz_tdiv_q_2exp((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(n))
+	return z
+}
+
+// FRem2Exp sets z to the remainder of FDiv2Exp(x, n), which lies in
+// [0, 2^n), and returns z.
+func (z *Int) FRem2Exp(x *Int, n uint) *Int {
+	C.fmpz_fdiv_r_2exp((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(n))
+	return z
+}
+
+// CRem2Exp sets z to the remainder of CDiv2Exp(x, n), which lies in
+// (-2^n, 0], and returns z.
+func (z *Int) CRem2Exp(x *Int, n uint) *Int {
+	C.goflint_fmpz_rem_2exp((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(n), 'c')
+	return z
+}
+
+// TRem2Exp sets z to the remainder of TDiv2Exp(x, n), which has the
+// sign of x, and returns z.
+func (z *Int) TRem2Exp(x *Int, n uint) *Int {
+	C.goflint_fmpz_rem_2exp((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(n), 't')
+	return z
+}
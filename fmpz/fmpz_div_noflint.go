@@ -0,0 +1,323 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpz
+
+import (
+	"math/big"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// The division functions come in three rounding modes, named after
+// FLINT's: F rounds the quotient toward minus infinity (floor), C
+// toward plus infinity (ceiling) and T toward zero (truncation).
+// A non-zero remainder r = x - q*y has the sign of y for F, of -y
+// for C and of x for T.  Div is TDiv; Mod and DivMod are Euclidean
+// and floor division, see there.
+//
+// If the divisor is 0, a division-by-zero run-time panic occurs.
+
+func checkDivisor(op string, zero bool) {
+	if zero {
+		flint.Panic("fmpz.Int."+op, flint.ErrDivisionByZero)
+	}
+}
+
+// divRem sets q and r to the quotient and remainder of x by y,
+// with the quotient rounded down (mode 'f'), up ('c') or toward
+// zero ('t').  Either q or r may be nil.
+func divRem(q, r, x, y *big.Int, mode byte) {
+	if q == nil {
+		q = new(big.Int)
+	}
+	if r == nil {
+		r = new(big.Int)
+	}
+	y = new(big.Int).Set(y)
+	q.QuoRem(x, y, r)
+	if r.Sign() == 0 {
+		return
+	}
+	switch {
+	case mode == 'f' && r.Sign() != y.Sign():
+		q.Sub(q, big.NewInt(1))
+		r.Add(r, y)
+	case mode == 'c' && r.Sign() == y.Sign():
+		q.Add(q, big.NewInt(1))
+		r.Sub(r, y)
+	}
+}
+
+// FDiv sets z to the quotient x/y rounded toward minus infinity and
+// returns z.
+func (z *Int) FDiv(x, y *Int) *Int {
+	checkDivisor("FDiv", y.Sign() == 0)
+	divRem(z.big(), nil, x.big(), y.big(), 'f')
+	return z
+}
+
+// CDiv sets z to the quotient x/y rounded toward plus infinity and
+// returns z.
+func (z *Int) CDiv(x, y *Int) *Int {
+	checkDivisor("CDiv", y.Sign() == 0)
+	divRem(z.big(), nil, x.big(), y.big(), 'c')
+	return z
+}
+
+// TDiv sets z to the quotient x/y rounded toward zero and returns z.
+func (z *Int) TDiv(x, y *Int) *Int {
+	checkDivisor("TDiv", y.Sign() == 0)
+	z.big().Quo(x.big(), y.big())
+	return z
+}
+
+// FRem sets z to the remainder of FDiv(x, y) and returns z.
+func (z *Int) FRem(x, y *Int) *Int {
+	checkDivisor("FRem", y.Sign() == 0)
+	divRem(nil, z.big(), x.big(), y.big(), 'f')
+	return z
+}
+
+// CRem sets z to the remainder of CDiv(x, y) and returns z.
+func (z *Int) CRem(x, y *Int) *Int {
+	checkDivisor("CRem", y.Sign() == 0)
+	divRem(nil, z.big(), x.big(), y.big(), 'c')
+	return z
+}
+
+// TRem sets z to the remainder of TDiv(x, y) and returns z.
+func (z *Int) TRem(x, y *Int) *Int {
+	checkDivisor("TRem", y.Sign() == 0)
+	z.big().Rem(x.big(), y.big())
+	return z
+}
+
+// FDivRem sets z to FDiv(x, y) and r to FRem(x, y) and returns the
+// pair (z, r).  z and r must be distinct.
+func (z *Int) FDivRem(x, y, r *Int) (*Int, *Int) {
+	checkDivisor("FDivRem", y.Sign() == 0)
+	divRem(z.big(), r.big(), x.big(), y.big(), 'f')
+	return z, r
+}
+
+// CDivRem sets z to CDiv(x, y) and r to CRem(x, y) and returns the
+// pair (z, r).  z and r must be distinct.
+func (z *Int) CDivRem(x, y, r *Int) (*Int, *Int) {
+	checkDivisor("CDivRem", y.Sign() == 0)
+	divRem(z.big(), r.big(), x.big(), y.big(), 'c')
+	return z, r
+}
+
+// TDivRem sets z to TDiv(x, y) and r to TRem(x, y) and returns the
+// pair (z, r).  z and r must be distinct.
+func (z *Int) TDivRem(x, y, r *Int) (*Int, *Int) {
+	checkDivisor("TDivRem", y.Sign() == 0)
+	divRem(z.big(), r.big(), x.big(), y.big(), 't')
+	return z, r
+}
+
+// FDivInt64 sets z to the quotient x/y rounded toward minus infinity
+// and returns z.
+func (z *Int) FDivInt64(x *Int, y int64) *Int {
+	checkDivisor("FDivInt64", y == 0)
+	divRem(z.big(), nil, x.big(), big.NewInt(y), 'f')
+	return z
+}
+
+// CDivInt64 sets z to the quotient x/y rounded toward plus infinity
+// and returns z.
+func (z *Int) CDivInt64(x *Int, y int64) *Int {
+	checkDivisor("CDivInt64", y == 0)
+	divRem(z.big(), nil, x.big(), big.NewInt(y), 'c')
+	return z
+}
+
+// TDivInt64 sets z to the quotient x/y rounded toward zero and
+// returns z.
+func (z *Int) TDivInt64(x *Int, y int64) *Int {
+	checkDivisor("TDivInt64", y == 0)
+	divRem(z.big(), nil, x.big(), big.NewInt(y), 't')
+	return z
+}
+
+// FRemInt64 returns the remainder of z divided by y, with the
+// quotient rounded toward minus infinity.
+func (z *Int) FRemInt64(y int64) int64 {
+	return z.remInt64("FRemInt64", y, 'f')
+}
+
+// CRemInt64 returns the remainder of z divided by y, with the
+// quotient rounded toward plus infinity.
+func (z *Int) CRemInt64(y int64) int64 {
+	return z.remInt64("CRemInt64", y, 'c')
+}
+
+// TRemInt64 returns the remainder of z divided by y, with the
+// quotient rounded toward zero.
+func (z *Int) TRemInt64(y int64) int64 {
+	return z.remInt64("TRemInt64", y, 't')
+}
+
+func (z *Int) remInt64(op string, y int64, mode byte) int64 {
+	checkDivisor(op, y == 0)
+	r := new(big.Int)
+	divRem(nil, r, z.big(), big.NewInt(y), mode)
+	return r.Int64()
+}
+
+// FDivUint64 sets z to the quotient x/y rounded toward minus
+// infinity and returns z.
+func (z *Int) FDivUint64(x *Int, y uint64) *Int {
+	checkDivisor("FDivUint64", y == 0)
+	divRem(z.big(), nil, x.big(), new(big.Int).SetUint64(y), 'f')
+	return z
+}
+
+// CDivUint64 sets z to the quotient x/y rounded toward plus infinity
+// and returns z.
+func (z *Int) CDivUint64(x *Int, y uint64) *Int {
+	checkDivisor("CDivUint64", y == 0)
+	divRem(z.big(), nil, x.big(), new(big.Int).SetUint64(y), 'c')
+	return z
+}
+
+// TDivUint64 sets z to the quotient x/y rounded toward zero and
+// returns z.
+func (z *Int) TDivUint64(x *Int, y uint64) *Int {
+	checkDivisor("TDivUint64", y == 0)
+	divRem(z.big(), nil, x.big(), new(big.Int).SetUint64(y), 't')
+	return z
+}
+
+// FRemUint64 returns z mod y, the remainder of z divided by y with
+// the quotient rounded toward minus infinity, which lies in [0, y).
+func (z *Int) FRemUint64(y uint64) uint64 {
+	checkDivisor("FRemUint64", y == 0)
+	r := new(big.Int)
+	divRem(nil, r, z.big(), new(big.Int).SetUint64(y), 'f')
+	return r.Uint64()
+}
+
+// DivExact sets z = x/y and returns z.  y must divide x; otherwise
+// the result is undefined.  DivExact is faster than the other
+// divisions; see DivExactChecked for a safe variant.
+func (z *Int) DivExact(x, y *Int) *Int {
+	checkDivisor("DivExact", y.Sign() == 0)
+	z.big().Quo(x.big(), y.big())
+	return z
+}
+
+// DivExactChecked sets z = x/y and returns z and true if y divides
+// x.  Otherwise it sets z to 0 and returns false.
+func (z *Int) DivExactChecked(x, y *Int) (*Int, bool) {
+	checkDivisor("DivExactChecked", y.Sign() == 0)
+	r := new(big.Int)
+	z.big().QuoRem(x.big(), new(big.Int).Set(y.big()), r)
+	if r.Sign() != 0 {
+		z.SetInt64(0)
+		return z, false
+	}
+	return z, true
+}
+
+// DivExactInt64 sets z = x/y and returns z.  y must divide x;
+// otherwise the result is undefined.
+func (z *Int) DivExactInt64(x *Int, y int64) *Int {
+	checkDivisor("DivExactInt64", y == 0)
+	z.big().Quo(x.big(), big.NewInt(y))
+	return z
+}
+
+// DivExactUint64 sets z = x/y and returns z.  y must divide x;
+// otherwise the result is undefined.
+func (z *Int) DivExactUint64(x *Int, y uint64) *Int {
+	checkDivisor("DivExactUint64", y == 0)
+	z.big().Quo(x.big(), new(big.Int).SetUint64(y))
+	return z
+}
+
+// AddMul sets z = z + x*y and returns z.
+func (z *Int) AddMul(x, y *Int) *Int {
+	z.big().Add(z.big(), new(big.Int).Mul(x.big(), y.big()))
+	return z
+}
+
+// SubMul sets z = z - x*y and returns z.
+func (z *Int) SubMul(x, y *Int) *Int {
+	z.big().Sub(z.big(), new(big.Int).Mul(x.big(), y.big()))
+	return z
+}
+
+// MulInt64 sets z = x*y and returns z.
+func (z *Int) MulInt64(x *Int, y int64) *Int {
+	z.big().Mul(x.big(), big.NewInt(y))
+	return z
+}
+
+// AddInt64 sets z = x + y and returns z.
+func (z *Int) AddInt64(x *Int, y int64) *Int {
+	z.big().Add(x.big(), big.NewInt(y))
+	return z
+}
+
+// SubInt64 sets z = x - y and returns z.
+func (z *Int) SubInt64(x *Int, y int64) *Int {
+	z.big().Sub(x.big(), big.NewInt(y))
+	return z
+}
+
+// Mul2Exp sets z = x * 2^n and returns z.  It is the same as Lsh.
+func (z *Int) Mul2Exp(x *Int, n uint) *Int {
+	z.big().Lsh(x.big(), n)
+	return z
+}
+
+// FDiv2Exp sets z to x / 2^n rounded toward minus infinity and
+// returns z.  It is the same as Rsh.
+func (z *Int) FDiv2Exp(x *Int, n uint) *Int {
+	z.big().Rsh(x.big(), n)
+	return z
+}
+
+// CDiv2Exp sets z to x / 2^n rounded toward plus infinity and
+// returns z.
+func (z *Int) CDiv2Exp(x *Int, n uint) *Int {
+	// ceil(x/2^n) = -floor(-x/2^n)
+	z.big().Neg(x.big())
+	z.big().Rsh(z.big(), n)
+	z.big().Neg(z.big())
+	return z
+}
+
+// TDiv2Exp sets z to x / 2^n rounded toward zero and returns z.
+func (z *Int) TDiv2Exp(x *Int, n uint) *Int {
+	if x.Sign() < 0 {
+		return z.CDiv2Exp(x, n)
+	}
+	return z.FDiv2Exp(x, n)
+}
+
+// FRem2Exp sets z to the remainder of FDiv2Exp(x, n), which lies in
+// [0, 2^n), and returns z.
+func (z *Int) FRem2Exp(x *Int, n uint) *Int {
+	q := new(Int).FDiv2Exp(x, n)
+	return z.Sub(x, q.Mul2Exp(q, n))
+}
+
+// CRem2Exp sets z to the remainder of CDiv2Exp(x, n), which lies in
+// (-2^n, 0], and returns z.
+func (z *Int) CRem2Exp(x *Int, n uint) *Int {
+	q := new(Int).CDiv2Exp(x, n)
+	return z.Sub(x, q.Mul2Exp(q, n))
+}
+
+// TRem2Exp sets z to the remainder of TDiv2Exp(x, n), which has the
+// sign of x, and returns z.
+func (z *Int) TRem2Exp(x *Int, n uint) *Int {
+	q := new(Int).TDiv2Exp(x, n)
+	return z.Sub(x, q.Mul2Exp(q, n))
+}
@@ -0,0 +1,205 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpz
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+var divTests = []string{
+	"0", "1", "-1", "7", "-7", "12", "-12",
+	"4611686018427387903", "-4611686018427387904",
+	"9223372036854775807", "-9223372036854775808",
+	"123456789012345678901234567890", "-123456789012345678901234567890",
+}
+
+// checkDivision reports whether q and r are the quotient and
+// remainder of x by y in the given rounding mode.
+func checkDivision(x, y, q, r *Int, mode byte) bool {
+	t := NewInt(0).Mul(q, y)
+	if t.Add(t, r).Cmp(x) != 0 {
+		return false
+	}
+	if NewInt(0).Abs(r).Cmp(NewInt(0).Abs(y)) >= 0 {
+		return false
+	}
+	if r.Sign() == 0 {
+		return true
+	}
+	switch mode {
+	case 'f':
+		return r.Sign() == y.Sign()
+	case 'c':
+		return r.Sign() == -y.Sign()
+	}
+	return r.Sign() == x.Sign()
+}
+
+func TestDivRounding(t *testing.T) {
+	type divFuncs struct {
+		div    func(z, x, y *Int) *Int
+		rem    func(z, x, y *Int) *Int
+		divRem func(z, x, y, r *Int) (*Int, *Int)
+		div64  func(z, x *Int, y int64) *Int
+		rem64  func(z *Int, y int64) int64
+		divU64 func(z, x *Int, y uint64) *Int
+	}
+	modes := map[byte]divFuncs{
+		'f': {(*Int).FDiv, (*Int).FRem, (*Int).FDivRem, (*Int).FDivInt64, (*Int).FRemInt64, (*Int).FDivUint64},
+		'c': {(*Int).CDiv, (*Int).CRem, (*Int).CDivRem, (*Int).CDivInt64, (*Int).CRemInt64, (*Int).CDivUint64},
+		't': {(*Int).TDiv, (*Int).TRem, (*Int).TDivRem, (*Int).TDivInt64, (*Int).TRemInt64, (*Int).TDivUint64},
+	}
+	for mode, f := range modes {
+		for _, xs := range divTests {
+			for _, ys := range divTests {
+				x, y := mustInt(t, xs), mustInt(t, ys)
+				if y.Sign() == 0 {
+					continue
+				}
+				q, r := f.divRem(NewInt(0), x, y, NewInt(0))
+				if !checkDivision(x, y, q, r, mode) {
+					t.Errorf("%cDivRem(%s, %s) = %v, %v", mode, xs, ys, q, r)
+					continue
+				}
+				if z := f.div(NewInt(0), x, y); z.Cmp(q) != 0 {
+					t.Errorf("%cDiv(%s, %s) = %v, want %v", mode, xs, ys, z, q)
+				}
+				if z := f.rem(NewInt(0), x, y); z.Cmp(r) != 0 {
+					t.Errorf("%cRem(%s, %s) = %v, want %v", mode, xs, ys, z, r)
+				}
+				// In place.
+				if z := NewInt(0).Set(x); f.div(z, z, y).Cmp(q) != 0 {
+					t.Errorf("%cDiv(%s, %s) in place = %v, want %v", mode, xs, ys, z, q)
+				}
+				if y.BitLen() < 64 {
+					v := y.Int64()
+					if z := f.div64(NewInt(0), x, v); z.Cmp(q) != 0 {
+						t.Errorf("%cDivInt64(%s, %s) = %v, want %v", mode, xs, ys, z, q)
+					}
+					if m := f.rem64(x, v); m != r.Int64() {
+						t.Errorf("%cRemInt64(%s, %s) = %d, want %v", mode, xs, ys, m, r)
+					}
+				}
+				if y.Sign() > 0 && y.BitLen() <= 64 {
+					u := uint64(y.Int64())
+					if z := f.divU64(NewInt(0), x, u); z.Cmp(q) != 0 {
+						t.Errorf("%cDivUint64(%s, %s) = %v, want %v", mode, xs, ys, z, q)
+					}
+				}
+			}
+		}
+	}
+
+	x := mustInt(t, "-123456789012345678901234567890")
+	big := uint64(math.MaxUint64)
+	if r := x.FRemUint64(big); NewInt(0).FRem(x, NewInt(0).SetUint64(big)).Cmp(NewInt(0).SetUint64(r)) != 0 {
+		t.Errorf("FRemUint64 = %d", r)
+	}
+	if r := NewInt(-7).FRemUint64(3); r != 2 {
+		t.Errorf("FRemUint64(-7, 3) = %d, want 2", r)
+	}
+	if q := NewInt(-7).FDivUint64(NewInt(-7), big); q.Int64() != -1 {
+		t.Errorf("FDivUint64(-7, 2^64-1) = %v, want -1", q)
+	}
+}
+
+func TestDiv2Exp(t *testing.T) {
+	for _, xs := range divTests {
+		x := mustInt(t, xs)
+		for _, n := range []uint{0, 1, 3, 62, 64, 100} {
+			y := NewInt(0).Mul2Exp(NewInt(1), n)
+			for _, tc := range []struct {
+				name      string
+				got, want *Int
+			}{
+				{"Mul2Exp", NewInt(0).Mul2Exp(x, n), NewInt(0).Lsh(x, n)},
+				{"FDiv2Exp", NewInt(0).FDiv2Exp(x, n), NewInt(0).FDiv(x, y)},
+				{"CDiv2Exp", NewInt(0).CDiv2Exp(x, n), NewInt(0).CDiv(x, y)},
+				{"TDiv2Exp", NewInt(0).TDiv2Exp(x, n), NewInt(0).TDiv(x, y)},
+				{"FRem2Exp", NewInt(0).FRem2Exp(x, n), NewInt(0).FRem(x, y)},
+				{"CRem2Exp", NewInt(0).CRem2Exp(x, n), NewInt(0).CRem(x, y)},
+				{"TRem2Exp", NewInt(0).TRem2Exp(x, n), NewInt(0).TRem(x, y)},
+			} {
+				if tc.got.Cmp(tc.want) != 0 {
+					t.Errorf("%s(%s, %d) = %v, want %v", tc.name, xs, n, tc.got, tc.want)
+				}
+			}
+		}
+	}
+}
+
+func TestDivExact(t *testing.T) {
+	x := mustInt(t, "-123456789012345678901234567890")
+	y := mustInt(t, "987654321987654321")
+	p := NewInt(0).Mul(x, y)
+	if z := NewInt(0).DivExact(p, y); z.Cmp(x) != 0 {
+		t.Errorf("DivExact = %v, want %v", z, x)
+	}
+	if z, ok := NewInt(0).DivExactChecked(p, x); !ok || z.Cmp(y) != 0 {
+		t.Errorf("DivExactChecked = %v, %v, want %v", z, ok, y)
+	}
+	if z, ok := NewInt(0).DivExactChecked(p.AddInt64(p, 1), x); ok || z.Sign() != 0 {
+		t.Errorf("DivExactChecked of a non-multiple = %v, %v, want 0, false", z, ok)
+	}
+	p.MulInt64(x, -6)
+	if z := NewInt(0).DivExactInt64(p, -6); z.Cmp(x) != 0 {
+		t.Errorf("DivExactInt64 = %v, want %v", z, x)
+	}
+	if z := NewInt(0).DivExactUint64(p, 3); z.Cmp(NewInt(0).MulInt64(x, -2)) != 0 {
+		t.Errorf("DivExactUint64 = %v", z)
+	}
+
+	for name, f := range map[string]func(){
+		"FDiv":            func() { NewInt(0).FDiv(x, NewInt(0)) },
+		"CRem":            func() { NewInt(0).CRem(x, NewInt(0)) },
+		"TDivRem":         func() { NewInt(0).TDivRem(x, NewInt(0), NewInt(0)) },
+		"FDivInt64":       func() { NewInt(0).FDivInt64(x, 0) },
+		"CRemInt64":       func() { NewInt(1).CRemInt64(0) },
+		"TDivUint64":      func() { NewInt(0).TDivUint64(x, 0) },
+		"FRemUint64":      func() { x.FRemUint64(0) },
+		"DivExact":        func() { NewInt(0).DivExact(x, NewInt(0)) },
+		"DivExactChecked": func() { NewInt(0).DivExactChecked(x, NewInt(0)) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDivisionByZero) {
+			t.Errorf("%s by zero: err = %v, want ErrDivisionByZero", name, err)
+		}
+	}
+}
+
+func TestScalarArith(t *testing.T) {
+	for _, xs := range divTests {
+		x := mustInt(t, xs)
+		for _, v := range []int64{0, 1, -1, 12345, 1<<62 - 1, -1 << 62, math.MaxInt64, math.MinInt64} {
+			y := NewInt(v)
+			for _, tc := range []struct {
+				name      string
+				got, want *Int
+			}{
+				{"AddInt64", NewInt(0).AddInt64(x, v), NewInt(0).Add(x, y)},
+				{"SubInt64", NewInt(0).SubInt64(x, v), NewInt(0).Sub(x, y)},
+				{"MulInt64", NewInt(0).MulInt64(x, v), NewInt(0).Mul(x, y)},
+			} {
+				if tc.got.Cmp(tc.want) != 0 {
+					t.Errorf("%s(%s, %d) = %v, want %v", tc.name, xs, v, tc.got, tc.want)
+				}
+			}
+			z := NewInt(0).Set(y)
+			z.AddMul(x, x)
+			want := NewInt(0).Mul(x, x)
+			if z.Cmp(want.Add(want, y)) != 0 {
+				t.Errorf("%d + %s*%s = %v, want %v", v, xs, xs, z, want)
+			}
+			z.SubMul(x, y)
+			want.Sub(want, NewInt(0).Mul(x, y))
+			if z.Cmp(want) != 0 {
+				t.Errorf("SubMul = %v, want %v", z, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpz
+
+import (
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/internal/util"
+)
+
+// Exp sets z = x^y mod m and returns z.
+//
+// If m == nil, Exp sets z = x^y.  y must be non-negative, or Exp
+// panics with flint.ErrDomain, and if x^y is too large to represent
+// Exp panics with flint.ErrOverflow.
+//
+// Otherwise m must be positive; Exp panics with
+// flint.ErrDivisionByZero if m is 0 and with flint.ErrDomain if m is
+// negative.  The result lies in [0, m).  A negative y stands for the
+// power -y of the inverse of x mod m; if x has no inverse, Exp panics
+// with flint.ErrNotInvertible.
+func (z *Int) Exp(x, y, m *Int) *Int {
+	if m != nil {
+		checkModulus("Exp", m)
+		if y.Sign() < 0 {
+			t := new(Int).inverse("Exp", x, m)
+			return z.powMod(t, new(Int).Neg(y), m)
+		}
+		return z.powMod(x, y, m)
+	}
+	if y.Sign() < 0 {
+		flint.Panic("fmpz.Int.Exp", flint.ErrDomain)
+	}
+	if n := x.BitLen(); n <= 1 {
+		// x is -1, 0 or 1, whose powers are known for any y.
+		switch {
+		case y.Sign() == 0:
+			return z.SetInt64(1)
+		case x.Sign() < 0 && y.bit(0) == 0:
+			return z.SetInt64(1)
+		}
+		return z.Set(x)
+	} else if y.BitLen() > 64 || y.word() > maxPowBits/uint64(n) {
+		// x^y has at most y*n bits.
+		flint.Panic("fmpz.Int.Exp", flint.ErrOverflow)
+	}
+	return z.pow(x, y.word())
+}
+
+// MultiExp sets z to the product of xs[i]^ys[i] mod m and returns
+// z.  The rules for m and for negative exponents are those of Exp,
+// except that a nil m panics with flint.ErrDomain.  MultiExp shares
+// the squarings between all factors, which makes it faster than
+// multiplying the results of Exp.
+func (z *Int) MultiExp(xs, ys []*Int, m *Int) *Int {
+	if m == nil {
+		flint.Panic("fmpz.Int.MultiExp", flint.ErrDomain)
+	}
+	util.CheckLen("fmpz", len(xs), len(ys))
+	checkModulus("MultiExp", m)
+	bs := make([]*Int, len(xs))
+	es := make([]*Int, len(ys))
+	n := 0
+	for i := range xs {
+		bs[i], es[i] = new(Int), new(Int)
+		if ys[i].Sign() < 0 {
+			bs[i].inverse("MultiExp", xs[i], m)
+			es[i].Neg(ys[i])
+		} else {
+			bs[i].Mod(xs[i], m)
+			es[i].Set(ys[i])
+		}
+		if l := es[i].BitLen(); l > n {
+			n = l
+		}
+	}
+	// Left-to-right square and multiply over all exponents at once.
+	r := new(Int).Mod(NewInt(1), m)
+	for j := n - 1; j >= 0; j-- {
+		r.Mul(r, r).Mod(r, m)
+		for i, e := range es {
+			if e.bit(j) != 0 {
+				r.Mul(r, bs[i]).Mod(r, m)
+			}
+		}
+	}
+	return z.Set(r)
+}
+
+// checkModulus panics unless m is positive.
+func checkModulus(op string, m *Int) {
+	switch m.Sign() {
+	case 0:
+		flint.Panic("fmpz.Int."+op, flint.ErrDivisionByZero)
+	case -1:
+		flint.Panic("fmpz.Int."+op, flint.ErrDomain)
+	}
+}
+
+// inverse sets z to the inverse of x mod m and returns z, or panics
+// with flint.ErrNotInvertible if there is none.
+func (z *Int) inverse(op string, x, m *Int) *Int {
+	if _, ok := z.invMod(x, m); !ok {
+		flint.Panic("fmpz.Int."+op, flint.ErrNotInvertible)
+	}
+	return z
+}
@@ -0,0 +1,102 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+func TestExp(t *testing.T) {
+	m := NewInt(1000000007)
+	huge := mustInt(t, "1000000000000000000000000000000")
+	for _, tc := range []struct {
+		name string
+		got  *Int
+		want string
+	}{
+		{"2^64", NewInt(0).Exp(NewInt(2), mustInt(t, "64"), nil), "18446744073709551616"},
+		{"x^0", NewInt(0).Exp(NewInt(12345), NewInt(0), nil), "1"},
+		{"0^huge", NewInt(7).Exp(NewInt(0), huge, nil), "0"},
+		{"1^huge", NewInt(0).Exp(NewInt(1), huge, nil), "1"},
+		{"(-1)^huge", NewInt(0).Exp(NewInt(-1), huge, nil), "1"},
+		{"(-1)^(huge+1)", NewInt(0).Exp(NewInt(-1), NewInt(0).AddInt64(huge, 1), nil), "-1"},
+		{"3^huge mod m", NewInt(0).Exp(NewInt(3), huge, m), "965115194"},
+		{"3^-5 mod m", NewInt(0).Exp(NewInt(3), NewInt(-5), m), "707818935"},
+		{"(-2)^-3 mod 101", NewInt(0).Exp(NewInt(-2), NewInt(-3), NewInt(101)), "63"},
+		{"(-2)^3 mod 101", NewInt(0).Exp(NewInt(-2), NewInt(3), NewInt(101)), "93"},
+		{"x^-1 mod 1", NewInt(0).Exp(NewInt(6), NewInt(-1), NewInt(1)), "0"},
+	} {
+		if s := tc.got.String(); s != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, s, tc.want)
+		}
+	}
+
+	// z may alias the modulus.
+	z := NewInt(97)
+	if z.Exp(NewInt(5), NewInt(3), z); z.Int64() != 28 {
+		t.Errorf("5^3 mod 97 in place = %v, want 28", z)
+	}
+
+	for _, tc := range []struct {
+		name string
+		f    func()
+		err  error
+	}{
+		{"negative exponent", func() { NewInt(0).Exp(NewInt(2), NewInt(-1), nil) }, flint.ErrDomain},
+		{"huge exponent", func() { NewInt(0).Exp(NewInt(2), huge, nil) }, flint.ErrOverflow},
+		{"2^(2^40)", func() { NewInt(0).Exp(NewInt(2), NewInt(1<<40), nil) }, flint.ErrOverflow},
+		// 3^y has about 1.58*y bits, more than maxPowBits.
+		{"3^(maxPowBits/2 + 1)", func() { NewInt(0).Exp(NewInt(3), NewInt(0).SetUint64(maxPowBits/2+1), nil) }, flint.ErrOverflow},
+		{"(-3)^(maxPowBits/2 + 1)", func() { NewInt(0).Exp(NewInt(-3), NewInt(0).SetUint64(maxPowBits/2+1), nil) }, flint.ErrOverflow},
+		{"zero modulus", func() { NewInt(0).Exp(NewInt(2), NewInt(3), NewInt(0)) }, flint.ErrDivisionByZero},
+		{"negative modulus", func() { NewInt(0).Exp(NewInt(2), NewInt(3), NewInt(-5)) }, flint.ErrDomain},
+		{"no inverse", func() { NewInt(0).Exp(NewInt(6), NewInt(-1), NewInt(9)) }, flint.ErrNotInvertible},
+	} {
+		if err := flint.Try(tc.f); !errors.Is(err, tc.err) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestMultiExp(t *testing.T) {
+	m := NewInt(1000000007)
+	xs := []*Int{NewInt(2), NewInt(3), NewInt(10)}
+	ys := []*Int{NewInt(100), NewInt(-7), NewInt(12345)}
+	if z := NewInt(0).MultiExp(xs, ys, m); z.String() != "472403984" {
+		t.Errorf("MultiExp = %v, want 472403984", z)
+	}
+
+	// Against a product of Exp with large operands.
+	n := mustInt(t, "123456789012345678901234567891")
+	xs = []*Int{mustInt(t, "-98765432109876543210"), NewInt(0), NewInt(7), n}
+	ys = []*Int{mustInt(t, "340282366920938463463374607431768211457"), NewInt(0), NewInt(-3), NewInt(5)}
+	want := NewInt(1)
+	for i := range xs {
+		want.Mul(want, NewInt(0).Exp(xs[i], ys[i], n)).Mod(want, n)
+	}
+	if z := NewInt(0).MultiExp(xs, ys, n); z.Cmp(want) != 0 {
+		t.Errorf("MultiExp = %v, want %v", z, want)
+	}
+	if z := NewInt(5).MultiExp(nil, nil, m); z.Int64() != 1 {
+		t.Errorf("empty MultiExp = %v, want 1", z)
+	}
+
+	for _, tc := range []struct {
+		name string
+		f    func()
+		err  error
+	}{
+		{"nil modulus", func() { NewInt(0).MultiExp(xs, ys, nil) }, flint.ErrDomain},
+		{"zero modulus", func() { NewInt(0).MultiExp(xs, ys, NewInt(0)) }, flint.ErrDivisionByZero},
+		{"no inverse", func() { NewInt(0).MultiExp([]*Int{NewInt(3)}, []*Int{NewInt(-1)}, NewInt(9)) }, flint.ErrNotInvertible},
+	} {
+		if err := flint.Try(tc.f); !errors.Is(err, tc.err) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpz
+
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+import "C"
+
+// Factorial sets z = n! and returns z.
+func (z *Int) Factorial(n uint64) *Int {
+	C.fmpz_fac_ui((*C.fmpz)(z), C.ulong(n))
+	return z
+}
+
+// BinomialUint64 sets z to the binomial coefficient C(n, k) and
+// returns z.  It is 0 if k > n.
+func (z *Int) BinomialUint64(n, k uint64) *Int {
+	C.fmpz_bin_uiui((*C.fmpz)(z), C.ulong(n), C.ulong(k))
+	return z
+}
+
+// RisingFactorial sets z to x(x+1)...(x+n-1) and returns z.  It is
+// 1 if n = 0.
+func (z *Int) RisingFactorial(x *Int, n uint64) *Int {
+	C.fmpz_rfac_ui((*C.fmpz)(z), (*C.fmpz)(x), C.ulong(n))
+	return z
+}
+
+// Fibonacci sets z to the n-th Fibonacci number F(n), with F(0) = 0
+// and F(1) = 1, and returns z.
+func (z *Int) Fibonacci(n uint64) *Int {
+	C.fmpz_fib_ui((*C.fmpz)(z), C.ulong(n))
+	return z
+}
+
+// Primorial sets z to the product of the primes p <= n and returns
+// z.
+func (z *Int) Primorial(n uint64) *Int {
+	C.fmpz_primorial((*C.fmpz)(z), C.ulong(n))
+	return z
+}
@@ -0,0 +1,115 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpz
+
+import (
+	"math"
+	"math/big"
+)
+
+// Factorial sets z = n! and returns z.
+func (z *Int) Factorial(n uint64) *Int {
+	if n > math.MaxInt64 {
+		z.big().Set(rising(big.NewInt(1), n))
+		return z
+	}
+	z.big().MulRange(1, int64(n))
+	return z
+}
+
+// BinomialUint64 sets z to the binomial coefficient C(n, k) and
+// returns z.  It is 0 if k > n.
+func (z *Int) BinomialUint64(n, k uint64) *Int {
+	if k > n {
+		return z.SetInt64(0)
+	}
+	if n > math.MaxInt64 {
+		// big.Int.Binomial takes int64 arguments; compute
+		// C(n, k) = n(n-1)...(n-k+1)/k! with k <= n/2 instead.
+		k = min(k, n-k)
+		if k == 0 {
+			return z.SetInt64(1)
+		}
+		num := rising(new(big.Int).SetUint64(n-k+1), k)
+		z.big().Quo(num, new(big.Int).MulRange(1, int64(k)))
+		return z
+	}
+	z.big().Binomial(int64(n), int64(k))
+	return z
+}
+
+// RisingFactorial sets z to x(x+1)...(x+n-1) and returns z.  It is
+// 1 if n = 0.
+func (z *Int) RisingFactorial(x *Int, n uint64) *Int {
+	if n == 0 {
+		return z.SetInt64(1)
+	}
+	z.big().Set(rising(new(big.Int).Set(x.big()), n))
+	return z
+}
+
+// rising returns x(x+1)...(x+n-1) for n > 0, splitting the product
+// in halves so that the factors stay balanced.
+func rising(x *big.Int, n uint64) *big.Int {
+	if n == 1 {
+		return x
+	}
+	h := n / 2
+	y := new(big.Int).Add(x, new(big.Int).SetUint64(h))
+	return new(big.Int).Mul(rising(x, h), rising(y, n-h))
+}
+
+// Fibonacci sets z to the n-th Fibonacci number F(n), with F(0) = 0
+// and F(1) = 1, and returns z.
+func (z *Int) Fibonacci(n uint64) *Int {
+	// Fast doubling: F(2k) = F(k)(2F(k+1) - F(k)) and
+	// F(2k+1) = F(k)^2 + F(k+1)^2.
+	a, b := big.NewInt(0), big.NewInt(1) // F(k), F(k+1)
+	t, u := new(big.Int), new(big.Int)
+	for i := 63; i >= 0; i-- {
+		t.Lsh(b, 1).Sub(t, a).Mul(t, a)
+		u.Mul(a, a)
+		b.Mul(b, b).Add(b, u)
+		a, t = t, a
+		if n>>uint(i)&1 != 0 {
+			a.Add(a, b)
+			a, b = b, a
+		}
+	}
+	z.big().Set(a)
+	return z
+}
+
+// Primorial sets z to the product of the primes p <= n and returns
+// z.
+func (z *Int) Primorial(n uint64) *Int {
+	composite := make([]bool, n+1)
+	ps := []*big.Int{}
+	for p := uint64(2); p <= n; p++ {
+		if composite[p] {
+			continue
+		}
+		ps = append(ps, new(big.Int).SetUint64(p))
+		for q := p * p; q <= n; q += p {
+			composite[q] = true
+		}
+	}
+	z.big().Set(product(ps))
+	return z
+}
+
+// product returns the product of xs, 1 if xs is empty.
+func product(xs []*big.Int) *big.Int {
+	switch len(xs) {
+	case 0:
+		return big.NewInt(1)
+	case 1:
+		return new(big.Int).Set(xs[0])
+	}
+	h := len(xs) / 2
+	return new(big.Int).Mul(product(xs[:h]), product(xs[h:]))
+}
@@ -2,12 +2,50 @@
 // Use of this source code is governed by the GNU General
 // Public License version 2 (or any later version).
 
+//go:build !noflint
+
 package fmpz
 
-// #cgo LDFLAGS: -lflint -lmpir -lmpfr -lm
 // #include <stdlib.h>
-// #include <flint.h>
-// #include <fmpz.h>
-// #include <arith.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_factor.h>
+// #include <flint/arith.h>
 import "C"
 
+import (
+	"unsafe"
+)
+
+// Factor returns the prime factorisation of the absolute value
+// of z as two slices of the same length: the distinct primes in
+// increasing order and their multiplicities.  The factorisation
+// of 0 and 1 is empty.
+//
+// NB Factor computes the complete factorisation, which may take
+// a very long time if |z| has two or more large prime factors.
+func (z *Int) Factor() ([]*Int, []uint64) {
+	var f C.fmpz_factor_struct
+	C.fmpz_factor_init(&f)
+	defer C.fmpz_factor_clear(&f)
+
+	if z.Sign() == 0 {
+		return nil, nil
+	}
+	C.fmpz_factor(&f, (*C.fmpz)(z))
+
+	n := int(f.num)
+	if n == 0 {
+		return nil, nil
+	}
+	ps := unsafe.Slice(f.p, n)
+	es := unsafe.Slice(f.exp, n)
+	p := make([]*Int, n)
+	e := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		p[i] = NewInt(0)
+		C.fmpz_set((*C.fmpz)(p[i]), &ps[i])
+		e[i] = uint64(es[i])
+	}
+	return p, e
+}
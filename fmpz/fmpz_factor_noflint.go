@@ -0,0 +1,115 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpz
+
+import (
+	"sort"
+)
+
+// Factor returns the prime factorisation of the absolute value
+// of z as two slices of the same length: the distinct primes in
+// increasing order and their multiplicities.  The factorisation
+// of 0 and 1 is empty.
+//
+// NB Factor computes the complete factorisation, which may take
+// a very long time if |z| has two or more large prime factors.
+// Without FLINT it uses trial division and Pollard's rho method
+// only, so it is much slower than the FLINT backend.
+func (z *Int) Factor() ([]*Int, []uint64) {
+	if z.Sign() == 0 {
+		return nil, nil
+	}
+	n := new(Int).Abs(z)
+	m := make(map[string]uint64)
+	ps := make(map[string]*Int)
+	add := func(p *Int) {
+		k := p.String()
+		if _, ok := ps[k]; !ok {
+			ps[k] = new(Int).Set(p)
+		}
+		m[k]++
+	}
+
+	one, d, r := NewInt(1), NewInt(0), NewInt(0)
+	for t := int64(2); t < 1000 && n.Cmp(one) > 0; t++ {
+		d.SetInt64(t)
+		for {
+			q, _ := new(Int).DivMod(n, d, r)
+			if r.Sign() != 0 {
+				break
+			}
+			add(d)
+			n.Set(q)
+		}
+	}
+
+	stack := []*Int{n}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch {
+		case n.Cmp(one) == 0:
+		case n.IsProbablePrime():
+			add(n)
+		default:
+			f := rho(n)
+			stack = append(stack, f, new(Int).Div(n, f))
+		}
+	}
+
+	p := make([]*Int, 0, len(ps))
+	for _, x := range ps {
+		p = append(p, x)
+	}
+	sort.Slice(p, func(i, j int) bool { return p[i].Cmp(p[j]) < 0 })
+	e := make([]uint64, len(p))
+	for i, x := range p {
+		e[i] = m[x.String()]
+	}
+	return p, e
+}
+
+// rho returns a nontrivial factor of the odd composite n, using
+// Pollard's rho method with Brent's cycle detection.
+func rho(n *Int) *Int {
+	one := NewInt(1)
+	x, y, g, q, t := NewInt(0), NewInt(0), NewInt(0), NewInt(0), NewInt(0)
+	for c := int64(1); ; c++ {
+		f := func(v *Int) { v.Mul(v, v).Add(v, t.SetInt64(c)).Mod(v, n) }
+		y.SetInt64(2)
+		g.Set(one)
+		for r := 1; g.Cmp(one) == 0; r *= 2 {
+			x.Set(y)
+			for i := 0; i < r; i++ {
+				f(y)
+			}
+			for k := 0; k < r && g.Cmp(one) == 0; k += 128 {
+				ys := new(Int).Set(y)
+				q.Set(one)
+				for i := 0; i < 128 && i < r-k; i++ {
+					f(y)
+					q.Mul(q, t.Abs(t.Sub(x, y))).Mod(q, n)
+				}
+				g.GCD(nil, nil, q, n)
+				if g.Sign() == 0 || g.Cmp(n) == 0 {
+					// Overshot; step back one at a time.
+					y.Set(ys)
+					for {
+						f(y)
+						g.GCD(nil, nil, t.Abs(t.Sub(x, y)), n)
+						if g.Sign() != 0 && g.Cmp(one) != 0 || x.Cmp(y) == 0 {
+							break
+						}
+					}
+				}
+			}
+		}
+		if g.Sign() != 0 && g.Cmp(n) != 0 {
+			return new(Int).Set(g)
+		}
+	}
+}
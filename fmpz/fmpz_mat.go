@@ -2,21 +2,89 @@
 // Use of this source code is governed by the GNU General
 // Public License version 2 (or any later version).
 
+//go:build !noflint
+
 package fmpz
 
-// #cgo LDFLAGS: -lflint -lmpir -lmpfr -lm
 // #include <stdlib.h>
-// #include <flint.h>
-// #include <fmpz.h>
-// #include <fmpz_mat.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_mat.h>
+//
+// // The layout of fmpz_mat_struct differs between FLINT versions;
+// // the entry macro does not.
+// static fmpz *goflint_fmpz_mat_entry(fmpz_mat_struct *m, slong i, slong j)
+// {
+// 	return fmpz_mat_entry(m, i, j);
+// }
 import "C"
 
 import (
-	// "os"
-	// "unsafe"
+	"runtime"
+	"strings"
 )
 
 // An IntMat represents a matrix with integral entries. The
 // zero value for a Mat represents the zero matrix.
 type Mat C.fmpz_mat_struct
 
+// NewMat returns the zero matrix with the given dimensions.
+func NewMat(rows, cols int) *Mat {
+	z := new(Mat)
+	C.fmpz_mat_init((*C.fmpz_mat_struct)(z), C.slong(rows), C.slong(cols))
+	runtime.SetFinalizer(z, (*Mat).destroy)
+	return z
+}
+
+func (z *Mat) destroy() {
+	C.fmpz_mat_clear((*C.fmpz_mat_struct)(z))
+}
+
+// Rows returns the number of rows of z.
+func (z *Mat) Rows() int {
+	return int(z.r)
+}
+
+// Cols returns the number of columns of z.
+func (z *Mat) Cols() int {
+	return int(z.c)
+}
+
+// entry returns a pointer to the entry of z in row i and column j.
+func (z *Mat) entry(i, j int) *C.fmpz {
+	if i < 0 || i >= z.Rows() || j < 0 || j >= z.Cols() {
+		panic("fmpz: Mat index out of range")
+	}
+	return C.goflint_fmpz_mat_entry((*C.fmpz_mat_struct)(z), C.slong(i), C.slong(j))
+}
+
+// Entry returns a new Int equal to the entry of z in row i and
+// column j.
+func (z *Mat) Entry(i, j int) *Int {
+	x := NewInt(0)
+	C.fmpz_set((*C.fmpz)(x), z.entry(i, j))
+	return x
+}
+
+// SetEntry sets the entry of z in row i and column j to x and
+// returns z.
+func (z *Mat) SetEntry(i, j int, x *Int) *Mat {
+	C.fmpz_set(z.entry(i, j), (*C.fmpz)(x))
+	return z
+}
+
+// String returns a string representation of z, one row per line.
+func (z *Mat) String() string {
+	var b strings.Builder
+	for i := 0; i < z.Rows(); i++ {
+		b.WriteString("[")
+		for j := 0; j < z.Cols(); j++ {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(z.Entry(i, j).String())
+		}
+		b.WriteString("]\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,250 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpz
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_mod.h>
+import "C"
+
+import (
+	"runtime"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/internal/util"
+)
+
+// A ModRing represents the ring Z/nZ for a multi-precision
+// modulus n > 0.  It precomputes the data FLINT needs for fast
+// reduction, so it should be reused for many operations.
+//
+// The methods of ModRing expect their Int arguments to be reduced
+// residues 0 <= x < n, and return reduced residues.  Use Reduce to
+// bring an arbitrary Int into range.
+type ModRing C.fmpz_mod_ctx_struct
+
+// NewModRing returns the ring Z/nZ.  If n <= 0, NewModRing panics
+// with flint.ErrDomain.
+func NewModRing(n *Int) *ModRing {
+	if n.Sign() <= 0 {
+		flint.Panic("fmpz.NewModRing", flint.ErrDomain)
+	}
+	r := new(ModRing)
+	C.fmpz_mod_ctx_init((*C.fmpz_mod_ctx_struct)(r), (*C.fmpz)(n))
+	runtime.SetFinalizer(r, (*ModRing).destroy)
+	return r
+}
+
+func (r *ModRing) destroy() {
+	C.fmpz_mod_ctx_clear((*C.fmpz_mod_ctx_struct)(r))
+}
+
+func (r *ModRing) ctx() *C.fmpz_mod_ctx_struct {
+	return (*C.fmpz_mod_ctx_struct)(r)
+}
+
+func (r *ModRing) modulus() *C.fmpz {
+	return C.fmpz_mod_ctx_modulus(r.ctx())
+}
+
+// Modulus returns a new Int equal to the modulus n of r.
+func (r *ModRing) Modulus() *Int {
+	z := NewInt(0)
+	C.fmpz_set((*C.fmpz)(z), r.modulus())
+	return z
+}
+
+// String returns a description of r such as "Z/101Z".
+func (r *ModRing) String() string {
+	return "Z/" + r.Modulus().String() + "Z"
+}
+
+// Reduce sets z to the residue of x in r, 0 <= z < n, and returns z.
+func (r *ModRing) Reduce(z, x *Int) *Int {
+	C.fmpz_mod_set_fmpz((*C.fmpz)(z), (*C.fmpz)(x), r.ctx())
+	return z
+}
+
+// IsReduced reports whether 0 <= x < n.
+func (r *ModRing) IsReduced(x *Int) bool {
+	return C.fmpz_mod_is_canonical((*C.fmpz)(x), r.ctx()) != 0
+}
+
+// Add sets z = x + y mod n and returns z.
+func (r *ModRing) Add(z, x, y *Int) *Int {
+	C.fmpz_mod_add((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y), r.ctx())
+	return z
+}
+
+// Sub sets z = x - y mod n and returns z.
+func (r *ModRing) Sub(z, x, y *Int) *Int {
+	C.fmpz_mod_sub((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y), r.ctx())
+	return z
+}
+
+// Neg sets z = -x mod n and returns z.
+func (r *ModRing) Neg(z, x *Int) *Int {
+	C.fmpz_mod_neg((*C.fmpz)(z), (*C.fmpz)(x), r.ctx())
+	return z
+}
+
+// Mul sets z = x * y mod n and returns z.
+func (r *ModRing) Mul(z, x, y *Int) *Int {
+	C.fmpz_mod_mul((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y), r.ctx())
+	return z
+}
+
+// Inv sets z to the inverse of x mod n and returns (z, true).
+// If x is not invertible, z is unchanged and Inv returns (z, false).
+func (r *ModRing) Inv(z, x *Int) (*Int, bool) {
+	t := NewInt(0)
+	if C.fmpz_invmod((*C.fmpz)(t), (*C.fmpz)(x), r.modulus()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Pow sets z = x^e mod n and returns (z, true).  A negative
+// exponent is allowed if x is invertible; otherwise z is unchanged
+// and Pow returns (z, false).
+func (r *ModRing) Pow(z, x, e *Int) (*Int, bool) {
+	t := NewInt(0)
+	if C.fmpz_mod_pow_fmpz((*C.fmpz)(t), (*C.fmpz)(x), (*C.fmpz)(e), r.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Sqrt sets z to a square root of x mod n and returns (z, true).
+// The modulus must be prime.  If x is not a square, z is unchanged
+// and Sqrt returns (z, false).
+func (r *ModRing) Sqrt(z, x *Int) (*Int, bool) {
+	t := NewInt(0)
+	if C.fmpz_sqrtmod((*C.fmpz)(t), (*C.fmpz)(x), r.modulus()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+/*
+ * batched operations
+ */
+
+// AddVec sets z[i] = x[i] + y[i] mod n for every i and returns z.
+// The slices must have the same length.
+func (r *ModRing) AddVec(z, x, y []*Int) []*Int {
+	util.CheckLen("fmpz", len(z), len(x), len(y))
+	for i := range z {
+		r.Add(z[i], x[i], y[i])
+	}
+	return z
+}
+
+// MulVec sets z[i] = x[i] * y[i] mod n for every i and returns z.
+// The slices must have the same length.
+func (r *ModRing) MulVec(z, x, y []*Int) []*Int {
+	util.CheckLen("fmpz", len(z), len(x), len(y))
+	for i := range z {
+		r.Mul(z[i], x[i], y[i])
+	}
+	return z
+}
+
+// InvVec sets z[i] to the inverse of x[i] mod n for every i, using
+// Montgomery's trick: a single modular inversion and 3(len(x)-1)
+// multiplications.  It returns (z, true) on success.  If some x[i]
+// is not invertible, z is unchanged and InvVec returns (z, false).
+func (r *ModRing) InvVec(z, x []*Int) ([]*Int, bool) {
+	util.CheckLen("fmpz", len(z), len(x))
+	if len(x) == 0 {
+		return z, true
+	}
+
+	// prefix[i] = x[0] * ... * x[i]
+	prefix := make([]*Int, len(x))
+	prefix[0] = new(Int).Set(x[0])
+	for i := 1; i < len(x); i++ {
+		prefix[i] = r.Mul(NewInt(0), prefix[i-1], x[i])
+	}
+	inv, ok := r.Inv(NewInt(0), prefix[len(x)-1])
+	if !ok {
+		return z, false
+	}
+
+	t := NewInt(0)
+	for i := len(x) - 1; i > 0; i-- {
+		t.Set(x[i]) // z[i] may alias x[i]
+		r.Mul(z[i], inv, prefix[i-1])
+		r.Mul(inv, inv, t)
+	}
+	z[0].Set(inv)
+	return z, true
+}
+
+// PowVec sets z[i] = x[i]^e mod n for every i and returns (z, true).
+// If e is negative and some x[i] is not invertible, PowVec returns
+// (z, false) and the contents of z are unspecified.
+func (r *ModRing) PowVec(z, x []*Int, e *Int) ([]*Int, bool) {
+	util.CheckLen("fmpz", len(z), len(x))
+	if e.Sign() < 0 {
+		if _, ok := r.InvVec(z, x); !ok {
+			return z, false
+		}
+		x = z
+		e = new(Int).Neg(e)
+	}
+	for i := range z {
+		r.Pow(z[i], x[i], e)
+	}
+	return z, true
+}
+
+// ModRingCRT returns the unique residue z modulo the product M of
+// the moduli of rs with z = xs[i] mod rs[i].Modulus() for every i,
+// together with the ring Z/MZ.  The moduli must be pairwise coprime
+// and xs[i] must be reduced in rs[i]; otherwise ModRingCRT returns
+// (nil, nil, false).
+func ModRingCRT(rs []*ModRing, xs []*Int) (*Int, *ModRing, bool) {
+	util.CheckLen("fmpz", len(rs), len(xs))
+	if len(rs) == 0 {
+		return nil, nil, false
+	}
+
+	z := NewInt(0)
+	m := NewInt(0)
+	g := NewInt(0)
+	t := NewInt(0)
+	r0 := rs[0]
+	if !r0.IsReduced(xs[0]) {
+		return nil, nil, false
+	}
+	z.Set(xs[0])
+	C.fmpz_set((*C.fmpz)(m), r0.modulus())
+	for i := 1; i < len(rs); i++ {
+		if !rs[i].IsReduced(xs[i]) {
+			return nil, nil, false
+		}
+		C.fmpz_gcd((*C.fmpz)(g), (*C.fmpz)(m), rs[i].modulus())
+		if C.fmpz_is_one((*C.fmpz)(g)) == 0 {
+			return nil, nil, false
+		}
+		// fmpz_CRT requires moduli greater than 1.
+		if C.fmpz_is_one(rs[i].modulus()) != 0 {
+			continue
+		}
+		if C.fmpz_is_one((*C.fmpz)(m)) != 0 {
+			z.Set(xs[i])
+			C.fmpz_set((*C.fmpz)(m), rs[i].modulus())
+			continue
+		}
+		C.fmpz_CRT((*C.fmpz)(t), (*C.fmpz)(z), (*C.fmpz)(m), (*C.fmpz)(xs[i]), rs[i].modulus(), 0)
+		z.Set(t)
+		C.fmpz_mul((*C.fmpz)(m), (*C.fmpz)(m), rs[i].modulus())
+	}
+	return z, NewModRing(m), true
+}
@@ -0,0 +1,359 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpz
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_mod.h>
+// #include <flint/fmpz_mod_poly.h>
+// #include <flint/fmpz_mod_poly_factor.h>
+import "C"
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// A ModPoly represents a univariate polynomial with coefficients
+// in Z/nZ for a multi-precision modulus n, given by a ModRing.
+//
+// Operations combining several ModPolys require them to belong to
+// the same ModRing.  GCD, roots, factorisation and irreducibility
+// testing require the modulus to be prime.
+type ModPoly struct {
+	p C.fmpz_mod_poly_struct
+	r *ModRing
+}
+
+// NewModPoly returns the zero polynomial over r.
+func NewModPoly(r *ModRing) *ModPoly {
+	z := &ModPoly{r: r}
+	C.fmpz_mod_poly_init(&z.p, r.ctx())
+	runtime.SetFinalizer(z, (*ModPoly).destroy)
+	return z
+}
+
+// NewModPolyCoeffs returns the polynomial over r with the given
+// coefficients, starting with the constant term.  The coefficients
+// are reduced modulo n.
+func NewModPolyCoeffs(r *ModRing, c []*Int) *ModPoly {
+	z := NewModPoly(r)
+	for i := len(c) - 1; i >= 0; i-- {
+		z.SetCoeff(i, c[i])
+	}
+	return z
+}
+
+func (z *ModPoly) destroy() {
+	C.fmpz_mod_poly_clear(&z.p, z.r.ctx())
+}
+
+func (z *ModPoly) ctx() *C.fmpz_mod_ctx_struct {
+	return z.r.ctx()
+}
+
+// same panics unless all of xs belong to the ring of z.
+func (z *ModPoly) same(xs ...*ModPoly) {
+	for _, x := range xs {
+		if x.r != z.r {
+			panic("fmpz: ModPoly operands belong to different ModRings")
+		}
+	}
+}
+
+// Ring returns the ModRing of z.
+func (z *ModPoly) Ring() *ModRing {
+	return z.r
+}
+
+// Degree returns the degree of z.  The degree of the zero
+// polynomial is -1.
+func (z *ModPoly) Degree() int {
+	return int(C.fmpz_mod_poly_degree(&z.p, z.ctx()))
+}
+
+// Coeff returns a new Int equal to the coefficient of x^n in z.
+func (z *ModPoly) Coeff(n int) *Int {
+	c := NewInt(0)
+	C.fmpz_mod_poly_get_coeff_fmpz((*C.fmpz)(c), &z.p, C.slong(n), z.ctx())
+	return c
+}
+
+// Coeffs returns the coefficients of z, starting with the
+// constant term.
+func (z *ModPoly) Coeffs() []*Int {
+	c := make([]*Int, z.Degree()+1)
+	for i := range c {
+		c[i] = z.Coeff(i)
+	}
+	return c
+}
+
+// SetCoeff sets the coefficient of x^n in z to c mod n and returns z.
+func (z *ModPoly) SetCoeff(n int, c *Int) *ModPoly {
+	t := z.r.Reduce(NewInt(0), c)
+	C.fmpz_mod_poly_set_coeff_fmpz(&z.p, C.slong(n), (*C.fmpz)(t), z.ctx())
+	return z
+}
+
+// Set sets z = x and returns z.
+func (z *ModPoly) Set(x *ModPoly) *ModPoly {
+	z.same(x)
+	C.fmpz_mod_poly_set(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// Equal reports whether z and x are equal.
+func (z *ModPoly) Equal(x *ModPoly) bool {
+	z.same(x)
+	return C.fmpz_mod_poly_equal(&z.p, &x.p, z.ctx()) != 0
+}
+
+// String returns a string representation of z as a
+// polynomial in the variable 'x'.
+func (z *ModPoly) String() string {
+	d := z.Degree()
+	if d < 0 {
+		return "0"
+	}
+	var b strings.Builder
+	for i := d; i >= 0; i-- {
+		c := z.Coeff(i)
+		if c.Sign() == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("+")
+		}
+		b.WriteString(c.String())
+		switch i {
+		case 0:
+		case 1:
+			b.WriteString("*x")
+		default:
+			b.WriteString("*x^")
+			b.WriteString(strconv.Itoa(i))
+		}
+	}
+	return b.String()
+}
+
+// Add sets z = x + y and returns z.
+func (z *ModPoly) Add(x, y *ModPoly) *ModPoly {
+	z.same(x, y)
+	C.fmpz_mod_poly_add(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *ModPoly) Sub(x, y *ModPoly) *ModPoly {
+	z.same(x, y)
+	C.fmpz_mod_poly_sub(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *ModPoly) Neg(x *ModPoly) *ModPoly {
+	z.same(x)
+	C.fmpz_mod_poly_neg(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *ModPoly) Mul(x, y *ModPoly) *ModPoly {
+	z.same(x, y)
+	C.fmpz_mod_poly_mul(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// ScalarMul sets z = c*x and returns z.
+func (z *ModPoly) ScalarMul(x *ModPoly, c *Int) *ModPoly {
+	z.same(x)
+	t := z.r.Reduce(NewInt(0), c)
+	C.fmpz_mod_poly_scalar_mul_fmpz(&z.p, &x.p, (*C.fmpz)(t), z.ctx())
+	return z
+}
+
+// MakeMonic sets z to x divided by its leading coefficient and
+// returns (z, true).  If the leading coefficient is not invertible,
+// z is unchanged and MakeMonic returns (z, false).
+func (z *ModPoly) MakeMonic(x *ModPoly) (*ModPoly, bool) {
+	z.same(x)
+	d := x.Degree()
+	if d < 0 {
+		return z.Set(x), true
+	}
+	if _, ok := z.r.Inv(NewInt(0), x.Coeff(d)); !ok {
+		return z, false
+	}
+	C.fmpz_mod_poly_make_monic(&z.p, &x.p, z.ctx())
+	return z, true
+}
+
+// Derivative sets z to the derivative of x and returns z.
+func (z *ModPoly) Derivative(x *ModPoly) *ModPoly {
+	z.same(x)
+	C.fmpz_mod_poly_derivative(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// DivMod sets z to the quotient and m to the remainder of x divided
+// by y and returns (z, m, true).  If the leading coefficient of y is
+// not invertible, z and m are unchanged and DivMod returns false.
+func (z *ModPoly) DivMod(x, y, m *ModPoly) (*ModPoly, *ModPoly, bool) {
+	z.same(x, y, m)
+	if y.Degree() < 0 {
+		flint.Panic("fmpz.ModPoly.DivMod", flint.ErrDivisionByZero)
+	}
+	f := NewInt(0)
+	q := NewModPoly(z.r)
+	r := NewModPoly(z.r)
+	C.fmpz_mod_poly_divrem_f((*C.fmpz)(f), &q.p, &r.p, &x.p, &y.p, z.ctx())
+	if C.fmpz_is_one((*C.fmpz)(f)) == 0 {
+		return z, m, false
+	}
+	z.Set(q)
+	m.Set(r)
+	return z, m, true
+}
+
+// GCD sets z to the monic greatest common divisor of x and y and
+// returns z.  The modulus must be prime.
+func (z *ModPoly) GCD(x, y *ModPoly) *ModPoly {
+	z.same(x, y)
+	C.fmpz_mod_poly_gcd(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// XGCD sets z to the monic greatest common divisor of x and y, and
+// s and t such that z = s*x + t*y, and returns z.  The modulus must
+// be prime.
+func (z *ModPoly) XGCD(s, t, x, y *ModPoly) *ModPoly {
+	z.same(s, t, x, y)
+	C.fmpz_mod_poly_xgcd(&z.p, &s.p, &t.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// PowMod sets z = x^e mod f and returns z.  The polynomial f must
+// have an invertible leading coefficient.  If f is zero, PowMod
+// panics with flint.ErrDivisionByZero, and if e is negative, with
+// flint.ErrDomain.
+func (z *ModPoly) PowMod(x *ModPoly, e *Int, f *ModPoly) *ModPoly {
+	z.same(x, f)
+	if f.Degree() < 0 {
+		flint.Panic("fmpz.ModPoly.PowMod", flint.ErrDivisionByZero)
+	}
+	if e.Sign() < 0 {
+		flint.Panic("fmpz.ModPoly.PowMod", flint.ErrDomain)
+	}
+	t := NewModPoly(z.r)
+	C.fmpz_mod_poly_rem(&t.p, &x.p, &f.p, z.ctx())
+	C.fmpz_mod_poly_powmod_fmpz_binexp(&z.p, &t.p, (*C.fmpz)(e), &f.p, z.ctx())
+	return z
+}
+
+// Compose sets z = x(y) and returns z.
+func (z *ModPoly) Compose(x, y *ModPoly) *ModPoly {
+	z.same(x, y)
+	C.fmpz_mod_poly_compose(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// ComposeMod sets z = x(y) mod f and returns z.  The polynomial f
+// must have an invertible leading coefficient.  If f is zero,
+// ComposeMod panics with flint.ErrDivisionByZero.
+func (z *ModPoly) ComposeMod(x, y, f *ModPoly) *ModPoly {
+	z.same(x, y, f)
+	if f.Degree() < 0 {
+		flint.Panic("fmpz.ModPoly.ComposeMod", flint.ErrDivisionByZero)
+	}
+	t := NewModPoly(z.r)
+	C.fmpz_mod_poly_rem(&t.p, &y.p, &f.p, z.ctx())
+	C.fmpz_mod_poly_compose_mod(&z.p, &x.p, &t.p, &f.p, z.ctx())
+	return z
+}
+
+// Evaluate sets y to the value of z at a and returns y.
+func (z *ModPoly) Evaluate(y, a *Int) *Int {
+	t := z.r.Reduce(NewInt(0), a)
+	C.fmpz_mod_poly_evaluate_fmpz((*C.fmpz)(y), &z.p, (*C.fmpz)(t), z.ctx())
+	return y
+}
+
+// IsIrreducible reports whether z is irreducible.  The modulus
+// must be prime.
+func (z *ModPoly) IsIrreducible() bool {
+	return C.fmpz_mod_poly_is_irreducible(&z.p, z.ctx()) != 0
+}
+
+// Roots returns the distinct roots of z in Z/pZ together with their
+// multiplicities.  The modulus must be prime.  If z is zero, Roots
+// panics with flint.ErrDomain.
+func (z *ModPoly) Roots() ([]*Int, []int) {
+	if z.Degree() < 0 {
+		flint.Panic("fmpz.ModPoly.Roots", flint.ErrDomain)
+	}
+	var f C.fmpz_mod_poly_factor_struct
+	C.fmpz_mod_poly_factor_init(&f, z.ctx())
+	defer C.fmpz_mod_poly_factor_clear(&f, z.ctx())
+	C.fmpz_mod_poly_roots(&f, &z.p, 1, z.ctx())
+
+	n := int(f.num)
+	if n == 0 {
+		return nil, nil
+	}
+	ps := unsafe.Slice(f.poly, n)
+	es := unsafe.Slice(f.exp, n)
+	roots := make([]*Int, n)
+	mult := make([]int, n)
+	for i := 0; i < n; i++ {
+		// Each factor is monic and linear, x - a.
+		roots[i] = NewInt(0)
+		C.fmpz_mod_poly_get_coeff_fmpz((*C.fmpz)(roots[i]), &ps[i], 0, z.ctx())
+		z.r.Neg(roots[i], roots[i])
+		mult[i] = int(es[i])
+	}
+	return roots, mult
+}
+
+// Factor returns the factorisation of z into monic irreducible
+// polynomials as two slices of the same length: the factors and
+// their multiplicities.  The leading coefficient of z is returned
+// separately.  The modulus must be prime.  If z is zero, Factor
+// panics with flint.ErrDomain.
+func (z *ModPoly) Factor() (lead *Int, fs []*ModPoly, es []int) {
+	d := z.Degree()
+	if d < 0 {
+		flint.Panic("fmpz.ModPoly.Factor", flint.ErrDomain)
+	}
+	lead = z.Coeff(d)
+
+	var f C.fmpz_mod_poly_factor_struct
+	C.fmpz_mod_poly_factor_init(&f, z.ctx())
+	defer C.fmpz_mod_poly_factor_clear(&f, z.ctx())
+	C.fmpz_mod_poly_factor(&f, &z.p, z.ctx())
+
+	n := int(f.num)
+	if n == 0 {
+		return lead, nil, nil
+	}
+	ps := unsafe.Slice(f.poly, n)
+	exps := unsafe.Slice(f.exp, n)
+	fs = make([]*ModPoly, n)
+	es = make([]int, n)
+	for i := 0; i < n; i++ {
+		fs[i] = NewModPoly(z.r)
+		C.fmpz_mod_poly_set(&fs[i].p, &ps[i], z.ctx())
+		es[i] = int(exps[i])
+	}
+	return lead, fs, es
+}
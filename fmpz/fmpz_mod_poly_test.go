@@ -0,0 +1,151 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// modPoly returns the polynomial over r with coefficients c, starting
+// with the constant term.
+func modPoly(r *ModRing, c ...int64) *ModPoly {
+	cs := make([]*Int, len(c))
+	for i := range c {
+		cs[i] = NewInt(c[i])
+	}
+	return NewModPolyCoeffs(r, cs)
+}
+
+func TestModPolyArith(t *testing.T) {
+	r := NewModRing(NewInt(7))
+	a := modPoly(r, 1, 1)    // x + 1
+	b := modPoly(r, 2, 1)    // x + 2
+	f := modPoly(r, 1, 0, 1) // x^2 + 1
+	for _, tc := range []struct {
+		name string
+		got  *ModPoly
+		want string
+	}{
+		{"Mul", NewModPoly(r).Mul(a, b), "1*x^2+3*x+2"},
+		{"Add", NewModPoly(r).Add(a, b), "2*x+3"},
+		{"Sub", NewModPoly(r).Sub(a, b), "6"},
+		{"Neg", NewModPoly(r).Neg(a), "6*x+6"},
+		{"ScalarMul", NewModPoly(r).ScalarMul(a, NewInt(-2)), "5*x+5"},
+		{"Derivative", NewModPoly(r).Derivative(NewModPoly(r).Mul(a, b)), "2*x+3"},
+		{"Compose", NewModPoly(r).Compose(f, a), "1*x^2+2*x+2"},
+		// x^7 = (x^2)^3 * x = -x mod x^2 + 1.
+		{"PowMod", NewModPoly(r).PowMod(modPoly(r, 0, 1), NewInt(7), f), "6*x"},
+		// (x + 1)^2 = 2x mod x^2 + 1.
+		{"ComposeMod", NewModPoly(r).ComposeMod(modPoly(r, 0, 0, 1), a, f), "2*x"},
+		{"Zero", NewModPoly(r).Sub(a, a), "0"},
+	} {
+		if s := tc.got.String(); s != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, s, tc.want)
+		}
+	}
+	if y := NewModPoly(r).Mul(a, b).Evaluate(NewInt(0), NewInt(5)); y.Sign() != 0 {
+		t.Errorf("(x^2+3x+2)(5) = %v, want 0", y)
+	}
+	if NewModPoly(r).Mul(a, b).Degree() != 2 || NewModPoly(r).Degree() != -1 {
+		t.Errorf("Degree gives wrong results")
+	}
+}
+
+func TestModPolyDivision(t *testing.T) {
+	r := NewModRing(NewInt(7))
+	x := modPoly(r, 1, 0, 1) // x^2 + 1
+	y := modPoly(r, 1, 1)    // x + 1
+	q, m, ok := NewModPoly(r).DivMod(x, y, NewModPoly(r))
+	if !ok || q.String() != "1*x+6" || m.String() != "2" {
+		t.Errorf("DivMod(x^2+1, x+1) = %v, %v, %v, want x+6, 2", q, m, ok)
+	}
+
+	u := NewModPoly(r).Mul(y, modPoly(r, 2, 1))
+	v := NewModPoly(r).Mul(y, modPoly(r, 3, 1))
+	if g := NewModPoly(r).GCD(u, v); g.String() != "1*x+1" {
+		t.Errorf("GCD = %v, want x+1", g)
+	}
+	s, tt := NewModPoly(r), NewModPoly(r)
+	g := NewModPoly(r).XGCD(s, tt, u, v)
+	lhs := NewModPoly(r).Add(NewModPoly(r).Mul(s, u), NewModPoly(r).Mul(tt, v))
+	if g.String() != "1*x+1" || !lhs.Equal(g) {
+		t.Errorf("XGCD = %v, %v, %v", g, s, tt)
+	}
+
+	// Over Z/12Z, 2x + 1 is not monic-able and not a valid divisor.
+	r12 := NewModRing(NewInt(12))
+	d := modPoly(r12, 1, 2)
+	if _, ok := NewModPoly(r12).MakeMonic(d); ok {
+		t.Errorf("MakeMonic(2x+1) mod 12 succeeded")
+	}
+	if _, _, ok := NewModPoly(r12).DivMod(modPoly(r12, 1, 0, 1), d, NewModPoly(r12)); ok {
+		t.Errorf("DivMod by 2x+1 mod 12 succeeded")
+	}
+
+	zero := NewModPoly(r)
+	for name, f := range map[string]func(){
+		"DivMod":     func() { NewModPoly(r).DivMod(x, zero, NewModPoly(r)) },
+		"PowMod":     func() { NewModPoly(r).PowMod(x, NewInt(3), zero) },
+		"ComposeMod": func() { NewModPoly(r).ComposeMod(x, y, zero) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDivisionByZero) {
+			t.Errorf("%s by zero: err = %v, want ErrDivisionByZero", name, err)
+		}
+	}
+	for name, f := range map[string]func(){
+		"PowMod":     func() { NewModPoly(r).PowMod(x, NewInt(-1), y) },
+		"Roots":      func() { zero.Roots() },
+		"Factor":     func() { zero.Factor() },
+		"NewModRing": func() { NewModRing(NewInt(0)) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDomain) {
+			t.Errorf("%s: err = %v, want ErrDomain", name, err)
+		}
+	}
+}
+
+func TestModPolyFactor(t *testing.T) {
+	r := NewModRing(NewInt(7))
+	a := modPoly(r, 1, 1)    // x + 1
+	f := modPoly(r, 1, 0, 1) // x^2 + 1, irreducible as 7 = 3 mod 4
+	if !f.IsIrreducible() || NewModPoly(r).Mul(a, a).IsIrreducible() {
+		t.Errorf("IsIrreducible gives wrong results")
+	}
+
+	// (x + 1)^2 (x + 2) has roots 6 and 5.
+	p := NewModPoly(r).Mul(NewModPoly(r).Mul(a, a), modPoly(r, 2, 1))
+	roots, mult := p.Roots()
+	got := map[int64]int{}
+	for i := range roots {
+		got[roots[i].Int64()] = mult[i]
+	}
+	if len(got) != 2 || got[6] != 2 || got[5] != 1 {
+		t.Errorf("Roots = %v, %v, want 6 (twice) and 5", roots, mult)
+	}
+	if roots, _ := f.Roots(); len(roots) != 0 {
+		t.Errorf("Roots(x^2+1) = %v, want none", roots)
+	}
+
+	// 3 (x + 1)^2 (x^2 + 1).
+	p = NewModPoly(r).Mul(NewModPoly(r).Mul(a, a), f)
+	p.ScalarMul(p, NewInt(3))
+	lead, fs, es := p.Factor()
+	if lead.Int64() != 3 || len(fs) != 2 {
+		t.Fatalf("Factor = %v, %v, %v, want 3, [x+1 x^2+1], [2 1]", lead, fs, es)
+	}
+	for i := range fs {
+		switch s := fs[i].String(); {
+		case s == "1*x+1" && es[i] == 2:
+		case s == "1*x^2+1" && es[i] == 1:
+		default:
+			t.Errorf("Factor: unexpected factor %s^%d", s, es[i])
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpz
+
+import "testing"
+
+func TestModRing(t *testing.T) {
+	r := NewModRing(NewInt(101))
+	if s := r.String(); s != "Z/101Z" {
+		t.Errorf("String = %q, want Z/101Z", s)
+	}
+	for _, tc := range []struct {
+		name string
+		got  *Int
+		want int64
+	}{
+		{"Reduce", r.Reduce(NewInt(0), NewInt(-1)), 100},
+		{"Add", r.Add(NewInt(0), NewInt(100), NewInt(5)), 4},
+		{"Sub", r.Sub(NewInt(0), NewInt(3), NewInt(5)), 99},
+		{"Neg", r.Neg(NewInt(0), NewInt(1)), 100},
+		{"Neg0", r.Neg(NewInt(0), NewInt(0)), 0},
+		{"Mul", r.Mul(NewInt(0), NewInt(50), NewInt(3)), 49},
+	} {
+		if tc.got.Int64() != tc.want {
+			t.Errorf("%s = %v, want %d", tc.name, tc.got, tc.want)
+		}
+	}
+	if !r.IsReduced(NewInt(100)) || r.IsReduced(NewInt(101)) || r.IsReduced(NewInt(-1)) {
+		t.Errorf("IsReduced gives wrong results")
+	}
+
+	if z, ok := r.Inv(NewInt(0), NewInt(3)); !ok || z.Int64() != 34 {
+		t.Errorf("Inv(3) = %v, %v, want 34", z, ok)
+	}
+	if z, ok := r.Pow(NewInt(0), NewInt(2), NewInt(100)); !ok || z.Int64() != 1 {
+		t.Errorf("2^100 = %v, %v, want 1", z, ok)
+	}
+	if z, ok := r.Pow(NewInt(0), NewInt(3), NewInt(-1)); !ok || z.Int64() != 34 {
+		t.Errorf("3^-1 = %v, %v, want 34", z, ok)
+	}
+	if z, ok := r.Sqrt(NewInt(0), NewInt(4)); !ok || z.Int64() != 2 && z.Int64() != 99 {
+		t.Errorf("Sqrt(4) = %v, %v, want 2 or 99", z, ok)
+	}
+	// 101 = 5 mod 8, so 2 is not a square.
+	if z, ok := r.Sqrt(NewInt(7), NewInt(2)); ok || z.Int64() != 7 {
+		t.Errorf("Sqrt(2) = %v, %v, want 7 (unchanged), false", z, ok)
+	}
+
+	r12 := NewModRing(NewInt(12))
+	if z, ok := r12.Inv(NewInt(5), NewInt(4)); ok || z.Int64() != 5 {
+		t.Errorf("Inv(4) mod 12 = %v, %v, want 5 (unchanged), false", z, ok)
+	}
+	if _, ok := r12.Pow(NewInt(0), NewInt(4), NewInt(-2)); ok {
+		t.Errorf("4^-2 mod 12 succeeded")
+	}
+
+	// A multi-precision modulus.
+	p := mustInt(t, "170141183460469231731687303715884105727")
+	rp := NewModRing(p)
+	x := mustInt(t, "123456789012345678901234567890")
+	y, ok := rp.Inv(NewInt(0), x)
+	if !ok || rp.Mul(y, y, x).Int64() != 1 {
+		t.Errorf("x * x^-1 mod 2^127-1 = %v, %v, want 1", y, ok)
+	}
+	if m := rp.Modulus(); m.Cmp(p) != 0 {
+		t.Errorf("Modulus = %v, want %v", m, p)
+	}
+}
+
+func TestModRingVec(t *testing.T) {
+	r := NewModRing(NewInt(101))
+	x := []*Int{NewInt(1), NewInt(2), NewInt(3), NewInt(100)}
+	z := []*Int{NewInt(0), NewInt(0), NewInt(0), NewInt(0)}
+	if _, ok := r.InvVec(z, x); !ok {
+		t.Fatalf("InvVec failed")
+	}
+	for i := range z {
+		if r.Mul(NewInt(0), z[i], x[i]).Int64() != 1 {
+			t.Errorf("InvVec: %v * %v != 1", x[i], z[i])
+		}
+	}
+	// In place.
+	y := []*Int{NewInt(1), NewInt(2), NewInt(3), NewInt(100)}
+	r.InvVec(y, y)
+	for i := range y {
+		if y[i].Cmp(z[i]) != 0 {
+			t.Errorf("InvVec in place: %v, want %v", y, z)
+			break
+		}
+	}
+
+	r.MulVec(z, x, x)
+	r.AddVec(z, z, x)
+	for i, want := range []int64{2, 6, 12, 0} {
+		if z[i].Int64() != want {
+			t.Errorf("x^2 + x = %v, want 2 6 12 0", z)
+			break
+		}
+	}
+	if _, ok := r.PowVec(z, x, NewInt(-2)); !ok {
+		t.Fatalf("PowVec failed")
+	}
+	for i := range z {
+		if r.Mul(NewInt(0), z[i], r.Mul(NewInt(0), x[i], x[i])).Int64() != 1 {
+			t.Errorf("PowVec(-2): %v * %v^2 != 1", z[i], x[i])
+		}
+	}
+
+	r12 := NewModRing(NewInt(12))
+	z = []*Int{NewInt(0), NewInt(0)}
+	if _, ok := r12.InvVec(z, []*Int{NewInt(5), NewInt(4)}); ok {
+		t.Errorf("InvVec with a non-unit succeeded")
+	}
+}
+
+func TestModRingCRT(t *testing.T) {
+	rs := []*ModRing{NewModRing(NewInt(3)), NewModRing(NewInt(5)), NewModRing(NewInt(1)), NewModRing(NewInt(7))}
+	z, m, ok := ModRingCRT(rs, []*Int{NewInt(2), NewInt(3), NewInt(0), NewInt(2)})
+	if !ok || z.Int64() != 23 || m.Modulus().Int64() != 105 {
+		t.Errorf("ModRingCRT = %v, %v, %v, want 23, Z/105Z", z, m, ok)
+	}
+	if _, _, ok := ModRingCRT(rs[:2], []*Int{NewInt(3), NewInt(1)}); ok {
+		t.Errorf("ModRingCRT with an unreduced residue succeeded")
+	}
+	rs = []*ModRing{NewModRing(NewInt(4)), NewModRing(NewInt(6))}
+	if _, _, ok := ModRingCRT(rs, []*Int{NewInt(1), NewInt(1)}); ok {
+		t.Errorf("ModRingCRT with non-coprime moduli succeeded")
+	}
+	if _, _, ok := ModRingCRT(nil, nil); ok {
+		t.Errorf("ModRingCRT of nothing succeeded")
+	}
+}
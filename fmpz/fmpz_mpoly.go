@@ -0,0 +1,428 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpz
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_vec.h>
+// #include <flint/mpoly.h>
+// #include <flint/fmpz_mpoly.h>
+// #include <flint/fmpz_mpoly_factor.h>
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/internal/util"
+)
+
+// An Ordering is a monomial ordering for multivariate polynomials.
+type Ordering int
+
+const (
+	// Lex is the lexicographic ordering.
+	Lex Ordering = C.ORD_LEX
+	// DegLex is the graded lexicographic ordering.
+	DegLex Ordering = C.ORD_DEGLEX
+	// DegRevLex is the graded reverse lexicographic ordering.
+	DegRevLex Ordering = C.ORD_DEGREVLEX
+)
+
+// String returns the name of o.
+func (o Ordering) String() string {
+	switch o {
+	case Lex:
+		return "lex"
+	case DegLex:
+		return "deglex"
+	case DegRevLex:
+		return "degrevlex"
+	}
+	return "unknown"
+}
+
+// An MPolyCtx describes a ring Z[x_1, ..., x_n] of multivariate
+// polynomials with named variables and a monomial ordering.
+type MPolyCtx struct {
+	c    C.fmpz_mpoly_ctx_struct
+	vars []string
+	cv   **C.char
+}
+
+// NewMPolyCtx returns the polynomial ring over Z in the given
+// variables with monomial ordering ord.
+func NewMPolyCtx(vars []string, ord Ordering) *MPolyCtx {
+	if len(vars) == 0 {
+		panic("fmpz: MPolyCtx needs at least one variable")
+	}
+	c := &MPolyCtx{vars: append([]string(nil), vars...)}
+	c.cv = (**C.char)(util.CStrings(vars))
+	C.fmpz_mpoly_ctx_init(&c.c, C.slong(len(vars)), C.ordering_t(ord))
+	runtime.SetFinalizer(c, (*MPolyCtx).destroy)
+	return c
+}
+
+func (c *MPolyCtx) destroy() {
+	C.fmpz_mpoly_ctx_clear(&c.c)
+	util.FreeCStrings(unsafe.Pointer(c.cv), len(c.vars))
+}
+
+// Vars returns the names of the variables of c.
+func (c *MPolyCtx) Vars() []string {
+	return append([]string(nil), c.vars...)
+}
+
+// NVars returns the number of variables of c.
+func (c *MPolyCtx) NVars() int {
+	return len(c.vars)
+}
+
+// Ordering returns the monomial ordering of c.
+func (c *MPolyCtx) Ordering() Ordering {
+	return Ordering(C.fmpz_mpoly_ctx_ord(&c.c))
+}
+
+// An MPoly represents a multivariate polynomial with integer
+// coefficients.  Its terms are kept sorted in decreasing order
+// with respect to the monomial ordering of its context.
+type MPoly struct {
+	p C.fmpz_mpoly_struct
+	c *MPolyCtx
+}
+
+// NewMPoly returns the zero polynomial in c.
+func NewMPoly(c *MPolyCtx) *MPoly {
+	z := &MPoly{c: c}
+	C.fmpz_mpoly_init(&z.p, &c.c)
+	runtime.SetFinalizer(z, (*MPoly).destroy)
+	return z
+}
+
+func (z *MPoly) destroy() {
+	C.fmpz_mpoly_clear(&z.p, &z.c.c)
+}
+
+func (z *MPoly) ctx() *C.fmpz_mpoly_ctx_struct {
+	return &z.c.c
+}
+
+// same panics unless all of xs belong to the context of z.
+func (z *MPoly) same(xs ...*MPoly) {
+	for _, x := range xs {
+		if x.c != z.c {
+			panic("fmpz: MPoly operands belong to different contexts")
+		}
+	}
+}
+
+// Ctx returns the context of z.
+func (z *MPoly) Ctx() *MPolyCtx {
+	return z.c
+}
+
+// SetString sets z to the value of s, a polynomial expression in
+// the variables of the context such as "2*x^2*y - 3*(y + 1)^2",
+// and returns (z, true).  If s cannot be parsed, SetString returns
+// (nil, false).
+func (z *MPoly) SetString(s string) (*MPoly, bool) {
+	p := C.CString(s)
+	defer C.free(unsafe.Pointer(p))
+	if C.fmpz_mpoly_set_str_pretty(&z.p, p, z.c.cv, z.ctx()) != 0 {
+		return nil, false
+	}
+	return z, true
+}
+
+// String returns a string representation of z in the variables
+// of its context.
+func (z *MPoly) String() string {
+	p := C.fmpz_mpoly_get_str_pretty(&z.p, z.c.cv, z.ctx())
+	defer C.free(unsafe.Pointer(p))
+	return C.GoString(p)
+}
+
+// Set sets z = x and returns z.
+func (z *MPoly) Set(x *MPoly) *MPoly {
+	z.same(x)
+	C.fmpz_mpoly_set(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// SetInt64 sets z to the constant x and returns z.
+func (z *MPoly) SetInt64(x int64) *MPoly {
+	C.fmpz_mpoly_set_si(&z.p, C.slong(x), z.ctx())
+	return z
+}
+
+// SetInt sets z to the constant x and returns z.
+func (z *MPoly) SetInt(x *Int) *MPoly {
+	C.fmpz_mpoly_set_fmpz(&z.p, (*C.fmpz)(x), z.ctx())
+	return z
+}
+
+// SetVar sets z to the i-th variable of its context and returns z.
+func (z *MPoly) SetVar(i int) *MPoly {
+	util.CheckVar("fmpz", i, z.c.NVars())
+	C.fmpz_mpoly_gen(&z.p, C.slong(i), z.ctx())
+	return z
+}
+
+// SetTerms sets z to the sum of the terms cs[i] * x^es[i], where
+// es[i] holds one exponent per variable, and returns z.
+func (z *MPoly) SetTerms(cs []*Int, es [][]uint64) *MPoly {
+	util.CheckLen("fmpz", len(cs), len(es))
+	C.fmpz_mpoly_zero(&z.p, z.ctx())
+	exp := make([]C.ulong, z.c.NVars())
+	for i, c := range cs {
+		util.CheckLen("fmpz", len(exp), len(es[i]))
+		for j, e := range es[i] {
+			exp[j] = C.ulong(e)
+		}
+		C.fmpz_mpoly_push_term_fmpz_ui(&z.p, (*C.fmpz)(c), &exp[0], z.ctx())
+	}
+	C.fmpz_mpoly_sort_terms(&z.p, z.ctx())
+	C.fmpz_mpoly_combine_like_terms(&z.p, z.ctx())
+	return z
+}
+
+// Len returns the number of terms of z.
+func (z *MPoly) Len() int {
+	return int(C.fmpz_mpoly_length(&z.p, z.ctx()))
+}
+
+// Term returns the coefficient and the exponent vector of the i-th
+// term of z, 0 <= i < z.Len().  Term 0 is the leading term.
+func (z *MPoly) Term(i int) (*Int, []uint64) {
+	if i < 0 || i >= z.Len() {
+		panic("fmpz: MPoly term index out of range")
+	}
+	c := NewInt(0)
+	C.fmpz_mpoly_get_term_coeff_fmpz((*C.fmpz)(c), &z.p, C.slong(i), z.ctx())
+	if C.fmpz_mpoly_term_exp_fits_ui(&z.p, C.slong(i), z.ctx()) == 0 {
+		panic("fmpz: MPoly exponent does not fit in a word")
+	}
+	exp := make([]C.ulong, z.c.NVars())
+	C.fmpz_mpoly_get_term_exp_ui(&exp[0], &z.p, C.slong(i), z.ctx())
+	e := make([]uint64, len(exp))
+	for j, v := range exp {
+		e[j] = uint64(v)
+	}
+	return c, e
+}
+
+// IsZero reports whether z is zero.
+func (z *MPoly) IsZero() bool {
+	return C.fmpz_mpoly_is_zero(&z.p, z.ctx()) != 0
+}
+
+// Equal reports whether z and x are equal.
+func (z *MPoly) Equal(x *MPoly) bool {
+	z.same(x)
+	return C.fmpz_mpoly_equal(&z.p, &x.p, z.ctx()) != 0
+}
+
+// Degree returns the degree of z in the i-th variable.  The degree
+// of the zero polynomial is -1.
+func (z *MPoly) Degree(i int) int {
+	util.CheckVar("fmpz", i, z.c.NVars())
+	return int(C.fmpz_mpoly_degree_si(&z.p, C.slong(i), z.ctx()))
+}
+
+// TotalDegree returns the total degree of z.  The total degree of
+// the zero polynomial is -1.
+func (z *MPoly) TotalDegree() int {
+	return int(C.fmpz_mpoly_total_degree_si(&z.p, z.ctx()))
+}
+
+// Add sets z = x + y and returns z.
+func (z *MPoly) Add(x, y *MPoly) *MPoly {
+	z.same(x, y)
+	C.fmpz_mpoly_add(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *MPoly) Sub(x, y *MPoly) *MPoly {
+	z.same(x, y)
+	C.fmpz_mpoly_sub(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *MPoly) Neg(x *MPoly) *MPoly {
+	z.same(x)
+	C.fmpz_mpoly_neg(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *MPoly) Mul(x, y *MPoly) *MPoly {
+	z.same(x, y)
+	C.fmpz_mpoly_mul(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// ScalarMul sets z = c*x and returns z.
+func (z *MPoly) ScalarMul(x *MPoly, c *Int) *MPoly {
+	z.same(x)
+	C.fmpz_mpoly_scalar_mul_fmpz(&z.p, &x.p, (*C.fmpz)(c), z.ctx())
+	return z
+}
+
+// Pow sets z = x^k and returns (z, true).  If the exponents of the
+// result would overflow, z is unchanged and Pow returns (z, false).
+func (z *MPoly) Pow(x *MPoly, k uint64) (*MPoly, bool) {
+	z.same(x)
+	t := NewMPoly(z.c)
+	if C.fmpz_mpoly_pow_ui(&t.p, &x.p, C.ulong(k), z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Div sets z = x / y and returns (z, true) if y divides x exactly.
+// Otherwise z is unchanged and Div returns (z, false).  If y is
+// zero, a division-by-zero run-time panic occurs.
+func (z *MPoly) Div(x, y *MPoly) (*MPoly, bool) {
+	z.same(x, y)
+	if y.IsZero() {
+		flint.Panic("fmpz.MPoly.Div", flint.ErrDivisionByZero)
+	}
+	t := NewMPoly(z.c)
+	if C.fmpz_mpoly_divides(&t.p, &x.p, &y.p, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// DivMod sets z to the quotient and m to the remainder of the
+// multivariate division of x by y with respect to the monomial
+// ordering, and returns (z, m).  If y is zero, a division-by-zero
+// run-time panic occurs.
+func (z *MPoly) DivMod(x, y, m *MPoly) (*MPoly, *MPoly) {
+	z.same(x, y, m)
+	if y.IsZero() {
+		flint.Panic("fmpz.MPoly.DivMod", flint.ErrDivisionByZero)
+	}
+	C.fmpz_mpoly_divrem(&z.p, &m.p, &x.p, &y.p, z.ctx())
+	return z, m
+}
+
+// GCD sets z to the greatest common divisor of x and y, with
+// positive leading coefficient, and returns (z, true).  If FLINT
+// cannot compute it, z is unchanged and GCD returns (z, false).
+func (z *MPoly) GCD(x, y *MPoly) (*MPoly, bool) {
+	z.same(x, y)
+	t := NewMPoly(z.c)
+	if C.fmpz_mpoly_gcd(&t.p, &x.p, &y.p, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Derivative sets z to the partial derivative of x with respect to
+// the i-th variable and returns z.
+func (z *MPoly) Derivative(x *MPoly, i int) *MPoly {
+	z.same(x)
+	util.CheckVar("fmpz", i, x.c.NVars())
+	C.fmpz_mpoly_derivative(&z.p, &x.p, C.slong(i), z.ctx())
+	return z
+}
+
+// Evaluate returns the value of z at the point vals, which holds
+// one value per variable, and true.  If the result is too large to
+// compute, Evaluate returns (nil, false).
+func (z *MPoly) Evaluate(vals []*Int) (*Int, bool) {
+	n := z.c.NVars()
+	util.CheckLen("fmpz", n, len(vals))
+	v := C._fmpz_vec_init(C.slong(n))
+	defer C._fmpz_vec_clear(v, C.slong(n))
+	vs := unsafe.Slice(v, n)
+	pp := C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(v)))
+	defer C.free(pp)
+	ps := unsafe.Slice((**C.fmpz)(pp), n)
+	for i, x := range vals {
+		C.fmpz_set(&vs[i], (*C.fmpz)(x))
+		ps[i] = &vs[i]
+	}
+	r := NewInt(0)
+	if C.fmpz_mpoly_evaluate_all_fmpz((*C.fmpz)(r), &z.p, (**C.fmpz)(pp), z.ctx()) == 0 {
+		return nil, false
+	}
+	return r, true
+}
+
+// EvaluateOne sets z to x with the i-th variable replaced by val
+// and returns (z, true).  If the result is too large to compute, z
+// is unchanged and EvaluateOne returns (z, false).
+func (z *MPoly) EvaluateOne(x *MPoly, i int, val *Int) (*MPoly, bool) {
+	z.same(x)
+	util.CheckVar("fmpz", i, x.c.NVars())
+	t := NewMPoly(z.c)
+	if C.fmpz_mpoly_evaluate_one_fmpz(&t.p, &x.p, C.slong(i), (*C.fmpz)(val), z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Compose sets z to x with the i-th variable of x replaced by
+// ys[i] and returns (z, true).  The context of x must have one
+// variable per entry of ys; z and all ys belong to a common,
+// possibly different context.  If the result is too large to
+// compute, z is unchanged and Compose returns (z, false).
+func (z *MPoly) Compose(x *MPoly, ys []*MPoly) (*MPoly, bool) {
+	n := x.c.NVars()
+	util.CheckLen("fmpz", n, len(ys))
+	z.same(ys...)
+
+	vals, ptrs := util.PointerArray(n, unsafe.Sizeof(C.fmpz_mpoly_struct{}))
+	defer util.Free(vals, ptrs)
+	cs := unsafe.Slice((*C.fmpz_mpoly_struct)(vals), n)
+	for i, y := range ys {
+		C.fmpz_mpoly_init(&cs[i], z.ctx())
+		defer C.fmpz_mpoly_clear(&cs[i], z.ctx())
+		C.fmpz_mpoly_set(&cs[i], &y.p, z.ctx())
+	}
+
+	t := NewMPoly(z.c)
+	if C.fmpz_mpoly_compose_fmpz_mpoly(&t.p, &x.p, (**C.fmpz_mpoly_struct)(ptrs), x.ctx(), z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Factor returns the factorisation of z into irreducible
+// polynomials: a constant, the factors and their multiplicities.
+// If FLINT cannot factor z, Factor returns ok == false.
+func (z *MPoly) Factor() (c *Int, fs []*MPoly, es []int, ok bool) {
+	var f C.fmpz_mpoly_factor_struct
+	C.fmpz_mpoly_factor_init(&f, z.ctx())
+	defer C.fmpz_mpoly_factor_clear(&f, z.ctx())
+	if C.fmpz_mpoly_factor(&f, &z.p, z.ctx()) == 0 {
+		return nil, nil, nil, false
+	}
+
+	c = NewInt(0)
+	C.fmpz_set((*C.fmpz)(c), &f.constant[0])
+	n := int(f.num)
+	if n == 0 {
+		return c, nil, nil, true
+	}
+	ps := unsafe.Slice(f.poly, n)
+	exps := unsafe.Slice(f.exp, n)
+	fs = make([]*MPoly, n)
+	es = make([]int, n)
+	for i := 0; i < n; i++ {
+		fs[i] = NewMPoly(z.c)
+		C.fmpz_mpoly_set(&fs[i].p, &ps[i], z.ctx())
+		es[i] = int(C.fmpz_get_si(&exps[i]))
+	}
+	return c, fs, es, true
+}
@@ -0,0 +1,132 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/internal/testutil"
+)
+
+func mpoly(t *testing.T, c *MPolyCtx, s string) *MPoly {
+	t.Helper()
+	return mustMPoly(t)(NewMPoly(c).SetString(s))
+}
+
+func TestMPolyArith(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, Lex)
+	if c.NVars() != 2 || c.Ordering() != Lex || c.Ordering().String() != "lex" || c.Vars()[1] != "y" {
+		t.Errorf("MPolyCtx has variables %v and ordering %v", c.Vars(), c.Ordering())
+	}
+	p := mpoly(t, c, "x^2 - y^2")
+	a := mpoly(t, c, "x + y")
+	b := mpoly(t, c, "x - y")
+	for _, tc := range []struct {
+		name      string
+		got, want *MPoly
+	}{
+		{"Mul", NewMPoly(c).Mul(a, b), p},
+		{"Add", NewMPoly(c).Add(a, b), mpoly(t, c, "2*x")},
+		{"Sub", NewMPoly(c).Sub(a, b), mpoly(t, c, "2*y")},
+		{"Neg", NewMPoly(c).Neg(b), mpoly(t, c, "y - x")},
+		{"ScalarMul", NewMPoly(c).ScalarMul(a, NewInt(-3)), mpoly(t, c, "-3*x - 3*y")},
+		{"SetVar", NewMPoly(c).SetVar(1), mpoly(t, c, "y")},
+		{"SetInt", NewMPoly(c).SetInt(NewInt(7)), NewMPoly(c).SetInt64(7)},
+		{"SetTerms", NewMPoly(c).SetTerms([]*Int{NewInt(1), NewInt(-1)}, [][]uint64{{2, 0}, {0, 2}}), p},
+		{"Derivative", NewMPoly(c).Derivative(p, 1), mpoly(t, c, "-2*y")},
+		{"EvaluateOne", mustMPoly(t)(NewMPoly(c).EvaluateOne(p, 0, NewInt(2))), mpoly(t, c, "4 - y^2")},
+		{"Compose", mustMPoly(t)(NewMPoly(c).Compose(p, []*MPoly{mpoly(t, c, "y"), mpoly(t, c, "x")})), NewMPoly(c).Neg(p)},
+		{"Pow", mustMPoly(t)(NewMPoly(c).Pow(a, 2)), mpoly(t, c, "x^2 + 2*x*y + y^2")},
+		{"Div", mustMPoly(t)(NewMPoly(c).Div(p, a)), b},
+		{"GCD", mustMPoly(t)(NewMPoly(c).GCD(p, mpoly(t, c, "x^2 + 2*x*y + y^2"))), a},
+	} {
+		if !tc.got.Equal(tc.want) {
+			t.Errorf("%s = %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+
+	if v, ok := p.Evaluate([]*Int{NewInt(3), NewInt(1)}); !ok || v.Int64() != 8 {
+		t.Errorf("p(3, 1) = %v, %v, want 8", v, ok)
+	}
+	if p.Degree(0) != 2 || p.Degree(1) != 2 || a.TotalDegree() != 1 || NewMPoly(c).TotalDegree() != -1 {
+		t.Errorf("Degree and TotalDegree give wrong results")
+	}
+	if _, ok := NewMPoly(c).Div(p, mpoly(t, c, "x + 2")); ok {
+		t.Errorf("(x^2 - y^2)/(x + 2) succeeded")
+	}
+	q, r := NewMPoly(c).DivMod(mpoly(t, c, "x^2 + y"), mpoly(t, c, "x"), NewMPoly(c))
+	if !q.Equal(mpoly(t, c, "x")) || !r.Equal(mpoly(t, c, "y")) {
+		t.Errorf("DivMod(x^2 + y, x) = %v, %v, want x, y", q, r)
+	}
+
+	// Under lex, x^2 is the leading term of p.
+	if p.Len() != 2 {
+		t.Errorf("Len = %d, want 2", p.Len())
+	}
+	if k, e := p.Term(0); k.Int64() != 1 || e[0] != 2 || e[1] != 0 {
+		t.Errorf("Term(0) = %v, %v, want 1, [2 0]", k, e)
+	}
+	if k, e := p.Term(1); k.Int64() != -1 || e[0] != 0 || e[1] != 2 {
+		t.Errorf("Term(1) = %v, %v, want -1, [0 2]", k, e)
+	}
+}
+
+var mustMPoly = testutil.Must[*MPoly]
+
+func TestMPolyFactor(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y", "z"}, DegRevLex)
+	p := mpoly(t, c, "2*x^2*z - 2*y^2*z")
+	k, fs, es, ok := p.Factor()
+	if !ok || k.Int64() != 2 || len(fs) != 3 {
+		t.Fatalf("Factor = %v, %v, %v, %v", k, fs, es, ok)
+	}
+	prod := NewMPoly(c).SetInt(k)
+	for i := range fs {
+		if es[i] != 1 {
+			t.Errorf("factor %v has multiplicity %d, want 1", fs[i], es[i])
+		}
+		prod.Mul(prod, fs[i])
+	}
+	if !prod.Equal(p) {
+		t.Errorf("product of the factors = %v, want %v", prod, p)
+	}
+}
+
+func TestMPolyErrors(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, DegLex)
+	for _, s := range []string{"x +", "z", "x^y"} {
+		if _, ok := NewMPoly(c).SetString(s); ok {
+			t.Errorf("SetString(%q) succeeded", s)
+		}
+	}
+	p := mpoly(t, c, "x*y + 1")
+	zero := NewMPoly(c)
+	for name, f := range map[string]func(){
+		"Div":    func() { NewMPoly(c).Div(p, zero) },
+		"DivMod": func() { NewMPoly(c).DivMod(p, zero, NewMPoly(c)) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDivisionByZero) {
+			t.Errorf("%s by zero: err = %v, want ErrDivisionByZero", name, err)
+		}
+	}
+	for name, f := range map[string]func(){
+		"Degree(-1)":     func() { p.Degree(-1) },
+		"Degree(2)":      func() { p.Degree(2) },
+		"SetVar(2)":      func() { NewMPoly(c).SetVar(2) },
+		"Derivative(2)":  func() { NewMPoly(c).Derivative(p, 2) },
+		"EvaluateOne(5)": func() { NewMPoly(c).EvaluateOne(p, 5, NewInt(1)) },
+		"Evaluate":       func() { p.Evaluate([]*Int{NewInt(1)}) },
+		"Term(2)":        func() { p.Term(2) },
+		"other context":  func() { NewMPoly(NewMPolyCtx([]string{"x", "y"}, DegLex)).Add(p, p) },
+	} {
+		if !testutil.Misuse("fmpz", f) {
+			t.Errorf("%s did not report misuse", name)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpz
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// An Int represents a signed multi-precision integer.
+//
+// This is the pure Go backend, used when go.flint is built with
+// the noflint tag or without cgo.  It is implemented on math/big
+// and has the same methods and semantics as the FLINT backend,
+// except that its zero value is a valid 0.
+type Int big.Int
+
+// NewInt returns a new Int initialized to x.
+func NewInt(x int64) *Int { return new(Int).SetInt64(x) }
+
+func (z *Int) big() *big.Int { return (*big.Int)(z) }
+
+// Clear the allocated space used by the number
+//
+// This normally happens on a runtime.SetFinalizer call, but if you
+// want immediate deallocation you can call it.
+//
+// NB This is not part of big.Int
+func (z *Int) Clear() {
+	z.big().SetInt64(0)
+}
+
+// Len returns the length of z in bits.  0 is considered to
+// have length 1.
+func (z *Int) Len() int {
+	return max(z.big().BitLen(), 1)
+}
+
+// Set sets z = x and returns z.
+func (z *Int) Set(x *Int) *Int {
+	z.big().Set(x.big())
+	return z
+}
+
+// SetInt64 sets z = x and returns z.
+func (z *Int) SetInt64(x int64) *Int {
+	z.big().SetInt64(x)
+	return z
+}
+
+// SetUint64 sets z = x and returns z.
+func (z *Int) SetUint64(x uint64) *Int {
+	z.big().SetUint64(x)
+	return z
+}
+
+// SetString interprets s as a number in the given base
+// and sets z to that value.  The base must be in the range [2,36].
+// SetString returns false if s cannot be parsed or the base is invalid;
+// use ParseInt to get an error instead.
+func (z *Int) SetString(s string, base int) (*Int, bool) {
+	if base != 0 && (base < 2 || base > 36) {
+		return nil, false
+	}
+	// Like mpz_set_str, ignore white space, and accept neither the
+	// underscores nor the 0o prefix math/big allows.
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > 1 && s[0] == '+' {
+		s = s[1:]
+	}
+	t := strings.TrimPrefix(s, "-")
+	if strings.Contains(s, "_") || base == 0 && (strings.HasPrefix(t, "0o") || strings.HasPrefix(t, "0O")) {
+		return nil, false
+	}
+	if _, ok := z.big().SetString(s, base); !ok {
+		return nil, false
+	}
+	return z, true
+}
+
+// ParseInt returns the Int represented by s in the given base, as
+// SetString does.  If s cannot be parsed or the base is invalid,
+// the error is a *strconv.NumError.
+func ParseInt(s string, base int) (*Int, error) {
+	z, ok := NewInt(0).SetString(s, base)
+	if !ok {
+		err := strconv.ErrSyntax
+		if base != 0 && (base < 2 || base > 36) {
+			err = errors.New("invalid base " + strconv.Itoa(base))
+		}
+		return nil, &strconv.NumError{Func: "ParseInt", Num: s, Err: err}
+	}
+	return z, nil
+}
+
+// String returns the decimal representation of z.
+func (z *Int) String() string {
+	if z == nil {
+		return "nil"
+	}
+	return z.big().String()
+}
+
+// Add sets z = x + y and returns z.
+func (z *Int) Add(x, y *Int) *Int {
+	z.big().Add(x.big(), y.big())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *Int) Sub(x, y *Int) *Int {
+	z.big().Sub(x.big(), y.big())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *Int) Mul(x, y *Int) *Int {
+	z.big().Mul(x.big(), y.big())
+	return z
+}
+
+// Div sets z = x / y, rounding toward zero, and returns z.
+// If y == 0, a division-by-zero run-time panic occurs.
+func (z *Int) Div(x, y *Int) *Int {
+	if y.Sign() == 0 {
+		flint.Panic("fmpz.Int.Div", flint.ErrDivisionByZero)
+	}
+	z.big().Quo(x.big(), y.big())
+	return z
+}
+
+// TryDiv is like Div, but if y == 0 it leaves z unchanged and
+// returns an *flint.Error wrapping flint.ErrDivisionByZero instead
+// of panicking.
+func (z *Int) TryDiv(x, y *Int) (*Int, error) {
+	if y.Sign() == 0 {
+		return z, &flint.Error{Op: "fmpz.Int.TryDiv", Err: flint.ErrDivisionByZero}
+	}
+	return z.Div(x, y), nil
+}
+
+// Lsh sets z = x << s and returns z.
+func (z *Int) Lsh(x *Int, s uint) *Int {
+	z.big().Lsh(x.big(), s)
+	return z
+}
+
+// maxPowBits bounds the size in bits of the powers Exp computes
+// without a modulus, as in the FLINT backend, whose GMP integers
+// have at most 2^31-1 limbs.
+const maxPowBits = (1<<31 - 1) * bits.UintSize
+
+// pow sets z = x^y and returns z.
+func (z *Int) pow(x *Int, y uint64) *Int {
+	z.big().Exp(x.big(), new(big.Int).SetUint64(y), nil)
+	return z
+}
+
+// powMod sets z = x^y mod m for y >= 0 and m > 0 and returns z.
+func (z *Int) powMod(x, y, m *Int) *Int {
+	// big.Int.Exp does not allow z to alias m.
+	n := m.big()
+	if z == m {
+		n = new(big.Int).Set(n)
+	}
+	z.big().Exp(x.big(), y.big(), n)
+	return z
+}
+
+// invMod sets z to the inverse of x mod m > 0 and returns (z, true).
+// If x is not invertible, z is unchanged and invMod returns
+// (z, false).
+func (z *Int) invMod(x, m *Int) (*Int, bool) {
+	t := new(big.Int).ModInverse(x.big(), m.big())
+	if t == nil {
+		return z, false
+	}
+	z.big().Set(t)
+	return z, true
+}
+
+// word returns the low 64 bits of z >= 0.
+func (z *Int) word() uint64 {
+	return z.big().Uint64()
+}
+
+// bit returns the value of bit i of z >= 0.
+func (z *Int) bit(i int) uint {
+	return z.big().Bit(i)
+}
+
+// Int64 returns the value of z as a int64.
+// TODO(What happens if this is not possible?)
+func (z *Int) Int64() int64 {
+	return z.big().Int64()
+}
+
+// Neg sets z = -x and returns z.
+func (z *Int) Neg(x *Int) *Int {
+	z.big().Neg(x.big())
+	return z
+}
+
+// Abs sets z to the absolute value of x and returns z.
+func (z *Int) Abs(x *Int) *Int {
+	z.big().Abs(x.big())
+	return z
+}
+
+// Sign returns:
+//
+//	-1 if x <  0
+//	 0 if x == 0
+//	+1 if x >  0
+func (z *Int) Sign() int {
+	return z.big().Sign()
+}
+
+// Cmp compares z and y and returns:
+//
+//	-1 if z <  y
+//	 0 if z == y
+//	+1 if z >  y
+func (z *Int) Cmp(y *Int) (r int) {
+	return z.big().Cmp(y.big())
+}
+
+// Rsh sets z = x >> n and returns z.
+func (z *Int) Rsh(x *Int, n uint) *Int {
+	z.big().Rsh(x.big(), n)
+	return z
+}
+
+// BitLen returns the length of the absolute value of z in bits.
+// The bit length of 0 is 0.
+func (z *Int) BitLen() int {
+	return z.big().BitLen()
+}
+
+// Jacobi returns the Jacobi symbol (x/y), either +1, -1, or 0.
+// The y argument must be an odd integer.
+func Jacobi(x, y *Int) int {
+	if y.big().Bit(0) == 0 {
+		panic(fmt.Sprintf("big: invalid 2nd argument to Int.Jacobi: need odd integer but got %s", y))
+	}
+	return big.Jacobi(x.big(), y.big())
+}
+
+// Mod sets z to the modulus x%y for y != 0 and returns z.
+// If y == 0, a division-by-zero run-time panic occurs.
+// Mod implements Euclidean modulus (unlike Go); see DivMod for more details.
+func (z *Int) Mod(x, y *Int) *Int {
+	if y.Sign() == 0 {
+		flint.Panic("fmpz.Int.Mod", flint.ErrDivisionByZero)
+	}
+	z.big().Mod(x.big(), y.big())
+	return z
+}
+
+// DivMod sets z to the quotient x div y and m to the modulus x mod y
+// and returns the pair (z, m) for y != 0.
+// If y == 0, a division-by-zero run-time panic occurs.
+//
+// Like the FLINT backend, DivMod rounds the quotient toward minus
+// infinity, so m has the sign of y.
+func (z *Int) DivMod(x, y, m *Int) (*Int, *Int) {
+	if y.Sign() == 0 {
+		flint.Panic("fmpz.Int.DivMod", flint.ErrDivisionByZero)
+	}
+	y0 := new(big.Int).Set(y.big())
+	z.big().QuoRem(x.big(), y0, m.big())
+	if m.Sign() != 0 && m.Sign() != y0.Sign() {
+		z.big().Sub(z.big(), big.NewInt(1))
+		m.big().Add(m.big(), y0)
+	}
+	return z, m
+}
+
+// GCD sets z to the greatest common divisor of a and b, which both must
+// be > 0, and returns z.
+// If x and y are not nil, GCD sets x and y such that z = a*x + b*y.
+// If either a or b is <= 0, GCD sets z = x = y = 0.
+func (z *Int) GCD(x, y, a, b *Int) *Int {
+	if a.Sign() <= 0 || b.Sign() <= 0 {
+		z.SetInt64(0)
+		if x != nil {
+			x.SetInt64(0)
+		}
+		if y != nil {
+			y.SetInt64(0)
+		}
+		return z
+	}
+	var bx, by *big.Int
+	if x != nil {
+		bx = x.big()
+	}
+	if y != nil {
+		by = y.big()
+	}
+	z.big().GCD(bx, by, a.big(), b.big())
+	return z
+}
+
+/*
+ * functions without a clear receiver
+ */
+
+// CmpInt compares x and y. The result is
+//
+//	-1 if x <  y
+//	 0 if x == y
+//	+1 if x >  y
+func CmpInt(x, y *Int) int {
+	return x.big().Cmp(y.big())
+}
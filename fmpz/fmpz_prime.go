@@ -0,0 +1,27 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpz
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+import "C"
+
+// IsProbablePrime reports whether z is probably prime, using the
+// Baillie-PSW test.  No composites are known to pass it and it is
+// proven correct for all z < 2^64.
+func (z *Int) IsProbablePrime() bool {
+	return C.fmpz_is_probabprime((*C.fmpz)(z)) == 1
+}
+
+// IsPrime reports whether z is prime.  Unlike IsProbablePrime the
+// answer is proven: FLINT combines Pocklington, Morrison-Brillhart
+// and Selfridge tests and falls back to APR-CL where those fail.
+// IsPrime returns true only if z has been proven prime.
+func (z *Int) IsPrime() bool {
+	return C.fmpz_is_prime((*C.fmpz)(z)) == 1
+}
@@ -0,0 +1,158 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpz
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// A PrimeCertificate is a Pocklington-Lehmer proof that N is prime.
+//
+// Each entry of Factors names a prime q dividing N-1, together with
+// its own certificate, and a witness a with a^(N-1) = 1 (mod N) and
+// gcd(a^((N-1)/q) - 1, N) = 1.  If the product F of the largest
+// powers of these primes dividing N-1 satisfies F^2 > N, then N is
+// prime.  Primes below 2^64 carry no factors; they are checked
+// directly.
+//
+// Certificates use math/big only, so they can be archived (for
+// example with encoding/json) and checked with Verify on machines
+// without FLINT.
+type PrimeCertificate struct {
+	N       *big.Int
+	Factors []PocklingtonFactor `json:",omitempty"`
+}
+
+// A PocklingtonFactor is one prime factor of N-1 in a PrimeCertificate.
+type PocklingtonFactor struct {
+	Q *PrimeCertificate
+	A *big.Int
+}
+
+// smallPrimeBits is the size below which a certificate is checked
+// directly instead of recursively.
+const smallPrimeBits = 64
+
+// PrimeCertificate returns a Pocklington-Lehmer certificate for z.
+// It returns an error if z is not prime.
+//
+// NB Building the certificate requires the complete factorisation
+// of z-1 (and recursively of q-1 for its large prime factors q), so
+// it is only practical when those numbers factor easily, as is the
+// case for primes generated with a known factorisation of p-1.
+func (z *Int) PrimeCertificate() (*PrimeCertificate, error) {
+	if !z.IsPrime() {
+		return nil, fmt.Errorf("fmpz: %s is not prime", z)
+	}
+	return z.primeCertificate()
+}
+
+func (z *Int) primeCertificate() (*PrimeCertificate, error) {
+	c := &PrimeCertificate{N: bigInt(z)}
+	if z.BitLen() <= smallPrimeBits {
+		return c, nil
+	}
+
+	one := NewInt(1)
+	nm1 := new(Int).Sub(z, one)
+	qs, _ := nm1.Factor()
+
+	a, t, e, g := NewInt(0), NewInt(0), NewInt(0), NewInt(0)
+	for _, q := range qs {
+		e.Div(nm1, q)
+		found := false
+		for w := int64(2); w < 1000; w++ {
+			a.SetInt64(w)
+			t.Exp(a, nm1, z)
+			if t.Cmp(one) != 0 {
+				// Cannot happen for prime z; refuse to certify.
+				return nil, fmt.Errorf("fmpz: %s fails the Fermat test to base %d", z, w)
+			}
+			t.Exp(a, e, z)
+			t.Sub(t, one)
+			g.GCD(nil, nil, t, z)
+			if g.Cmp(one) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("fmpz: no Pocklington witness for factor %s of %s-1", q, z)
+		}
+		qc, err := q.primeCertificate()
+		if err != nil {
+			return nil, err
+		}
+		c.Factors = append(c.Factors, PocklingtonFactor{Q: qc, A: bigInt(a)})
+	}
+	return c, nil
+}
+
+// Verify checks the certificate c and returns nil if it proves that
+// c.N is prime.  Verify uses math/big only and does not call FLINT.
+func Verify(c *PrimeCertificate) error {
+	if c == nil || c.N == nil {
+		return errors.New("fmpz: empty prime certificate")
+	}
+	n := c.N
+	if n.Cmp(big.NewInt(2)) < 0 {
+		return fmt.Errorf("fmpz: certificate for %s: not prime", n)
+	}
+	if n.BitLen() <= smallPrimeBits {
+		// ProbablyPrime is exact below 2^64.
+		if !n.ProbablyPrime(0) {
+			return fmt.Errorf("fmpz: certificate for %s: not prime", n)
+		}
+		return nil
+	}
+
+	one := big.NewInt(1)
+	nm1 := new(big.Int).Sub(n, one)
+	f := big.NewInt(1)
+	r, t, e, g := new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	seen := make(map[string]bool)
+	for _, pf := range c.Factors {
+		if pf.Q == nil || pf.Q.N == nil || pf.A == nil {
+			return fmt.Errorf("fmpz: certificate for %s: incomplete factor", n)
+		}
+		q := pf.Q.N
+		if seen[q.String()] {
+			return fmt.Errorf("fmpz: certificate for %s: factor %s listed twice", n, q)
+		}
+		seen[q.String()] = true
+		if q.Cmp(one) <= 0 || r.Mod(nm1, q).Sign() != 0 {
+			return fmt.Errorf("fmpz: certificate for %s: %s does not divide N-1", n, q)
+		}
+		if err := Verify(pf.Q); err != nil {
+			return err
+		}
+		if t.Exp(pf.A, nm1, n).Cmp(one) != 0 {
+			return fmt.Errorf("fmpz: certificate for %s: witness %s fails the Fermat test", n, pf.A)
+		}
+		e.Quo(nm1, q)
+		t.Exp(pf.A, e, n)
+		t.Sub(t, one)
+		t.Mod(t, n)
+		if t.Sign() == 0 || g.GCD(nil, nil, t, n).Cmp(one) != 0 {
+			return fmt.Errorf("fmpz: certificate for %s: witness %s fails for factor %s", n, pf.A, q)
+		}
+		// Multiply in the full power of q dividing N-1.
+		for e.Set(nm1); r.Mod(e, q).Sign() == 0; e.Quo(e, q) {
+			f.Mul(f, q)
+		}
+	}
+	if t.Mul(f, f).Cmp(n) <= 0 {
+		return fmt.Errorf("fmpz: certificate for %s: factored part of N-1 is too small", n)
+	}
+	return nil
+}
+
+// bigInt returns the value of z as a new big.Int.
+func bigInt(z *Int) *big.Int {
+	b, _ := new(big.Int).SetString(z.String(), 10)
+	return b
+}
@@ -0,0 +1,32 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpz
+
+// IsProbablePrime reports whether z is probably prime, using the
+// Baillie-PSW test.  No composites are known to pass it and it is
+// proven correct for all z < 2^64.
+func (z *Int) IsProbablePrime() bool {
+	return z.big().ProbablyPrime(0)
+}
+
+// IsPrime reports whether z is prime.  Unlike IsProbablePrime the
+// answer is proven: below 2^64 the Baillie-PSW test is exact, and
+// larger z are proven prime by building a Pocklington certificate.
+// IsPrime returns true only if z has been proven prime.
+//
+// NB Without FLINT the proof needs the factorisation of z-1, so
+// IsPrime may be very slow for large z; see PrimeCertificate.
+func (z *Int) IsPrime() bool {
+	if !z.IsProbablePrime() {
+		return false
+	}
+	if z.BitLen() <= smallPrimeBits {
+		return true
+	}
+	_, err := z.primeCertificate()
+	return err == nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpz
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+// m127 is the Mersenne prime 2^127 - 1; 2^127 - 2 factors into
+// primes below 2^37.
+const m127 = "170141183460469231731687303715884105727"
+
+func TestPrimeCertificate(t *testing.T) {
+	c, err := mustInt(t, m127).PrimeCertificate()
+	if err != nil {
+		t.Fatalf("PrimeCertificate(2^127-1): %v", err)
+	}
+	if err := Verify(c); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if c.N.String() != m127 || len(c.Factors) != 12 {
+		t.Errorf("certificate has N = %v and %d factors, want %s and 12", c.N, len(c.Factors), m127)
+	}
+
+	// Certificates survive a round trip through JSON.
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var d PrimeCertificate
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if err := Verify(&d); err != nil {
+		t.Errorf("Verify after JSON round trip: %v", err)
+	}
+
+	small, err := NewInt(1000003).PrimeCertificate()
+	if err != nil || len(small.Factors) != 0 || Verify(small) != nil {
+		t.Errorf("PrimeCertificate(1000003) = %v, %v", small, err)
+	}
+	if _, err := mustInt(t, "170141183460469231731687303715884105729").PrimeCertificate(); err == nil {
+		t.Errorf("PrimeCertificate of a composite succeeded")
+	}
+}
+
+// tamper returns a deep copy of c.
+func tamper(t *testing.T, c *PrimeCertificate) *PrimeCertificate {
+	t.Helper()
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := new(PrimeCertificate)
+	if err := json.Unmarshal(b, d); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestVerifyRejects(t *testing.T) {
+	c, err := mustInt(t, m127).PrimeCertificate()
+	if err != nil {
+		t.Fatalf("PrimeCertificate(2^127-1): %v", err)
+	}
+	composite, _ := new(big.Int).SetString("170141183460469231731687303715884105729", 10)
+	for _, tc := range []struct {
+		name   string
+		modify func(c *PrimeCertificate) *PrimeCertificate
+	}{
+		{"nil", func(*PrimeCertificate) *PrimeCertificate { return nil }},
+		{"empty", func(*PrimeCertificate) *PrimeCertificate { return &PrimeCertificate{} }},
+		{"small composite", func(*PrimeCertificate) *PrimeCertificate {
+			return &PrimeCertificate{N: big.NewInt(561)}
+		}},
+		{"large composite without factors", func(*PrimeCertificate) *PrimeCertificate {
+			return &PrimeCertificate{N: composite}
+		}},
+		{"composite with the factors of a prime", func(c *PrimeCertificate) *PrimeCertificate {
+			c.N = composite
+			return c
+		}},
+		{"trivial witness", func(c *PrimeCertificate) *PrimeCertificate {
+			c.Factors[0].A = big.NewInt(1)
+			return c
+		}},
+		{"bad witness", func(c *PrimeCertificate) *PrimeCertificate {
+			c.Factors[3].A = new(big.Int).Sub(c.N, big.NewInt(1))
+			c.Factors[3].A.Rsh(c.Factors[3].A, 1)
+			return c
+		}},
+		{"missing factors", func(c *PrimeCertificate) *PrimeCertificate {
+			c.Factors = c.Factors[:2]
+			return c
+		}},
+		{"duplicate factor", func(c *PrimeCertificate) *PrimeCertificate {
+			c.Factors = append(c.Factors, c.Factors[0])
+			return c
+		}},
+		{"factor not dividing N-1", func(c *PrimeCertificate) *PrimeCertificate {
+			c.Factors[1].Q = &PrimeCertificate{N: big.NewInt(11)}
+			return c
+		}},
+		{"composite factor", func(c *PrimeCertificate) *PrimeCertificate {
+			// 9 divides N-1 but is not prime.
+			c.Factors[1].Q = &PrimeCertificate{N: big.NewInt(9)}
+			return c
+		}},
+		{"incomplete factor", func(c *PrimeCertificate) *PrimeCertificate {
+			c.Factors[2].Q = nil
+			return c
+		}},
+		{"missing witness", func(c *PrimeCertificate) *PrimeCertificate {
+			c.Factors[2].A = nil
+			return c
+		}},
+	} {
+		if err := Verify(tc.modify(tamper(t, c))); err == nil {
+			t.Errorf("%s: Verify accepted the certificate", tc.name)
+		}
+	}
+}
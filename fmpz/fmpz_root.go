@@ -0,0 +1,163 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpz
+
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+//
+// // goflint_fmpz_root sets r to the n-th root of x rounded toward
+// // zero and returns whether it is exact.
+// static int goflint_fmpz_root(fmpz_t r, const fmpz_t x, slong n)
+// {
+// 	fmpz_t t, u;
+// 	int exact;
+// 	fmpz_init(t);
+// 	fmpz_init(u);
+// 	fmpz_root(t, x, n);
+// 	if (fmpz_cmpabs_ui(t, 1) <= 0) {
+// 		// t^n = t for odd n, and t >= 0 for even n; skip the
+// 		// power, which may be huge for large n.
+// 		exact = fmpz_equal(t, x);
+// 	} else {
+// 		fmpz_pow_ui(u, t, n);
+// 		exact = fmpz_equal(u, x);
+// 	}
+// 	fmpz_swap(r, t);
+// 	fmpz_clear(t);
+// 	fmpz_clear(u);
+// 	return exact;
+// }
+//
+// // goflint_fmpz_perfect_power sets r and returns k > 1 so that
+// // x = r^k with k as large as possible, or sets r = x and returns 1
+// // if there is no such k.  |x| must be greater than 1.
+// // fmpz_is_perfect_power does not promise the largest exponent, so
+// // the root is decomposed until it is no longer a power.
+// static ulong goflint_fmpz_perfect_power(fmpz_t r, const fmpz_t x)
+// {
+// 	fmpz_t s, t;
+// 	ulong j, k;
+// 	fmpz_init(s);
+// 	fmpz_init(t);
+// 	k = fmpz_is_perfect_power(t, x);
+// 	if (k == 0) {
+// 		fmpz_set(t, x);
+// 		k = 1;
+// 	}
+// 	while (k > 1 && fmpz_cmpabs_ui(t, 1) > 0 && (j = fmpz_is_perfect_power(s, t)) > 1) {
+// 		k *= j;
+// 		fmpz_swap(t, s);
+// 	}
+// 	if (k % 2 == 0)
+// 		fmpz_abs(t, t);
+// 	fmpz_swap(r, t);
+// 	fmpz_clear(s);
+// 	fmpz_clear(t);
+// 	return k;
+// }
+import "C"
+
+import "github.com/frithjof-schulze/go.flint/flint"
+
+// Sqrt sets z to the square root of x rounded down and returns z.
+// If x is negative, Sqrt panics with flint.ErrDomain.
+func (z *Int) Sqrt(x *Int) *Int {
+	if x.Sign() < 0 {
+		flint.Panic("fmpz.Int.Sqrt", flint.ErrDomain)
+	}
+	C.fmpz_sqrt((*C.fmpz)(z), (*C.fmpz)(x))
+	return z
+}
+
+// SqrtRem sets z to Sqrt(x) and r to x - z*z and returns the pair
+// (z, r).  z and r must be distinct.  If x is negative, SqrtRem
+// panics with flint.ErrDomain.
+func (z *Int) SqrtRem(x, r *Int) (*Int, *Int) {
+	if x.Sign() < 0 {
+		flint.Panic("fmpz.Int.SqrtRem", flint.ErrDomain)
+	}
+	C.fmpz_sqrtrem((*C.fmpz)(z), (*C.fmpz)(r), (*C.fmpz)(x))
+	return z, r
+}
+
+// Root sets z to the n-th root of x rounded toward zero and returns
+// z and whether the root is exact, that is, whether z^n = x.  If n is
+// less than 1, or n is even and x is negative, Root panics with
+// flint.ErrDomain.
+func (z *Int) Root(x *Int, n int) (*Int, bool) {
+	if n < 1 || n%2 == 0 && x.Sign() < 0 {
+		flint.Panic("fmpz.Int.Root", flint.ErrDomain)
+	}
+	exact := C.goflint_fmpz_root((*C.fmpz)(z), (*C.fmpz)(x), C.slong(n)) != 0
+	return z, exact
+}
+
+// IsSquare reports whether z is the square of an integer.
+func (z *Int) IsSquare() bool {
+	return C.fmpz_is_square((*C.fmpz)(z)) != 0
+}
+
+// IsPerfectPower returns a base r and the largest k such that
+// z = r^k; z is a perfect power if and only if k > 1.  For even k,
+// r is positive.  0 and 1 are reported as 0^2 and 1^2, -1 as
+// (-1)^3.
+func (z *Int) IsPerfectPower() (*Int, int) {
+	r := new(Int)
+	switch z.Sign() {
+	case 0:
+		return r.SetInt64(0), 2
+	case 1:
+		if z.Cmp(NewInt(1)) == 0 {
+			return r.SetInt64(1), 2
+		}
+	case -1:
+		if z.Cmp(NewInt(-1)) == 0 {
+			return r.SetInt64(-1), 3
+		}
+	}
+	k := C.goflint_fmpz_perfect_power((*C.fmpz)(r), (*C.fmpz)(z))
+	return r, int(k)
+}
+
+// FLog returns the largest k with b^k <= z.  z must be positive and
+// b at least 2; otherwise FLog panics with flint.ErrDomain.
+func (z *Int) FLog(b *Int) int {
+	checkLog("FLog", z.Sign() > 0 && b.Cmp(NewInt(2)) >= 0)
+	return int(C.fmpz_flog((*C.fmpz)(z), (*C.fmpz)(b)))
+}
+
+// CLog returns the smallest k with b^k >= z.  z must be positive and
+// b at least 2; otherwise CLog panics with flint.ErrDomain.
+func (z *Int) CLog(b *Int) int {
+	checkLog("CLog", z.Sign() > 0 && b.Cmp(NewInt(2)) >= 0)
+	return int(C.fmpz_clog((*C.fmpz)(z), (*C.fmpz)(b)))
+}
+
+// FLogUint64 is like FLog for a word-sized base.
+func (z *Int) FLogUint64(b uint64) int {
+	checkLog("FLogUint64", z.Sign() > 0 && b >= 2)
+	return int(C.fmpz_flog_ui((*C.fmpz)(z), C.ulong(b)))
+}
+
+// CLogUint64 is like CLog for a word-sized base.
+func (z *Int) CLogUint64(b uint64) int {
+	checkLog("CLogUint64", z.Sign() > 0 && b >= 2)
+	return int(C.fmpz_clog_ui((*C.fmpz)(z), C.ulong(b)))
+}
+
+func checkLog(op string, ok bool) {
+	if !ok {
+		flint.Panic("fmpz.Int."+op, flint.ErrDomain)
+	}
+}
+
+// TrailingZeroBits returns the number of consecutive least
+// significant zero bits of |z|, its binary valuation.  It returns 0
+// for z = 0.
+func (z *Int) TrailingZeroBits() uint {
+	return uint(C.fmpz_val2((*C.fmpz)(z)))
+}
@@ -0,0 +1,183 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build noflint || !cgo
+
+package fmpz
+
+import (
+	"math/big"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+// Sqrt sets z to the square root of x rounded down and returns z.
+// If x is negative, Sqrt panics with flint.ErrDomain.
+func (z *Int) Sqrt(x *Int) *Int {
+	if x.Sign() < 0 {
+		flint.Panic("fmpz.Int.Sqrt", flint.ErrDomain)
+	}
+	z.big().Sqrt(x.big())
+	return z
+}
+
+// SqrtRem sets z to Sqrt(x) and r to x - z*z and returns the pair
+// (z, r).  z and r must be distinct.  If x is negative, SqrtRem
+// panics with flint.ErrDomain.
+func (z *Int) SqrtRem(x, r *Int) (*Int, *Int) {
+	if x.Sign() < 0 {
+		flint.Panic("fmpz.Int.SqrtRem", flint.ErrDomain)
+	}
+	s := new(big.Int).Sqrt(x.big())
+	t := new(big.Int).Mul(s, s)
+	r.big().Sub(x.big(), t)
+	z.big().Set(s)
+	return z, r
+}
+
+// Root sets z to the n-th root of x rounded toward zero and returns
+// z and whether the root is exact, that is, whether z^n = x.  If n is
+// less than 1, or n is even and x is negative, Root panics with
+// flint.ErrDomain.
+func (z *Int) Root(x *Int, n int) (*Int, bool) {
+	if n < 1 || n%2 == 0 && x.Sign() < 0 {
+		flint.Panic("fmpz.Int.Root", flint.ErrDomain)
+	}
+	a := new(big.Int).Abs(x.big())
+	r := root(a, n)
+	exact := new(big.Int).Exp(r, big.NewInt(int64(n)), nil).Cmp(a) == 0
+	if x.Sign() < 0 {
+		r.Neg(r)
+	}
+	z.big().Set(r)
+	return z, exact
+}
+
+// root returns the n-th root of a >= 0 rounded down, by Newton's
+// iteration from a power of two above the root.
+func root(a *big.Int, n int) *big.Int {
+	if n == 1 || a.Sign() == 0 {
+		return new(big.Int).Set(a)
+	}
+	if n >= a.BitLen() {
+		// 1 <= a < 2^n, so the root is 1.
+		return big.NewInt(1)
+	}
+	nn := big.NewInt(int64(n))
+	n1 := big.NewInt(int64(n - 1))
+	y := new(big.Int).Lsh(big.NewInt(1), uint((a.BitLen()+n-1)/n))
+	t, u := new(big.Int), new(big.Int)
+	for {
+		// y' = ((n-1)*y + a/y^(n-1)) / n
+		t.Exp(y, n1, nil)
+		t.Quo(a, t)
+		u.Mul(y, n1)
+		t.Add(t, u)
+		t.Quo(t, nn)
+		if t.Cmp(y) >= 0 {
+			return y
+		}
+		y.Set(t)
+	}
+}
+
+// IsSquare reports whether z is the square of an integer.
+func (z *Int) IsSquare() bool {
+	if z.Sign() < 0 {
+		return false
+	}
+	s := new(big.Int).Sqrt(z.big())
+	return s.Mul(s, s).Cmp(z.big()) == 0
+}
+
+// IsPerfectPower returns a base r and the largest k such that
+// z = r^k; z is a perfect power if and only if k > 1.  For even k,
+// r is positive.  0 and 1 are reported as 0^2 and 1^2, -1 as
+// (-1)^3.
+func (z *Int) IsPerfectPower() (*Int, int) {
+	a := new(big.Int).Abs(z.big())
+	if a.BitLen() <= 1 {
+		if z.Sign() < 0 {
+			return new(Int).Set(z), 3
+		}
+		return new(Int).Set(z), 2
+	}
+	// An exponent k > 1 satisfies 2^k <= |z|; the first exact root
+	// found counting down has the largest exponent.
+	for k := a.BitLen() - 1; k > 1; k-- {
+		if z.Sign() < 0 && k%2 == 0 {
+			continue
+		}
+		r := root(a, k)
+		if new(big.Int).Exp(r, big.NewInt(int64(k)), nil).Cmp(a) == 0 {
+			if z.Sign() < 0 {
+				r.Neg(r)
+			}
+			return (*Int)(r), k
+		}
+	}
+	return new(Int).Set(z), 1
+}
+
+// FLog returns the largest k with b^k <= z.  z must be positive and
+// b at least 2; otherwise FLog panics with flint.ErrDomain.
+func (z *Int) FLog(b *Int) int {
+	checkLog("FLog", z.Sign() > 0 && b.Cmp(NewInt(2)) >= 0)
+	k, _ := flog(z.big(), b.big())
+	return k
+}
+
+// CLog returns the smallest k with b^k >= z.  z must be positive and
+// b at least 2; otherwise CLog panics with flint.ErrDomain.
+func (z *Int) CLog(b *Int) int {
+	checkLog("CLog", z.Sign() > 0 && b.Cmp(NewInt(2)) >= 0)
+	return clog(z.big(), b.big())
+}
+
+// FLogUint64 is like FLog for a word-sized base.
+func (z *Int) FLogUint64(b uint64) int {
+	checkLog("FLogUint64", z.Sign() > 0 && b >= 2)
+	k, _ := flog(z.big(), new(big.Int).SetUint64(b))
+	return k
+}
+
+// CLogUint64 is like CLog for a word-sized base.
+func (z *Int) CLogUint64(b uint64) int {
+	checkLog("CLogUint64", z.Sign() > 0 && b >= 2)
+	return clog(z.big(), new(big.Int).SetUint64(b))
+}
+
+func checkLog(op string, ok bool) {
+	if !ok {
+		flint.Panic("fmpz.Int."+op, flint.ErrDomain)
+	}
+}
+
+// flog returns the largest k with b^k <= x and whether b^k = x.
+func flog(x, b *big.Int) (int, bool) {
+	// b < 2^len(b), so b^k < 2^(k*len(b)) <= x for k below.
+	k := (x.BitLen() - 1) / b.BitLen()
+	p := new(big.Int).Exp(b, big.NewInt(int64(k)), nil)
+	t := new(big.Int)
+	for t.Mul(p, b).Cmp(x) <= 0 {
+		p.Set(t)
+		k++
+	}
+	return k, p.Cmp(x) == 0
+}
+
+func clog(x, b *big.Int) int {
+	k, exact := flog(x, b)
+	if !exact {
+		k++
+	}
+	return k
+}
+
+// TrailingZeroBits returns the number of consecutive least
+// significant zero bits of |z|, its binary valuation.  It returns 0
+// for z = 0.
+func (z *Int) TrailingZeroBits() uint {
+	return z.big().TrailingZeroBits()
+}
@@ -0,0 +1,167 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package fmpz
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+func TestSqrt(t *testing.T) {
+	for _, s := range []string{"0", "1", "2", "15", "16", "17", "4611686018427387903", "123456789012345678901234567890"} {
+		x := mustInt(t, s)
+		z, r := NewInt(0).SqrtRem(x, NewInt(0))
+		// z^2 <= x < (z+1)^2
+		lo := NewInt(0).Mul(z, z)
+		hi := NewInt(0).AddInt64(z, 1)
+		hi.Mul(hi, hi)
+		if lo.Cmp(x) > 0 || hi.Cmp(x) <= 0 {
+			t.Errorf("SqrtRem(%s) = %v", s, z)
+		}
+		if lo.Add(lo, r).Cmp(x) != 0 {
+			t.Errorf("SqrtRem(%s) remainder = %v", s, r)
+		}
+		if y := NewInt(0).Sqrt(x); y.Cmp(z) != 0 {
+			t.Errorf("Sqrt(%s) = %v, want %v", s, y, z)
+		}
+		if x.IsSquare() != (r.Sign() == 0) {
+			t.Errorf("IsSquare(%s) = %v", s, x.IsSquare())
+		}
+	}
+	if NewInt(-4).IsSquare() {
+		t.Errorf("IsSquare(-4) = true")
+	}
+	for name, f := range map[string]func(){
+		"Sqrt":    func() { NewInt(0).Sqrt(NewInt(-1)) },
+		"SqrtRem": func() { NewInt(0).SqrtRem(NewInt(-1), NewInt(0)) },
+		"Root":    func() { NewInt(0).Root(NewInt(-8), 2) },
+		"Root0":   func() { NewInt(0).Root(NewInt(8), 0) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDomain) {
+			t.Errorf("%s: err = %v, want ErrDomain", name, err)
+		}
+	}
+}
+
+func TestRoot(t *testing.T) {
+	for _, tc := range []struct {
+		x     string
+		n     int
+		want  string
+		exact bool
+	}{
+		{"0", 3, "0", true},
+		{"27", 3, "3", true},
+		{"28", 3, "3", false},
+		{"26", 3, "2", false},
+		{"-27", 3, "-3", true},
+		{"-28", 3, "-3", false},
+		{"-7", 1, "-7", true},
+		{"1267650600228229401496703205376", 100, "2", true},
+		{"1267650600228229401496703205375", 100, "1", false},
+		{"123456789012345678901234567890", 5, "658116", false},
+		{"123456789012345678901234567890", math.MaxInt, "1", false},
+		{"1", math.MaxInt, "1", true},
+		{"-1", math.MaxInt, "-1", true},
+		{"-2", math.MaxInt, "-1", false},
+		{"0", math.MaxInt, "0", true},
+	} {
+		z, exact := NewInt(0).Root(mustInt(t, tc.x), tc.n)
+		if z.String() != tc.want || exact != tc.exact {
+			t.Errorf("Root(%s, %d) = %v, %v, want %s, %v", tc.x, tc.n, z, exact, tc.want, tc.exact)
+		}
+	}
+}
+
+func TestIsPerfectPower(t *testing.T) {
+	for _, tc := range []struct {
+		x, r string
+		k    int
+	}{
+		{"0", "0", 2},
+		{"1", "1", 2},
+		{"-1", "-1", 3},
+		{"2", "2", 1},
+		{"12", "12", 1},
+		{"64", "2", 6},
+		{"-64", "-4", 3},
+		{"-32", "-2", 5},
+		{"4096", "2", 12},
+		{"1000000", "10", 6},
+		{"36", "6", 2},
+		{"1267650600228229401496703205376", "2", 100},
+		{"515377520732011331036461129765621272702107522001", "3", 100},
+		{"515377520732011331036461129765621272702107522002", "515377520732011331036461129765621272702107522002", 1},
+	} {
+		r, k := mustInt(t, tc.x).IsPerfectPower()
+		if r.String() != tc.r || k != tc.k {
+			t.Errorf("IsPerfectPower(%s) = %v, %d, want %s, %d", tc.x, r, k, tc.r, tc.k)
+		}
+	}
+}
+
+func TestLog(t *testing.T) {
+	for _, tc := range []struct {
+		x           string
+		b           uint64
+		floor, ceil int
+	}{
+		{"1", 2, 0, 0},
+		{"2", 2, 1, 1},
+		{"3", 2, 1, 2},
+		{"1000", 10, 3, 3},
+		{"999", 10, 2, 3},
+		{"1001", 10, 3, 4},
+		{"5", 7, 0, 1},
+		{"1267650600228229401496703205376", 2, 100, 100},
+		{"1267650600228229401496703205377", 2, 100, 101},
+		{"123456789012345678901234567890", 1000000007, 3, 4},
+	} {
+		x := mustInt(t, tc.x)
+		b := NewInt(0).SetUint64(tc.b)
+		if k := x.FLog(b); k != tc.floor {
+			t.Errorf("FLog(%s, %d) = %d, want %d", tc.x, tc.b, k, tc.floor)
+		}
+		if k := x.CLog(b); k != tc.ceil {
+			t.Errorf("CLog(%s, %d) = %d, want %d", tc.x, tc.b, k, tc.ceil)
+		}
+		if k := x.FLogUint64(tc.b); k != tc.floor {
+			t.Errorf("FLogUint64(%s, %d) = %d, want %d", tc.x, tc.b, k, tc.floor)
+		}
+		if k := x.CLogUint64(tc.b); k != tc.ceil {
+			t.Errorf("CLogUint64(%s, %d) = %d, want %d", tc.x, tc.b, k, tc.ceil)
+		}
+	}
+	for name, f := range map[string]func(){
+		"FLog(0)":       func() { NewInt(0).FLog(NewInt(2)) },
+		"CLog(-5)":      func() { NewInt(-5).CLog(NewInt(2)) },
+		"FLog base 1":   func() { NewInt(5).FLog(NewInt(1)) },
+		"CLogUint64(0)": func() { NewInt(5).CLogUint64(0) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDomain) {
+			t.Errorf("%s: err = %v, want ErrDomain", name, err)
+		}
+	}
+}
+
+func TestTrailingZeroBits(t *testing.T) {
+	for _, tc := range []struct {
+		x string
+		n uint
+	}{
+		{"0", 0},
+		{"1", 0},
+		{"-12", 2},
+		{"4611686018427387904", 62},
+		{"-1267650600228229401496703205376", 100},
+	} {
+		if n := mustInt(t, tc.x).TrailingZeroBits(); n != tc.n {
+			t.Errorf("TrailingZeroBits(%s) = %d, want %d", tc.x, n, tc.n)
+		}
+	}
+}
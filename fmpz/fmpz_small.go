@@ -0,0 +1,179 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fmpz
+
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+import "C"
+
+import (
+	"math/bits"
+	"unsafe"
+)
+
+// An fmpz is a single word.  If its value lies in [-coeffMax,
+// coeffMax] (COEFF_MAX in FLINT) it is the integer itself;
+// otherwise it is a tagged pointer to an mpz, and its top two bits
+// are 01, which puts it outside that range.  The hot operations
+// below read the word directly and only call into FLINT when an
+// operand or the result does not fit, saving the cost of a cgo call
+// for the common small-small case.
+const coeffMax = 1<<(8*unsafe.Sizeof(Int(0))-2) - 1
+
+// small returns the value of z and true if z is stored inline.
+func (z *Int) small() (int64, bool) {
+	v := int64(*z)
+	return v, -coeffMax <= v && v <= coeffMax
+}
+
+// setSmall sets z = v and returns z.  v must fit in a C.slong.
+// If z holds an mpz or v does not fit inline, FLINT does the
+// work so that the mpz is freed or allocated as needed.
+func (z *Int) setSmall(v int64) *Int {
+	if _, ok := z.small(); ok && -coeffMax <= v && v <= coeffMax {
+		*z = Int(v)
+		return z
+	}
+	C.fmpz_set_si((*C.fmpz)(z), C.slong(v))
+	return z
+}
+
+// addSmall sets z = x + y if x and y are stored inline.  The sum
+// of two inline values always fits in a C.slong.
+func (z *Int) addSmall(x, y *Int) bool {
+	a, ok := x.small()
+	if !ok {
+		return false
+	}
+	b, ok := y.small()
+	if !ok {
+		return false
+	}
+	z.setSmall(a + b)
+	return true
+}
+
+// subSmall sets z = x - y if x and y are stored inline.
+func (z *Int) subSmall(x, y *Int) bool {
+	a, ok := x.small()
+	if !ok {
+		return false
+	}
+	b, ok := y.small()
+	if !ok {
+		return false
+	}
+	z.setSmall(a - b)
+	return true
+}
+
+// mulSmall sets z = x * y if x and y and the product are stored
+// inline.
+func (z *Int) mulSmall(x, y *Int) bool {
+	a, ok := x.small()
+	if !ok {
+		return false
+	}
+	b, ok := y.small()
+	if !ok {
+		return false
+	}
+	r, ok := mulInline(a, b)
+	if !ok {
+		return false
+	}
+	z.setSmall(r)
+	return true
+}
+
+// mulInline returns a * b and true if the product fits inline.
+func mulInline(a, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(abs64(a), abs64(b))
+	if hi != 0 || lo > coeffMax {
+		return 0, false
+	}
+	r := int64(lo)
+	if (a < 0) != (b < 0) {
+		r = -r
+	}
+	return r, true
+}
+
+// divInline returns the quotient and remainder of a divided by b,
+// with the quotient rounded down (mode 'f'), up ('c') or toward
+// zero ('t').  a must be an inline value and b must not be 0.
+func divInline(a, b int64, mode byte) (q, r int64) {
+	q, r = a/b, a%b
+	if r != 0 {
+		switch {
+		case mode == 'f' && (r < 0) != (b < 0):
+			q, r = q-1, r+b
+		case mode == 'c' && (r < 0) == (b < 0):
+			q, r = q+1, r-b
+		}
+	}
+	return q, r
+}
+
+// cmpSmall compares x and y if both are stored inline.
+func cmpSmall(x, y *Int) (int, bool) {
+	a, ok := x.small()
+	if !ok {
+		return 0, false
+	}
+	b, ok := y.small()
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case a < b:
+		return -1, true
+	case a > b:
+		return 1, true
+	}
+	return 0, true
+}
+
+// The methods below are the slow paths of Add, Sub, Mul, Sign and
+// Cmp.  They always call FLINT, so the benchmarks also use them as
+// the baseline for the inline fast paths.
+
+func (z *Int) addFLINT(x, y *Int) *Int {
+	C.fmpz_add((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+func (z *Int) subFLINT(x, y *Int) *Int {
+	C.fmpz_sub((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+func (z *Int) mulFLINT(x, y *Int) *Int {
+	C.fmpz_mul((*C.fmpz)(z), (*C.fmpz)(x), (*C.fmpz)(y))
+	return z
+}
+
+func (z *Int) signFLINT() int {
+	return int(C.fmpz_sgn((*C.fmpz)(z)))
+}
+
+func (z *Int) cmpFLINT(y *Int) int {
+	r := int(C.fmpz_cmp((*C.fmpz)(z), (*C.fmpz)(y)))
+	if r < 0 {
+		return -1
+	} else if r > 0 {
+		return 1
+	}
+	return 0
+}
+
+func abs64(x int64) uint64 {
+	if x < 0 {
+		return uint64(-x)
+	}
+	return uint64(x)
+}
@@ -0,0 +1,23 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fmpz
+
+import "testing"
+
+// The baselines of the benchmarks in fmpz_bench_test.go: the same
+// workloads through the FLINT slow paths, skipping the inline fast
+// paths.
+
+func BenchmarkAddSmallFLINT(b *testing.B)  { benchmarkBinary(b, 0, (*Int).addFLINT) }
+func BenchmarkSubSmallFLINT(b *testing.B)  { benchmarkBinary(b, 0, (*Int).subFLINT) }
+func BenchmarkMulSmallFLINT(b *testing.B)  { benchmarkBinary(b, 0, (*Int).mulFLINT) }
+func BenchmarkCmpSmallFLINT(b *testing.B)  { benchmarkCmp(b, (*Int).cmpFLINT) }
+func BenchmarkSignSmallFLINT(b *testing.B) { benchmarkSign(b, (*Int).signFLINT) }
+
+func BenchmarkMixedFLINT(b *testing.B) {
+	benchmarkMixed(b, (*Int).addFLINT, (*Int).mulFLINT, (*Int).signFLINT, (*Int).cmpFLINT)
+}
@@ -1 +1,376 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+// These tests do not depend on the backend; they are run against
+// both the FLINT backend and the pure Go one (go test -tags noflint).
+
 package fmpz
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+func mustInt(t *testing.T, s string) *Int {
+	t.Helper()
+	z, ok := NewInt(0).SetString(s, 10)
+	if !ok {
+		t.Fatalf("SetString(%q) failed", s)
+	}
+	return z
+}
+
+func TestSetString(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		base int
+		want string
+		ok   bool
+	}{
+		{"0", 10, "0", true},
+		{"+17", 10, "17", true},
+		{"-123456789012345678901234567890", 10, "-123456789012345678901234567890", true},
+		{"ff", 16, "255", true},
+		{"0x1f", 0, "31", true},
+		{"0b101", 0, "5", true},
+		{"017", 0, "15", true},
+		{"z", 36, "35", true},
+		{"0x", 0, "", false},
+		{"0b", 0, "", false},
+		{"12a", 10, "", false},
+		{"", 10, "", false},
+		{"1_000", 0, "", false},
+		{"10", 1, "", false},
+		{"10", 37, "", false},
+	} {
+		z, ok := NewInt(0).SetString(tc.s, tc.base)
+		if ok != tc.ok {
+			t.Errorf("SetString(%q, %d): ok = %v, want %v", tc.s, tc.base, ok, tc.ok)
+			continue
+		}
+		if ok && z.String() != tc.want {
+			t.Errorf("SetString(%q, %d) = %s, want %s", tc.s, tc.base, z, tc.want)
+		}
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	if _, err := ParseInt("12x", 10); !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("ParseInt(12x): err = %v, want ErrSyntax", err)
+	}
+	var ne *strconv.NumError
+	if _, err := ParseInt("1", 99); !errors.As(err, &ne) || ne.Num != "1" {
+		t.Errorf("ParseInt(1, 99): err = %v, want *strconv.NumError", err)
+	}
+	if z, err := ParseInt("-42", 10); err != nil || z.Int64() != -42 {
+		t.Errorf("ParseInt(-42) = %v, %v", z, err)
+	}
+}
+
+func TestArith(t *testing.T) {
+	x := mustInt(t, "123456789012345678901234567890")
+	y := mustInt(t, "-987654321")
+	for _, tc := range []struct {
+		name string
+		got  *Int
+		want string
+	}{
+		{"Add", NewInt(0).Add(x, y), "123456789012345678900246913569"},
+		{"Sub", NewInt(0).Sub(x, y), "123456789012345678902222222211"},
+		{"Mul", NewInt(0).Mul(x, y), "-121932631124828532112482853211126352690"},
+		{"Div", NewInt(0).Div(x, y), "-124999998873437499901"},
+		{"Mod", NewInt(0).Mod(x, y), "574845669"},
+		{"Neg", NewInt(0).Neg(x), "-123456789012345678901234567890"},
+		{"Abs", NewInt(0).Abs(y), "987654321"},
+		{"Lsh", NewInt(0).Lsh(y, 70), "-1166016415537944393760955695104"},
+		{"Rsh", NewInt(0).Rsh(NewInt(-7), 1), "-4"},
+		{"Exp", NewInt(0).Exp(NewInt(3), NewInt(100), nil), "515377520732011331036461129765621272702107522001"},
+		{"ExpMod", NewInt(0).Exp(NewInt(3), NewInt(100), NewInt(1000007)), "664323"},
+		{"SetUint64", NewInt(0).SetUint64(1 << 63), "9223372036854775808"},
+	} {
+		if s := tc.got.String(); s != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, s, tc.want)
+		}
+	}
+}
+
+func TestDivMod(t *testing.T) {
+	// DivMod rounds the quotient toward minus infinity; Mod is
+	// always non-negative.
+	for _, tc := range []struct{ x, y, q, m, mod int64 }{
+		{7, 2, 3, 1, 1},
+		{-7, 2, -4, 1, 1},
+		{7, -2, -4, -1, 1},
+		{-7, -2, 3, -1, 1},
+		{6, 3, 2, 0, 0},
+	} {
+		q, m := NewInt(0).DivMod(NewInt(tc.x), NewInt(tc.y), NewInt(0))
+		if q.Int64() != tc.q || m.Int64() != tc.m {
+			t.Errorf("DivMod(%d, %d) = %v, %v, want %d, %d", tc.x, tc.y, q, m, tc.q, tc.m)
+		}
+		if r := NewInt(0).Mod(NewInt(tc.x), NewInt(tc.y)); r.Int64() != tc.mod {
+			t.Errorf("Mod(%d, %d) = %v, want %d", tc.x, tc.y, r, tc.mod)
+		}
+		if r := NewInt(0).Div(NewInt(tc.x), NewInt(tc.y)); r.Int64() != tc.x/tc.y {
+			t.Errorf("Div(%d, %d) = %v, want %d", tc.x, tc.y, r, tc.x/tc.y)
+		}
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	for name, f := range map[string]func(){
+		"Div":    func() { NewInt(0).Div(NewInt(1), NewInt(0)) },
+		"Mod":    func() { NewInt(0).Mod(NewInt(1), NewInt(0)) },
+		"DivMod": func() { NewInt(0).DivMod(NewInt(1), NewInt(0), NewInt(0)) },
+		"SymMod": func() { NewInt(0).SymMod(NewInt(1), NewInt(0)) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDivisionByZero) {
+			t.Errorf("%s by zero: err = %v, want ErrDivisionByZero", name, err)
+		}
+	}
+
+	z := NewInt(7)
+	if r, err := z.TryDiv(NewInt(1), NewInt(0)); !errors.Is(err, flint.ErrDivisionByZero) || r != z || z.Int64() != 7 {
+		t.Errorf("TryDiv(1, 0) = %v, %v, want 7, ErrDivisionByZero", r, err)
+	}
+	if r, err := z.TryDiv(NewInt(-7), NewInt(2)); err != nil || r.Int64() != -3 {
+		t.Errorf("TryDiv(-7, 2) = %v, %v, want -3, nil", r, err)
+	}
+}
+
+func TestCmpSign(t *testing.T) {
+	a, b := NewInt(-5), mustInt(t, "100000000000000000000")
+	if a.Cmp(b) != -1 || b.Cmp(a) != 1 || a.Cmp(NewInt(-5)) != 0 || CmpInt(b, a) != 1 {
+		t.Errorf("Cmp gives wrong results")
+	}
+	if a.Sign() != -1 || b.Sign() != 1 || NewInt(0).Sign() != 0 {
+		t.Errorf("Sign gives wrong results")
+	}
+	if n := b.BitLen(); n != 67 {
+		t.Errorf("BitLen = %d, want 67", n)
+	}
+	if NewInt(0).BitLen() != 0 || NewInt(0).Len() != 1 {
+		t.Errorf("BitLen or Len of 0 is wrong")
+	}
+}
+
+func TestGCD(t *testing.T) {
+	a, b := NewInt(240), NewInt(46)
+	x, y := NewInt(0), NewInt(0)
+	g := NewInt(0).GCD(x, y, a, b)
+	if g.Int64() != 2 {
+		t.Fatalf("GCD(240, 46) = %v, want 2", g)
+	}
+	s := NewInt(0).Mul(a, x)
+	s.Add(s, NewInt(0).Mul(b, y))
+	if s.Cmp(g) != 0 {
+		t.Errorf("240*%v + 46*%v = %v, want 2", x, y, s)
+	}
+	if g := NewInt(0).GCD(x, y, NewInt(0), b); g.Sign() != 0 || x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("GCD(0, 46) = %v, %v, %v, want 0, 0, 0", g, x, y)
+	}
+}
+
+func TestJacobi(t *testing.T) {
+	for _, tc := range []struct{ x, y int64 }{{1001, 9907}, {19, 45}, {8, 21}, {5, 21}, {-3, 7}} {
+		want := 1
+		// Euler's criterion does not apply to composite y; compare
+		// with the multiplicative definition instead.
+		for _, p := range []int64{3, 5, 7, 9907} {
+			yy := tc.y
+			for yy%p == 0 {
+				want *= legendre(tc.x, p)
+				yy /= p
+			}
+		}
+		if got := Jacobi(NewInt(tc.x), NewInt(tc.y)); got != want {
+			t.Errorf("Jacobi(%d, %d) = %d, want %d", tc.x, tc.y, got, want)
+		}
+	}
+}
+
+func legendre(a, p int64) int {
+	a = (a%p + p) % p
+	if a == 0 {
+		return 0
+	}
+	r := int64(1)
+	for e, b := (p-1)/2, a; e > 0; e >>= 1 {
+		if e&1 != 0 {
+			r = r * b % p
+		}
+		b = b * b % p
+	}
+	if r == 1 {
+		return 1
+	}
+	return -1
+}
+
+func TestCRT(t *testing.T) {
+	z, ok := NewInt(0).CRT(NewInt(2), NewInt(3), NewInt(3), NewInt(5), false)
+	if !ok || z.Int64() != 8 {
+		t.Errorf("CRT(2 mod 3, 3 mod 5) = %v, %v, want 8", z, ok)
+	}
+	z, ok = NewInt(0).CRT(NewInt(2), NewInt(3), NewInt(3), NewInt(5), true)
+	if !ok || z.Int64() != -7 {
+		t.Errorf("symmetric CRT(2 mod 3, 3 mod 5) = %v, %v, want -7", z, ok)
+	}
+	if _, ok := NewInt(0).CRT(NewInt(1), NewInt(4), NewInt(1), NewInt(6), false); ok {
+		t.Errorf("CRT with non-coprime moduli succeeded")
+	}
+
+	ms := []*Int{NewInt(7), NewInt(11), NewInt(13), NewInt(1000003)}
+	want := mustInt(t, "-12345678")
+	xs := make([]*Int, len(ms))
+	for i, m := range ms {
+		xs[i] = NewInt(0).Mod(want, m)
+	}
+	if z, ok := MultiCRT(ms, xs, true); !ok || z.Cmp(want) != 0 {
+		t.Errorf("MultiCRT = %v, %v, want %v", z, ok, want)
+	}
+	for _, ms := range [][]*Int{{NewInt(6), NewInt(9)}, {NewInt(5), NewInt(-7)}, {NewInt(0)}, nil} {
+		if _, ok := NewCRTPlan(ms); ok {
+			t.Errorf("NewCRTPlan(%v) succeeded", ms)
+		}
+	}
+
+	// A plan is reused for several residue vectors.
+	c, ok := NewCRTPlan([]*Int{NewInt(4), NewInt(1), NewInt(9), NewInt(25)})
+	if !ok || c.Modulus().Int64() != 900 {
+		t.Fatalf("NewCRTPlan = %v, %v, want modulus 900", c, ok)
+	}
+	for _, tc := range []struct {
+		xs        []int64
+		symmetric bool
+		want      int64
+	}{
+		{[]int64{1, 0, 1, 1}, false, 1},
+		{[]int64{3, 5, 8, 24}, false, 899},
+		{[]int64{3, 5, 8, 24}, true, -1},
+		{[]int64{-1, 0, 17, 0}, false, 575},
+		{[]int64{2, 0, 0, 0}, true, 450},
+	} {
+		xs := make([]*Int, len(tc.xs))
+		for i, x := range tc.xs {
+			xs[i] = NewInt(x)
+		}
+		if z := c.Lift(NewInt(0), xs, tc.symmetric); z.Int64() != tc.want {
+			t.Errorf("Lift(%v, %v) = %v, want %d", tc.xs, tc.symmetric, z, tc.want)
+		}
+	}
+
+	// Lifting in place, as done residue by residue in multimodular
+	// algorithms.
+	r, m := NewInt(0), NewInt(1)
+	for _, p := range []int64{101, 103, 107} {
+		q := NewInt(p)
+		if _, ok := r.CRT(r, m, NewInt(0).Mod(NewInt(-1000), q), q, true); !ok {
+			t.Fatalf("CRT modulo %d failed", p)
+		}
+		m.Mul(m, q)
+	}
+	if r.Int64() != -1000 {
+		t.Errorf("CRT in place = %v, want -1000", r)
+	}
+	if _, ok := NewInt(0).CRT(NewInt(1), NewInt(-3), NewInt(1), NewInt(5), false); ok {
+		t.Errorf("CRT with a negative modulus succeeded")
+	}
+	if z := NewInt(0).SymMod(NewInt(8), NewInt(5)); z.Int64() != -2 {
+		t.Errorf("SymMod(8, 5) = %v, want -2", z)
+	}
+}
+
+func TestPrime(t *testing.T) {
+	for _, tc := range []struct {
+		s     string
+		prime bool
+	}{
+		{"2", true},
+		{"1", false},
+		{"561", false},
+		{"18446744073709551557", true},
+		{"170141183460469231731687303715884105727", true},
+		{"170141183460469231731687303715884105729", false},
+	} {
+		z := mustInt(t, tc.s)
+		if z.IsProbablePrime() != tc.prime || z.IsPrime() != tc.prime {
+			t.Errorf("%s: prime = %v, %v, want %v", tc.s, z.IsProbablePrime(), z.IsPrime(), tc.prime)
+		}
+	}
+}
+
+func TestFactor(t *testing.T) {
+	z := mustInt(t, "-1234567890123456789012")
+	ps, es := z.Factor()
+	p := NewInt(1)
+	for i := range ps {
+		if !ps[i].IsPrime() {
+			t.Errorf("factor %v is not prime", ps[i])
+		}
+		if i > 0 && ps[i-1].Cmp(ps[i]) >= 0 {
+			t.Errorf("factors not increasing: %v", ps)
+		}
+		for j := uint64(0); j < es[i]; j++ {
+			p.Mul(p, ps[i])
+		}
+	}
+	if p.Cmp(NewInt(0).Abs(z)) != 0 {
+		t.Errorf("product of factors = %v, want %v", p, NewInt(0).Abs(z))
+	}
+	if ps, _ := NewInt(1).Factor(); len(ps) != 0 {
+		t.Errorf("Factor(1) = %v, want empty", ps)
+	}
+}
+
+func TestWordBoundary(t *testing.T) {
+	// Values around 2^62 switch between the inline and the mpz
+	// representation of the FLINT backend.
+	vals := []string{
+		"0", "1", "-1", "3037000499", "-3037000500", "2147483648",
+		"4611686018427387903", "-4611686018427387903",
+		"4611686018427387904", "-4611686018427387904",
+		"9223372036854775807", "-9223372036854775808",
+		"18446744073709551616",
+	}
+	for _, xs := range vals {
+		for _, ys := range vals {
+			x, y := mustInt(t, xs), mustInt(t, ys)
+			bx, _ := new(big.Int).SetString(xs, 10)
+			by, _ := new(big.Int).SetString(ys, 10)
+			for _, tc := range []struct {
+				op        string
+				got, want string
+			}{
+				{"+", NewInt(0).Add(x, y).String(), new(big.Int).Add(bx, by).String()},
+				{"-", NewInt(0).Sub(x, y).String(), new(big.Int).Sub(bx, by).String()},
+				{"*", NewInt(0).Mul(x, y).String(), new(big.Int).Mul(bx, by).String()},
+			} {
+				if tc.got != tc.want {
+					t.Errorf("%s %s %s = %s, want %s", xs, tc.op, ys, tc.got, tc.want)
+				}
+			}
+			if c, want := x.Cmp(y), bx.Cmp(by); c != want || CmpInt(x, y) != want {
+				t.Errorf("Cmp(%s, %s) = %d, want %d", xs, ys, c, want)
+			}
+			// Reuse a large z for a small result and vice versa.
+			z := NewInt(0).Set(x)
+			if z.Sub(z, x).Sign() != 0 {
+				t.Errorf("%s - %s != 0", xs, xs)
+			}
+			z.Mul(y, y)
+			if z.Add(z, x).String() != new(big.Int).Add(new(big.Int).Mul(by, by), bx).String() {
+				t.Errorf("%s^2 + %s = %v", ys, xs, z)
+			}
+		}
+		bx, _ := new(big.Int).SetString(xs, 10)
+		if s := mustInt(t, xs).Sign(); s != bx.Sign() {
+			t.Errorf("Sign(%s) = %d, want %d", xs, s, bx.Sign())
+		}
+	}
+}
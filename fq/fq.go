@@ -0,0 +1,379 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+// Package fq implements arithmetic in finite fields GF(p^k),
+// represented as Z/pZ[x]/(f(x)) for an irreducible polynomial f.
+package fq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpz_poly.h>
+// #include <flint/fmpz_mod.h>
+// #include <flint/fmpz_mod_poly.h>
+// #include <flint/fq.h>
+import "C"
+
+import (
+	"math/big"
+	"math/rand"
+	"runtime"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// A Ctx describes the finite field GF(p^k).  Elements of
+// different contexts must not be mixed.
+type Ctx C.fq_ctx_struct
+
+// NewCtx returns the field GF(p^d).  The defining polynomial is a
+// Conway polynomial if FLINT has one for p and d, and a random
+// sparse irreducible polynomial otherwise.  The generator is
+// printed as v.  NewCtx panics with flint.ErrDomain if p is not
+// prime or d < 1.
+func NewCtx(p *fmpz.Int, d int, v string) *Ctx {
+	if !p.IsPrime() || d < 1 {
+		flint.Panic("fq.NewCtx", flint.ErrDomain)
+	}
+	c := new(Ctx)
+	s := C.CString(v)
+	defer C.free(unsafe.Pointer(s))
+	C.fq_ctx_init(c.ctx(), (*C.fmpz)(p), C.slong(d), s)
+	runtime.SetFinalizer(c, (*Ctx).destroy)
+	return c
+}
+
+// NewCtxUint64 is like NewCtx for a word-size prime p.
+func NewCtxUint64(p uint64, d int, v string) *Ctx {
+	q := fmpz.NewInt(0)
+	C.fmpz_set_ui((*C.fmpz)(q), C.ulong(p))
+	return NewCtx(q, d, v)
+}
+
+// NewCtxModulus returns the field Z/pZ[v]/(m(v)), where p is the
+// modulus of the ring of m.  NewCtxModulus panics with
+// flint.ErrDomain if p is not prime or m is not irreducible.
+func NewCtxModulus(m *fmpz.ModPoly, v string) *Ctx {
+	p := m.Ring().Modulus()
+	if !p.IsPrime() || m.Degree() < 1 || !m.IsIrreducible() {
+		flint.Panic("fq.NewCtxModulus", flint.ErrDomain)
+	}
+
+	var mc C.fmpz_mod_ctx_struct
+	C.fmpz_mod_ctx_init(&mc, (*C.fmpz)(p))
+	defer C.fmpz_mod_ctx_clear(&mc)
+	var mp C.fmpz_mod_poly_struct
+	C.fmpz_mod_poly_init(&mp, &mc)
+	defer C.fmpz_mod_poly_clear(&mp, &mc)
+	for i, a := range m.Coeffs() {
+		C.fmpz_mod_poly_set_coeff_fmpz(&mp, C.slong(i), (*C.fmpz)(a), &mc)
+	}
+
+	c := new(Ctx)
+	s := C.CString(v)
+	defer C.free(unsafe.Pointer(s))
+	C.fq_ctx_init_modulus(c.ctx(), &mp, &mc, s)
+	runtime.SetFinalizer(c, (*Ctx).destroy)
+	return c
+}
+
+func (c *Ctx) destroy() {
+	C.fq_ctx_clear(c.ctx())
+}
+
+func (c *Ctx) ctx() *C.fq_ctx_struct {
+	return (*C.fq_ctx_struct)(c)
+}
+
+// Prime returns the characteristic p of c.
+func (c *Ctx) Prime() *fmpz.Int {
+	p := fmpz.NewInt(0)
+	C.fmpz_set((*C.fmpz)(p), C.fq_ctx_prime(c.ctx()))
+	return p
+}
+
+// Degree returns the degree k of c over its prime field.
+func (c *Ctx) Degree() int {
+	return int(C.fq_ctx_degree(c.ctx()))
+}
+
+// Order returns the number of elements p^k of c.
+func (c *Ctx) Order() *fmpz.Int {
+	q := fmpz.NewInt(0)
+	C.fq_ctx_order((*C.fmpz)(q), c.ctx())
+	return q
+}
+
+// Modulus returns the coefficients of the defining polynomial of
+// c, starting with the constant term.
+func (c *Ctx) Modulus() []*fmpz.Int {
+	m := C.fq_ctx_modulus(c.ctx())
+	n := int(C.fmpz_mod_poly_length(m, &c.ctxp[0]))
+	cs := make([]*fmpz.Int, n)
+	for i := range cs {
+		cs[i] = fmpz.NewInt(0)
+		C.fmpz_mod_poly_get_coeff_fmpz((*C.fmpz)(cs[i]), m, C.slong(i), &c.ctxp[0])
+	}
+	return cs
+}
+
+// Gen returns the generator of c over its prime field, that is
+// the class of v in Z/pZ[v]/(f(v)).
+func (c *Ctx) Gen() *Elem {
+	z := NewElem(c)
+	C.fq_gen(&z.e, c.ctx())
+	return z
+}
+
+// Primitive returns a generator of the multiplicative group of c.
+// It tries the generator of c first and then random elements drawn
+// from rnd.  If rnd is nil, Primitive uses a source with a fixed
+// seed, so that its result is reproducible.
+func (c *Ctx) Primitive(rnd *rand.Rand) *Elem {
+	z := c.Gen()
+	if !z.IsPrimitive() && rnd == nil {
+		rnd = rand.New(rand.NewSource(1))
+	}
+	for !z.IsPrimitive() {
+		z.Rand(rnd)
+	}
+	return z
+}
+
+// An Elem represents an element of a finite field.
+type Elem struct {
+	e C.fq_struct
+	c *Ctx
+}
+
+// NewElem returns the zero element of c.
+func NewElem(c *Ctx) *Elem {
+	z := &Elem{c: c}
+	C.fq_init(&z.e, c.ctx())
+	runtime.SetFinalizer(z, (*Elem).destroy)
+	return z
+}
+
+func (z *Elem) destroy() {
+	C.fq_clear(&z.e, z.c.ctx())
+}
+
+func (z *Elem) ctx() *C.fq_ctx_struct {
+	return z.c.ctx()
+}
+
+// same panics unless all of xs belong to the field of z.
+func (z *Elem) same(xs ...*Elem) {
+	for _, x := range xs {
+		if x.c != z.c {
+			panic("fq: operands belong to different fields")
+		}
+	}
+}
+
+// Ctx returns the field of z.
+func (z *Elem) Ctx() *Ctx {
+	return z.c
+}
+
+// Set sets z = x and returns z.
+func (z *Elem) Set(x *Elem) *Elem {
+	z.same(x)
+	C.fq_set(&z.e, &x.e, z.ctx())
+	return z
+}
+
+// SetInt64 sets z to the image of x in the prime field and returns z.
+func (z *Elem) SetInt64(x int64) *Elem {
+	C.fq_set_si(&z.e, C.slong(x), z.ctx())
+	return z
+}
+
+// SetInt sets z to the image of x in the prime field and returns z.
+func (z *Elem) SetInt(x *fmpz.Int) *Elem {
+	C.fq_set_fmpz(&z.e, (*C.fmpz)(x), z.ctx())
+	return z
+}
+
+// SetCoeffs sets z = c[0] + c[1]*v + c[2]*v^2 + ... reduced
+// modulo p and the defining polynomial, and returns z.
+func (z *Elem) SetCoeffs(c []*fmpz.Int) *Elem {
+	C.fq_zero(&z.e, z.ctx())
+	for i := len(c) - 1; i >= 0; i-- {
+		C.fmpz_poly_set_coeff_fmpz(&z.e, C.slong(i), (*C.fmpz)(c[i]))
+	}
+	C.fq_reduce(&z.e, z.ctx())
+	return z
+}
+
+// Coeffs returns the coordinates of z in the basis 1, v, ..., v^(k-1),
+// as a slice of length k.
+func (z *Elem) Coeffs() []*fmpz.Int {
+	c := make([]*fmpz.Int, z.c.Degree())
+	for i := range c {
+		c[i] = fmpz.NewInt(0)
+		C.fmpz_poly_get_coeff_fmpz((*C.fmpz)(c[i]), &z.e, C.slong(i))
+	}
+	return c
+}
+
+// Rand sets z to an element drawn uniformly from the field using
+// rnd, and returns z.
+func (z *Elem) Rand(rnd *rand.Rand) *Elem {
+	p, _ := new(big.Int).SetString(z.c.Prime().String(), 10)
+	t := new(big.Int)
+	c := make([]*fmpz.Int, z.c.Degree())
+	for i := range c {
+		t.Rand(rnd, p)
+		c[i], _ = fmpz.NewInt(0).SetString(t.String(), 10)
+	}
+	return z.SetCoeffs(c)
+}
+
+// String returns a string representation of z as a polynomial
+// in the generator of its field.
+func (z *Elem) String() string {
+	p := C.fq_get_str_pretty(&z.e, z.ctx())
+	defer C.free(unsafe.Pointer(p))
+	return C.GoString(p)
+}
+
+// Equal reports whether z and x are equal.
+func (z *Elem) Equal(x *Elem) bool {
+	z.same(x)
+	return C.fq_equal(&z.e, &x.e, z.ctx()) != 0
+}
+
+// IsZero reports whether z is zero.
+func (z *Elem) IsZero() bool {
+	return C.fq_is_zero(&z.e, z.ctx()) != 0
+}
+
+// IsOne reports whether z is one.
+func (z *Elem) IsOne() bool {
+	return C.fq_is_one(&z.e, z.ctx()) != 0
+}
+
+// Add sets z = x + y and returns z.
+func (z *Elem) Add(x, y *Elem) *Elem {
+	z.same(x, y)
+	C.fq_add(&z.e, &x.e, &y.e, z.ctx())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *Elem) Sub(x, y *Elem) *Elem {
+	z.same(x, y)
+	C.fq_sub(&z.e, &x.e, &y.e, z.ctx())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *Elem) Neg(x *Elem) *Elem {
+	z.same(x)
+	C.fq_neg(&z.e, &x.e, z.ctx())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *Elem) Mul(x, y *Elem) *Elem {
+	z.same(x, y)
+	C.fq_mul(&z.e, &x.e, &y.e, z.ctx())
+	return z
+}
+
+// Inv sets z = 1/x and returns (z, true).  If x is zero, z is
+// unchanged and Inv returns (z, false).
+func (z *Elem) Inv(x *Elem) (*Elem, bool) {
+	z.same(x)
+	if x.IsZero() {
+		return z, false
+	}
+	C.fq_inv(&z.e, &x.e, z.ctx())
+	return z, true
+}
+
+// Div sets z = x / y and returns (z, true).  If y is zero, z is
+// unchanged and Div returns (z, false).
+func (z *Elem) Div(x, y *Elem) (*Elem, bool) {
+	z.same(x, y)
+	if y.IsZero() {
+		return z, false
+	}
+	C.fq_div(&z.e, &x.e, &y.e, z.ctx())
+	return z, true
+}
+
+// Pow sets z = x^e and returns (z, true).  A negative exponent is
+// allowed for non-zero x; for x = 0 and e < 0, z is unchanged and
+// Pow returns (z, false).
+func (z *Elem) Pow(x *Elem, e *fmpz.Int) (*Elem, bool) {
+	z.same(x)
+	if e.Sign() < 0 {
+		t := NewElem(z.c)
+		if _, ok := t.Inv(x); !ok {
+			return z, false
+		}
+		C.fq_pow(&z.e, &t.e, (*C.fmpz)(fmpz.NewInt(0).Neg(e)), z.ctx())
+		return z, true
+	}
+	C.fq_pow(&z.e, &x.e, (*C.fmpz)(e), z.ctx())
+	return z, true
+}
+
+// Frobenius sets z = x^(p^e) and returns z.
+func (z *Elem) Frobenius(x *Elem, e int) *Elem {
+	z.same(x)
+	C.fq_frobenius(&z.e, &x.e, C.slong(e), z.ctx())
+	return z
+}
+
+// Trace returns the absolute trace of z, an element of the prime
+// field, as an Int in [0, p).
+func (z *Elem) Trace() *fmpz.Int {
+	t := fmpz.NewInt(0)
+	C.fq_trace((*C.fmpz)(t), &z.e, z.ctx())
+	return t
+}
+
+// Norm returns the absolute norm of z, an element of the prime
+// field, as an Int in [0, p).
+func (z *Elem) Norm() *fmpz.Int {
+	t := fmpz.NewInt(0)
+	C.fq_norm((*C.fmpz)(t), &z.e, z.ctx())
+	return t
+}
+
+// IsSquare reports whether z is a square.
+func (z *Elem) IsSquare() bool {
+	return C.fq_is_square(&z.e, z.ctx()) != 0
+}
+
+// Sqrt sets z to a square root of x and returns (z, true).  If x
+// is not a square, z is unchanged and Sqrt returns (z, false).
+func (z *Elem) Sqrt(x *Elem) (*Elem, bool) {
+	z.same(x)
+	t := NewElem(z.c)
+	if C.fq_sqrt(&t.e, &x.e, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// IsPrimitive reports whether z generates the multiplicative
+// group of its field.
+func (z *Elem) IsPrimitive() bool {
+	return C.fq_is_primitive(&z.e, z.ctx()) != 0
+}
+
+// Order returns the multiplicative order of z, or 0 if z is zero.
+func (z *Elem) Order() *fmpz.Int {
+	o := fmpz.NewInt(0)
+	C.fq_multiplicative_order((*C.fmpz)(o), &z.e, z.ctx())
+	return o
+}
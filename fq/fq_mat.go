@@ -0,0 +1,266 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fq.h>
+// #include <flint/fq_mat.h>
+//
+// static slong goflint_fq_mat_rref(fq_mat_struct *a, const fq_ctx_struct *ctx)
+// {
+// #if __FLINT_RELEASE >= 30000
+// 	return fq_mat_rref(a, a, ctx);
+// #else
+// 	return fq_mat_rref(a, ctx);
+// #endif
+// }
+import "C"
+
+import (
+	"runtime"
+	"strings"
+)
+
+// A Mat represents a dense matrix with entries in a finite field.
+type Mat struct {
+	m C.fq_mat_struct
+	c *Ctx
+}
+
+// NewMat returns the zero matrix over c with the given dimensions.
+func NewMat(c *Ctx, rows, cols int) *Mat {
+	z := &Mat{c: c}
+	C.fq_mat_init(&z.m, C.slong(rows), C.slong(cols), c.ctx())
+	runtime.SetFinalizer(z, (*Mat).destroy)
+	return z
+}
+
+func (z *Mat) destroy() {
+	C.fq_mat_clear(&z.m, z.c.ctx())
+}
+
+func (z *Mat) ctx() *C.fq_ctx_struct {
+	return z.c.ctx()
+}
+
+// same panics unless all of xs belong to the field of z.
+func (z *Mat) same(xs ...*Mat) {
+	for _, x := range xs {
+		if x.c != z.c {
+			panic("fq: operands belong to different fields")
+		}
+	}
+}
+
+// Ctx returns the field of z.
+func (z *Mat) Ctx() *Ctx {
+	return z.c
+}
+
+// Rows returns the number of rows of z.
+func (z *Mat) Rows() int {
+	return int(C.fq_mat_nrows(&z.m, z.ctx()))
+}
+
+// Cols returns the number of columns of z.
+func (z *Mat) Cols() int {
+	return int(C.fq_mat_ncols(&z.m, z.ctx()))
+}
+
+func (z *Mat) check(i, j int) {
+	if i < 0 || i >= z.Rows() || j < 0 || j >= z.Cols() {
+		panic("fq: Mat index out of range")
+	}
+}
+
+// Entry returns a new Elem equal to the entry of z in row i and
+// column j.
+func (z *Mat) Entry(i, j int) *Elem {
+	z.check(i, j)
+	a := NewElem(z.c)
+	C.fq_set(&a.e, C.fq_mat_entry(&z.m, C.slong(i), C.slong(j)), z.ctx())
+	return a
+}
+
+// SetEntry sets the entry of z in row i and column j to a and
+// returns z.
+func (z *Mat) SetEntry(i, j int, a *Elem) *Mat {
+	z.check(i, j)
+	if a.c != z.c {
+		panic("fq: operands belong to different fields")
+	}
+	C.fq_mat_entry_set(&z.m, C.slong(i), C.slong(j), &a.e, z.ctx())
+	return z
+}
+
+// Set sets z = x and returns z.  The matrices must have the same
+// dimensions.
+func (z *Mat) Set(x *Mat) *Mat {
+	z.same(x)
+	z.dims(x.Rows(), x.Cols())
+	C.fq_mat_set(&z.m, &x.m, z.ctx())
+	return z
+}
+
+// dims panics unless z has the given dimensions.
+func (z *Mat) dims(rows, cols int) {
+	if z.Rows() != rows || z.Cols() != cols {
+		panic("fq: Mat dimension mismatch")
+	}
+}
+
+// Equal reports whether z and x are equal.
+func (z *Mat) Equal(x *Mat) bool {
+	z.same(x)
+	if z.Rows() != x.Rows() || z.Cols() != x.Cols() {
+		return false
+	}
+	return C.fq_mat_equal(&z.m, &x.m, z.ctx()) != 0
+}
+
+// IsZero reports whether z is the zero matrix.
+func (z *Mat) IsZero() bool {
+	return C.fq_mat_is_zero(&z.m, z.ctx()) != 0
+}
+
+// String returns a string representation of z, one row per line.
+func (z *Mat) String() string {
+	var b strings.Builder
+	for i := 0; i < z.Rows(); i++ {
+		b.WriteString("[")
+		for j := 0; j < z.Cols(); j++ {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(z.Entry(i, j).String())
+		}
+		b.WriteString("]\n")
+	}
+	return b.String()
+}
+
+// Add sets z = x + y and returns z.
+func (z *Mat) Add(x, y *Mat) *Mat {
+	z.same(x, y)
+	z.dims(x.Rows(), x.Cols())
+	y.dims(x.Rows(), x.Cols())
+	C.fq_mat_add(&z.m, &x.m, &y.m, z.ctx())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *Mat) Sub(x, y *Mat) *Mat {
+	z.same(x, y)
+	z.dims(x.Rows(), x.Cols())
+	y.dims(x.Rows(), x.Cols())
+	C.fq_mat_sub(&z.m, &x.m, &y.m, z.ctx())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *Mat) Neg(x *Mat) *Mat {
+	z.same(x)
+	z.dims(x.Rows(), x.Cols())
+	C.fq_mat_neg(&z.m, &x.m, z.ctx())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *Mat) Mul(x, y *Mat) *Mat {
+	z.same(x, y)
+	if x.Cols() != y.Rows() {
+		panic("fq: Mat dimension mismatch")
+	}
+	z.dims(x.Rows(), y.Cols())
+	t := NewMat(z.c, x.Rows(), y.Cols())
+	C.fq_mat_mul(&t.m, &x.m, &y.m, z.ctx())
+	return z.Set(t)
+}
+
+// Rank returns the rank of z.
+func (z *Mat) Rank() int {
+	return int(C.fq_mat_rank(&z.m, z.ctx()))
+}
+
+// RREF sets z to the reduced row echelon form of x and returns z
+// and the rank of x.
+func (z *Mat) RREF(x *Mat) (*Mat, int) {
+	z.Set(x)
+	r := int(C.goflint_fq_mat_rref(&z.m, z.ctx()))
+	return z, r
+}
+
+// Inv sets z to the inverse of x and returns (z, true).  If x is
+// singular, z is unchanged and Inv returns (z, false).
+func (z *Mat) Inv(x *Mat) (*Mat, bool) {
+	z.same(x)
+	n := x.Rows()
+	if x.Cols() != n {
+		panic("fq: inverse of a non-square Mat")
+	}
+	z.dims(n, n)
+	t := NewMat(z.c, n, n)
+	if C.fq_mat_inv(&t.m, &x.m, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Solve sets z to a solution X of a*X = b and returns (z, true).
+// The matrix a may be singular or non-square.  If the system has
+// no solution, z is unchanged and Solve returns (z, false).
+func (z *Mat) Solve(a, b *Mat) (*Mat, bool) {
+	z.same(a, b)
+	if a.Rows() != b.Rows() {
+		panic("fq: Mat dimension mismatch")
+	}
+	z.dims(a.Cols(), b.Cols())
+	t := NewMat(z.c, a.Cols(), b.Cols())
+	if C.fq_mat_can_solve(&t.m, &a.m, &b.m, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Nullspace returns a matrix whose columns form a basis of the
+// right nullspace {v : z*v = 0} of z.  The result has z.Cols()
+// rows and as many columns as the nullity of z.
+func (z *Mat) Nullspace() *Mat {
+	rows, cols := z.Rows(), z.Cols()
+	r, rank := NewMat(z.c, rows, cols).RREF(z)
+
+	// pivots[i] is the column of the leading entry of row i.
+	pivots := make([]int, 0, rank)
+	isPivot := make([]bool, cols)
+	for i, j := 0, 0; i < rank; i++ {
+		for C.fq_is_zero(C.fq_mat_entry(&r.m, C.slong(i), C.slong(j)), z.ctx()) != 0 {
+			j++
+		}
+		pivots = append(pivots, j)
+		isPivot[j] = true
+	}
+
+	n := NewMat(z.c, cols, cols-rank)
+	one := NewElem(z.c).SetInt64(1)
+	t := NewElem(z.c)
+	k := 0
+	for j := 0; j < cols; j++ {
+		if isPivot[j] {
+			continue
+		}
+		// Free variable j set to 1; solve for the pivot variables.
+		n.SetEntry(j, k, one)
+		for i, p := range pivots {
+			C.fq_neg(&t.e, C.fq_mat_entry(&r.m, C.slong(i), C.slong(j)), z.ctx())
+			n.SetEntry(p, k, t)
+		}
+		k++
+	}
+	return n
+}
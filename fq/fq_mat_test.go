@@ -0,0 +1,90 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fq
+
+import "testing"
+
+// mat returns the rows x cols matrix over c with the given entries
+// in row-major order.
+func mat(c *Ctx, rows, cols int, a ...*Elem) *Mat {
+	z := NewMat(c, rows, cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			z.SetEntry(i, j, a[i*cols+j])
+		}
+	}
+	return z
+}
+
+func TestMat(t *testing.T) {
+	c := NewCtxUint64(5, 2, "a")
+	g := c.Gen()
+	g2 := NewElem(c).Mul(g, g)
+	one, zero := elem(c, 1), elem(c, 0)
+
+	// The second row is g times the first.
+	a := mat(c, 2, 3, one, g, one, g, g2, g)
+	if a.Rows() != 2 || a.Cols() != 3 || a.Rank() != 1 {
+		t.Errorf("a is %dx%d of rank %d, want 2x3 of rank 1", a.Rows(), a.Cols(), a.Rank())
+	}
+	if !a.Entry(1, 1).Equal(g2) {
+		t.Errorf("Entry(1, 1) = %v, want %v", a.Entry(1, 1), g2)
+	}
+	r, rank := NewMat(c, 2, 3).RREF(a)
+	if rank != 1 || !r.Equal(mat(c, 2, 3, one, g, one, zero, zero, zero)) {
+		t.Errorf("RREF = %v, %d", r, rank)
+	}
+
+	n := a.Nullspace()
+	if n.Rows() != 3 || n.Cols() != 2 {
+		t.Fatalf("Nullspace is %dx%d, want 3x2", n.Rows(), n.Cols())
+	}
+	if p := NewMat(c, 2, 2).Mul(a, n); !p.IsZero() || n.Rank() != 2 {
+		t.Errorf("a * Nullspace = %v, want 0", p)
+	}
+
+	s := NewMat(c, 2, 3).Add(a, a)
+	if d := NewMat(c, 2, 3).Sub(s, a); !d.Equal(a) {
+		t.Errorf("2a - a = %v, want %v", d, a)
+	}
+	if d := NewMat(c, 2, 3).Add(a, NewMat(c, 2, 3).Neg(a)); !d.IsZero() {
+		t.Errorf("a - a = %v, want 0", d)
+	}
+}
+
+func TestMatSolve(t *testing.T) {
+	c := NewCtxUint64(5, 2, "a")
+	g := c.Gen()
+	one, zero := elem(c, 1), elem(c, 0)
+
+	m := mat(c, 2, 2, one, g, zero, one)
+	inv, ok := NewMat(c, 2, 2).Inv(m)
+	if !ok || !inv.Equal(mat(c, 2, 2, one, NewElem(c).Neg(g), zero, one)) {
+		t.Errorf("Inv = %v, %v", inv, ok)
+	}
+	if p := NewMat(c, 2, 2).Mul(m, inv); !p.Equal(mat(c, 2, 2, one, zero, zero, one)) {
+		t.Errorf("m * m^-1 = %v, want 1", p)
+	}
+	singular := mat(c, 2, 2, one, g, g, NewElem(c).Mul(g, g))
+	if z, ok := NewMat(c, 2, 2).Inv(singular); ok || !z.IsZero() {
+		t.Errorf("Inv of a singular matrix = %v, %v", z, ok)
+	}
+
+	b := mat(c, 2, 1, g, one)
+	x, ok := NewMat(c, 2, 1).Solve(m, b)
+	if !ok || !NewMat(c, 2, 1).Mul(m, x).Equal(b) {
+		t.Errorf("Solve = %v, %v", x, ok)
+	}
+	// singular * x = (1, 0) has no solution, (1, g) has many.
+	if _, ok := NewMat(c, 2, 1).Solve(singular, mat(c, 2, 1, one, zero)); ok {
+		t.Errorf("Solve of an inconsistent system succeeded")
+	}
+	b = mat(c, 2, 1, one, g)
+	if x, ok := NewMat(c, 2, 1).Solve(singular, b); !ok || !NewMat(c, 2, 1).Mul(singular, x).Equal(b) {
+		t.Errorf("Solve of a consistent singular system = %v, %v", x, ok)
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package fq
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fq.h>
+// #include <flint/fq_poly.h>
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// A Poly represents a univariate polynomial with coefficients
+// in a finite field.
+type Poly struct {
+	p C.fq_poly_struct
+	c *Ctx
+}
+
+// NewPoly returns the zero polynomial over c.
+func NewPoly(c *Ctx) *Poly {
+	z := &Poly{c: c}
+	C.fq_poly_init(&z.p, c.ctx())
+	runtime.SetFinalizer(z, (*Poly).destroy)
+	return z
+}
+
+// NewPolyCoeffs returns the polynomial over c with the given
+// coefficients, starting with the constant term.
+func NewPolyCoeffs(c *Ctx, a []*Elem) *Poly {
+	z := NewPoly(c)
+	for i := len(a) - 1; i >= 0; i-- {
+		z.SetCoeff(i, a[i])
+	}
+	return z
+}
+
+func (z *Poly) destroy() {
+	C.fq_poly_clear(&z.p, z.c.ctx())
+}
+
+func (z *Poly) ctx() *C.fq_ctx_struct {
+	return z.c.ctx()
+}
+
+// same panics unless all of xs belong to the field of z.
+func (z *Poly) same(xs ...*Poly) {
+	for _, x := range xs {
+		if x.c != z.c {
+			panic("fq: operands belong to different fields")
+		}
+	}
+}
+
+// Ctx returns the field of z.
+func (z *Poly) Ctx() *Ctx {
+	return z.c
+}
+
+// Degree returns the degree of z.  The degree of the zero
+// polynomial is -1.
+func (z *Poly) Degree() int {
+	return int(C.fq_poly_degree(&z.p, z.ctx()))
+}
+
+// Coeff returns a new Elem equal to the coefficient of x^n in z.
+func (z *Poly) Coeff(n int) *Elem {
+	a := NewElem(z.c)
+	C.fq_poly_get_coeff(&a.e, &z.p, C.slong(n), z.ctx())
+	return a
+}
+
+// SetCoeff sets the coefficient of x^n in z to a and returns z.
+func (z *Poly) SetCoeff(n int, a *Elem) *Poly {
+	if a.c != z.c {
+		panic("fq: operands belong to different fields")
+	}
+	C.fq_poly_set_coeff(&z.p, C.slong(n), &a.e, z.ctx())
+	return z
+}
+
+// Set sets z = x and returns z.
+func (z *Poly) Set(x *Poly) *Poly {
+	z.same(x)
+	C.fq_poly_set(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// Equal reports whether z and x are equal.
+func (z *Poly) Equal(x *Poly) bool {
+	z.same(x)
+	return C.fq_poly_equal(&z.p, &x.p, z.ctx()) != 0
+}
+
+// String returns a string representation of z as a
+// polynomial in the variable 'x'.
+func (z *Poly) String() string {
+	v := C.CString("x")
+	defer C.free(unsafe.Pointer(v))
+	p := C.fq_poly_get_str_pretty(&z.p, v, z.ctx())
+	defer C.free(unsafe.Pointer(p))
+	return C.GoString(p)
+}
+
+// Add sets z = x + y and returns z.
+func (z *Poly) Add(x, y *Poly) *Poly {
+	z.same(x, y)
+	C.fq_poly_add(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *Poly) Sub(x, y *Poly) *Poly {
+	z.same(x, y)
+	C.fq_poly_sub(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *Poly) Neg(x *Poly) *Poly {
+	z.same(x)
+	C.fq_poly_neg(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *Poly) Mul(x, y *Poly) *Poly {
+	z.same(x, y)
+	C.fq_poly_mul(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// ScalarMul sets z = a*x and returns z.
+func (z *Poly) ScalarMul(x *Poly, a *Elem) *Poly {
+	z.same(x)
+	C.fq_poly_scalar_mul_fq(&z.p, &x.p, &a.e, z.ctx())
+	return z
+}
+
+// DivMod sets z to the quotient and m to the remainder of x
+// divided by y and returns (z, m).  If y is zero, a
+// division-by-zero run-time panic occurs.
+func (z *Poly) DivMod(x, y, m *Poly) (*Poly, *Poly) {
+	z.same(x, y, m)
+	if y.Degree() < 0 {
+		flint.Panic("fq.Poly.DivMod", flint.ErrDivisionByZero)
+	}
+	q := NewPoly(z.c)
+	r := NewPoly(z.c)
+	C.fq_poly_divrem(&q.p, &r.p, &x.p, &y.p, z.ctx())
+	z.Set(q)
+	m.Set(r)
+	return z, m
+}
+
+// MakeMonic sets z to x divided by its leading coefficient and
+// returns z.
+func (z *Poly) MakeMonic(x *Poly) *Poly {
+	z.same(x)
+	C.fq_poly_make_monic(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// Derivative sets z to the derivative of x and returns z.
+func (z *Poly) Derivative(x *Poly) *Poly {
+	z.same(x)
+	C.fq_poly_derivative(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// GCD sets z to the monic greatest common divisor of x and y and
+// returns z.
+func (z *Poly) GCD(x, y *Poly) *Poly {
+	z.same(x, y)
+	C.fq_poly_gcd(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// XGCD sets z to the monic greatest common divisor of x and y, and
+// s and t such that z = s*x + t*y, and returns z.
+func (z *Poly) XGCD(s, t, x, y *Poly) *Poly {
+	z.same(s, t, x, y)
+	C.fq_poly_xgcd(&z.p, &s.p, &t.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// PowMod sets z = x^e mod f and returns z.  If f is zero, PowMod
+// panics with flint.ErrDivisionByZero, and if e is negative, with
+// flint.ErrDomain.
+func (z *Poly) PowMod(x *Poly, e *fmpz.Int, f *Poly) *Poly {
+	z.same(x, f)
+	if f.Degree() < 0 {
+		flint.Panic("fq.Poly.PowMod", flint.ErrDivisionByZero)
+	}
+	if e.Sign() < 0 {
+		flint.Panic("fq.Poly.PowMod", flint.ErrDomain)
+	}
+	t := NewPoly(z.c)
+	C.fq_poly_rem(&t.p, &x.p, &f.p, z.ctx())
+	C.fq_poly_powmod_fmpz_binexp(&z.p, &t.p, (*C.fmpz)(e), &f.p, z.ctx())
+	return z
+}
+
+// Compose sets z = x(y) and returns z.
+func (z *Poly) Compose(x, y *Poly) *Poly {
+	z.same(x, y)
+	C.fq_poly_compose(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Evaluate sets y to the value of z at a and returns y.
+func (z *Poly) Evaluate(y, a *Elem) *Elem {
+	if y.c != z.c || a.c != z.c {
+		panic("fq: operands belong to different fields")
+	}
+	C.fq_poly_evaluate_fq(&y.e, &z.p, &a.e, z.ctx())
+	return y
+}
+
+// IsIrreducible reports whether z is irreducible.
+func (z *Poly) IsIrreducible() bool {
+	return C.fq_poly_is_irreducible(&z.p, z.ctx()) != 0
+}
+
+// factors copies the factors of f into new Polys.
+func (z *Poly) factors(f *C.fq_poly_factor_struct) ([]*Poly, []int) {
+	n := int(f.num)
+	if n == 0 {
+		return nil, nil
+	}
+	ps := unsafe.Slice(f.poly, n)
+	exps := unsafe.Slice(f.exp, n)
+	fs := make([]*Poly, n)
+	es := make([]int, n)
+	for i := 0; i < n; i++ {
+		fs[i] = NewPoly(z.c)
+		C.fq_poly_set(&fs[i].p, &ps[i], z.ctx())
+		es[i] = int(exps[i])
+	}
+	return fs, es
+}
+
+// Roots returns the distinct roots of z in its field together
+// with their multiplicities.  If z is zero, Roots panics with
+// flint.ErrDomain.
+func (z *Poly) Roots() ([]*Elem, []int) {
+	if z.Degree() < 0 {
+		flint.Panic("fq.Poly.Roots", flint.ErrDomain)
+	}
+	var f C.fq_poly_factor_struct
+	C.fq_poly_factor_init(&f, z.ctx())
+	defer C.fq_poly_factor_clear(&f, z.ctx())
+	C.fq_poly_roots(&f, &z.p, 1, z.ctx())
+
+	fs, es := z.factors(&f)
+	roots := make([]*Elem, len(fs))
+	for i, g := range fs {
+		// Each factor is monic and linear, x - a.
+		roots[i] = g.Coeff(0)
+		roots[i].Neg(roots[i])
+	}
+	return roots, es
+}
+
+// Factor returns the factorisation of z into monic irreducible
+// polynomials as two slices of the same length: the factors and
+// their multiplicities.  The leading coefficient of z is returned
+// separately.  If z is zero, Factor panics with flint.ErrDomain.
+func (z *Poly) Factor() (lead *Elem, fs []*Poly, es []int) {
+	if z.Degree() < 0 {
+		flint.Panic("fq.Poly.Factor", flint.ErrDomain)
+	}
+	lead = NewElem(z.c)
+
+	var f C.fq_poly_factor_struct
+	C.fq_poly_factor_init(&f, z.ctx())
+	defer C.fq_poly_factor_clear(&f, z.ctx())
+	C.fq_poly_factor(&f, &lead.e, &z.p, z.ctx())
+
+	fs, es = z.factors(&f)
+	return lead, fs, es
+}
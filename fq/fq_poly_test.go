@@ -0,0 +1,154 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// poly returns the polynomial over c with coefficients a, starting
+// with the constant term.
+func poly(c *Ctx, a ...*Elem) *Poly {
+	return NewPolyCoeffs(c, a)
+}
+
+func elem(c *Ctx, x int64) *Elem {
+	return NewElem(c).SetInt64(x)
+}
+
+func TestPolyArith(t *testing.T) {
+	c := NewCtxUint64(5, 2, "a")
+	g := c.Gen()
+	x := poly(c, elem(c, 0), elem(c, 1))
+	a := poly(c, elem(c, 1), elem(c, 1)) // x + 1
+	b := poly(c, g, elem(c, 1))          // x + a
+	p := NewPoly(c).Mul(a, b)
+	want := poly(c, g, NewElem(c).Add(g, elem(c, 1)), elem(c, 1))
+	if !p.Equal(want) || p.Degree() != 2 {
+		t.Errorf("(x+1)(x+a) = %v, want %v", p, want)
+	}
+	if s := NewPoly(c).Sub(NewPoly(c).Add(p, a), a); !s.Equal(p) {
+		t.Errorf("p + a - a = %v, want %v", s, p)
+	}
+	if s := NewPoly(c).Add(p, NewPoly(c).Neg(p)); s.Degree() != -1 {
+		t.Errorf("p - p = %v, want 0", s)
+	}
+	if d := NewPoly(c).Derivative(p); !d.Equal(NewPoly(c).Add(a, b)) {
+		t.Errorf("p' = %v, want 2x + a + 1", d)
+	}
+	if y := p.Evaluate(NewElem(c), NewElem(c).Neg(g)); !y.IsZero() {
+		t.Errorf("p(-a) = %v, want 0", y)
+	}
+	if q := NewPoly(c).Compose(a, b); !q.Equal(poly(c, NewElem(c).Add(g, elem(c, 1)), elem(c, 1))) {
+		t.Errorf("a(b) = %v, want x + a + 1", q)
+	}
+	m := NewPoly(c).ScalarMul(a, g)
+	if !m.Coeff(0).Equal(g) || !NewPoly(c).MakeMonic(m).Equal(a) {
+		t.Errorf("MakeMonic(a*(x+1)) = %v, want x + 1", NewPoly(c).MakeMonic(m))
+	}
+
+	// x^25 = x mod x^2 + 1, since x^2 + 1 splits over GF(5).
+	f := poly(c, elem(c, 1), elem(c, 0), elem(c, 1))
+	if z := NewPoly(c).PowMod(x, fmpz.NewInt(25), f); !z.Equal(x) {
+		t.Errorf("x^25 mod x^2+1 = %v, want x", z)
+	}
+}
+
+func TestPolyDivision(t *testing.T) {
+	c := NewCtxUint64(5, 2, "a")
+	g := c.Gen()
+	a := poly(c, elem(c, 1), elem(c, 1)) // x + 1
+	b := poly(c, g, elem(c, 1))          // x + a
+	p := NewPoly(c).Mul(a, b)
+	q, r := NewPoly(c).DivMod(p, a, NewPoly(c))
+	if !q.Equal(b) || r.Degree() != -1 {
+		t.Errorf("DivMod(p, x+1) = %v, %v, want x+a, 0", q, r)
+	}
+	q, r = NewPoly(c).DivMod(NewPoly(c).Add(p, poly(c, g)), b, NewPoly(c))
+	if !q.Equal(a) || !r.Equal(poly(c, g)) {
+		t.Errorf("DivMod(p+a, x+a) = %v, %v, want x+1, a", q, r)
+	}
+
+	u := NewPoly(c).Mul(p, a)
+	v := NewPoly(c).Mul(a, poly(c, elem(c, 3), elem(c, 1)))
+	if d := NewPoly(c).GCD(u, v); !d.Equal(a) {
+		t.Errorf("GCD = %v, want x + 1", d)
+	}
+	s, tt := NewPoly(c), NewPoly(c)
+	d := NewPoly(c).XGCD(s, tt, u, v)
+	if lhs := NewPoly(c).Add(NewPoly(c).Mul(s, u), NewPoly(c).Mul(tt, v)); !d.Equal(a) || !lhs.Equal(d) {
+		t.Errorf("XGCD = %v, %v, %v", d, s, tt)
+	}
+
+	zero := NewPoly(c)
+	for name, f := range map[string]func(){
+		"DivMod": func() { NewPoly(c).DivMod(p, zero, NewPoly(c)) },
+		"PowMod": func() { NewPoly(c).PowMod(p, fmpz.NewInt(2), zero) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDivisionByZero) {
+			t.Errorf("%s by zero: err = %v, want ErrDivisionByZero", name, err)
+		}
+	}
+	for name, f := range map[string]func(){
+		"PowMod": func() { NewPoly(c).PowMod(p, fmpz.NewInt(-1), a) },
+		"Roots":  func() { zero.Roots() },
+		"Factor": func() { zero.Factor() },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDomain) {
+			t.Errorf("%s: err = %v, want ErrDomain", name, err)
+		}
+	}
+}
+
+func TestPolyFactor(t *testing.T) {
+	c := NewCtxUint64(5, 2, "a")
+	// A primitive element is not a square, so x^2 - g is irreducible.
+	g := c.Primitive(nil)
+	f := poly(c, NewElem(c).Neg(g), elem(c, 0), elem(c, 1))
+	a := poly(c, elem(c, 1), elem(c, 1)) // x + 1
+	if !f.IsIrreducible() || NewPoly(c).Mul(a, a).IsIrreducible() {
+		t.Errorf("IsIrreducible gives wrong results")
+	}
+
+	// (x + 1)^2 (x + g) has roots -1 (twice) and -g.
+	p := NewPoly(c).Mul(NewPoly(c).Mul(a, a), poly(c, g, elem(c, 1)))
+	roots, mult := p.Roots()
+	if len(roots) != 2 {
+		t.Fatalf("Roots = %v, %v, want -1 (twice) and -g", roots, mult)
+	}
+	for i, r := range roots {
+		switch {
+		case r.Equal(elem(c, -1)) && mult[i] == 2:
+		case r.Equal(NewElem(c).Neg(g)) && mult[i] == 1:
+		default:
+			t.Errorf("Roots: unexpected root %v of multiplicity %d", r, mult[i])
+		}
+	}
+	if roots, _ := f.Roots(); len(roots) != 0 {
+		t.Errorf("Roots(x^2-g) = %v, want none", roots)
+	}
+
+	// 3 (x + 1)^2 (x^2 - g).
+	p = NewPoly(c).Mul(NewPoly(c).Mul(a, a), f)
+	p.ScalarMul(p, elem(c, 3))
+	lead, fs, es := p.Factor()
+	if !lead.Equal(elem(c, 3)) || len(fs) != 2 {
+		t.Fatalf("Factor = %v, %v, %v, want 3, [x+1 x^2-g], [2 1]", lead, fs, es)
+	}
+	for i := range fs {
+		switch {
+		case fs[i].Equal(a) && es[i] == 2:
+		case fs[i].Equal(f) && es[i] == 1:
+		default:
+			t.Errorf("Factor: unexpected factor (%v)^%d", fs[i], es[i])
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package fq
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+func ints(xs ...int64) []*fmpz.Int {
+	zs := make([]*fmpz.Int, len(xs))
+	for i, x := range xs {
+		zs[i] = fmpz.NewInt(x)
+	}
+	return zs
+}
+
+func TestCtx(t *testing.T) {
+	c := NewCtxUint64(5, 2, "a")
+	if c.Prime().Int64() != 5 || c.Degree() != 2 || c.Order().Int64() != 25 {
+		t.Errorf("GF(25) has p = %v, k = %d, q = %v", c.Prime(), c.Degree(), c.Order())
+	}
+	if m := c.Modulus(); len(m) != 3 || m[2].Int64() != 1 {
+		t.Errorf("Modulus = %v, want a monic quadratic", m)
+	}
+
+	// GF(49) = F_7[v]/(v^2 + 1), since -1 is not a square mod 7.
+	r := fmpz.NewModRing(fmpz.NewInt(7))
+	d := NewCtxModulus(fmpz.NewModPolyCoeffs(r, ints(1, 0, 1)), "v")
+	v := d.Gen()
+	if w := NewElem(d).Mul(v, v); !w.Equal(NewElem(d).SetInt64(-1)) {
+		t.Errorf("v^2 = %v, want -1", w)
+	}
+	if d.Order().Int64() != 49 {
+		t.Errorf("Order = %v, want 49", d.Order())
+	}
+
+	r6 := fmpz.NewModRing(fmpz.NewInt(6))
+	for name, f := range map[string]func(){
+		"NewCtx(6, 2)":           func() { NewCtxUint64(6, 2, "a") },
+		"NewCtx(5, 0)":           func() { NewCtxUint64(5, 0, "a") },
+		"NewCtxModulus(v^2 - 1)": func() { NewCtxModulus(fmpz.NewModPolyCoeffs(r, ints(-1, 0, 1)), "v") },
+		"NewCtxModulus(1)":       func() { NewCtxModulus(fmpz.NewModPolyCoeffs(r, ints(1)), "v") },
+		"NewCtxModulus mod 6":    func() { NewCtxModulus(fmpz.NewModPolyCoeffs(r6, ints(1, 0, 1)), "v") },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDomain) {
+			t.Errorf("%s: err = %v, want ErrDomain", name, err)
+		}
+	}
+}
+
+func TestElemArith(t *testing.T) {
+	c := NewCtxUint64(5, 2, "a")
+	g := c.Gen()
+	one := NewElem(c).SetInt64(1)
+
+	if z := NewElem(c).SetCoeffs(ints(7, -1)).Coeffs(); z[0].Int64() != 2 || z[1].Int64() != 4 {
+		t.Errorf("Coeffs(SetCoeffs(7, -1)) = %v, want [2 4]", z)
+	}
+	if z, ok := NewElem(c).Pow(g, fmpz.NewInt(24)); !ok || !z.IsOne() {
+		t.Errorf("a^24 = %v, want 1", z)
+	}
+	if z := NewElem(c).Frobenius(g, 1); !z.Equal(mustPow(t, g, 5)) {
+		t.Errorf("Frobenius(a) = %v, want a^5", z)
+	}
+	if z := NewElem(c).Frobenius(g, 2); !z.Equal(g) {
+		t.Errorf("Frobenius^2(a) = %v, want a", z)
+	}
+
+	h, ok := NewElem(c).Inv(g)
+	if !ok || !NewElem(c).Mul(g, h).Equal(one) {
+		t.Errorf("a * a^-1 = %v, want 1", NewElem(c).Mul(g, h))
+	}
+	if z, ok := NewElem(c).Div(one, g); !ok || !z.Equal(h) {
+		t.Errorf("1/a = %v, want %v", z, h)
+	}
+	if z, ok := NewElem(c).Pow(g, fmpz.NewInt(-3)); !ok || !NewElem(c).Mul(z, mustPow(t, g, 3)).IsOne() {
+		t.Errorf("a^-3 = %v", z)
+	}
+	zero := NewElem(c)
+	if _, ok := NewElem(c).Inv(zero); ok {
+		t.Errorf("Inv(0) succeeded")
+	}
+	if _, ok := NewElem(c).Div(g, zero); ok {
+		t.Errorf("a/0 succeeded")
+	}
+	if _, ok := NewElem(c).Pow(zero, fmpz.NewInt(-1)); ok {
+		t.Errorf("0^-1 succeeded")
+	}
+	if z := NewElem(c).Sub(NewElem(c).Add(g, one), g); !z.Equal(one) || !NewElem(c).Add(g, NewElem(c).Neg(g)).IsZero() {
+		t.Errorf("Add, Sub and Neg are inconsistent")
+	}
+
+	// For a in the prime field, Tr(a) = 2a and N(a) = a^2.
+	a := NewElem(c).SetInt64(3)
+	if a.Trace().Int64() != 1 || a.Norm().Int64() != 4 {
+		t.Errorf("Tr(3) = %v, N(3) = %v, want 1, 4", a.Trace(), a.Norm())
+	}
+}
+
+func mustPow(t *testing.T, x *Elem, e int64) *Elem {
+	t.Helper()
+	z, ok := NewElem(x.Ctx()).Pow(x, fmpz.NewInt(e))
+	if !ok {
+		t.Fatalf("%v^%d failed", x, e)
+	}
+	return z
+}
+
+func TestSqrt(t *testing.T) {
+	c := NewCtxUint64(5, 2, "a")
+	g := c.Primitive(nil)
+	x := NewElem(c).Mul(g, g)
+	if !x.IsSquare() {
+		t.Errorf("IsSquare(%v) = false", x)
+	}
+	if s, ok := NewElem(c).Sqrt(x); !ok || !NewElem(c).Mul(s, s).Equal(x) {
+		t.Errorf("Sqrt(%v) = %v, %v", x, s, ok)
+	}
+	// A primitive element of a field of odd order is not a square.
+	if z, ok := NewElem(c).Sqrt(g); ok || !z.IsZero() || g.IsSquare() {
+		t.Errorf("Sqrt(%v) = %v, %v, want 0 (unchanged), false", g, z, ok)
+	}
+}
+
+func TestPrimitive(t *testing.T) {
+	for _, c := range []*Ctx{NewCtxUint64(2, 8, "a"), NewCtxUint64(5, 2, "a"), NewCtxUint64(101, 3, "a")} {
+		q1 := fmpz.NewInt(0).SubInt64(c.Order(), 1)
+		for _, rnd := range []*rand.Rand{nil, rand.New(rand.NewSource(42))} {
+			g := c.Primitive(rnd)
+			if !g.IsPrimitive() || g.Order().Cmp(q1) != 0 {
+				t.Errorf("Primitive in GF(%v) = %v of order %v", c.Order(), g, g.Order())
+			}
+		}
+	}
+	c := NewCtxUint64(101, 3, "a")
+	if z := NewElem(c).Order(); z.Sign() != 0 {
+		t.Errorf("Order(0) = %v, want 0", z)
+	}
+	z := NewElem(c).Rand(rand.New(rand.NewSource(1)))
+	for _, a := range z.Coeffs() {
+		if a.Sign() < 0 || a.Cmp(fmpz.NewInt(101)) >= 0 {
+			t.Errorf("Rand gave coefficient %v outside [0, 101)", a)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/frithjof-schulze/go.flint
+
+go 1.21
@@ -0,0 +1,303 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+// Package groebner computes Gröbner bases of ideals of multivariate
+// polynomials over Q (fmpq.MPoly) and Z/pZ (nmod.MPoly).
+//
+// The monomial ordering is that of the polynomials' context, so
+// lex, grlex and grevlex bases are obtained by choosing fmpz.Lex,
+// fmpz.DegLex or fmpz.DegRevLex when creating the context.
+package groebner
+
+import (
+	"sort"
+
+	"github.com/frithjof-schulze/go.flint/fmpq"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+	"github.com/frithjof-schulze/go.flint/nmod"
+)
+
+// Poly is the set of operations on multivariate polynomials over a
+// field that Buchberger's algorithm needs.  It is implemented by
+// *fmpq.MPoly and *nmod.MPoly (for prime moduli).
+type Poly[P any] interface {
+	Copy() P
+	IsZero() bool
+	Equal(x P) bool
+	LeadExp() []uint64
+	Degree(i int) int
+	Sub(x, y P) P
+	Mul(x, y P) P
+	MakeMonic(x P) P
+	SetMonomial(e []uint64) P
+	Reduce(x P, gs []P) P
+}
+
+// Basis returns the reduced Gröbner basis of the ideal generated by
+// gens, using Buchberger's algorithm with the coprime and chain
+// criteria.  The elements are monic and sorted by leading exponent
+// vector.  The basis of the zero ideal is empty.
+func Basis[P Poly[P]](gens []P) []P {
+	var g []P
+	for _, f := range gens {
+		if !f.IsZero() {
+			g = append(g, f.Copy().MakeMonic(f))
+		}
+	}
+	if len(g) == 0 {
+		return nil
+	}
+	lead := make([][]uint64, len(g))
+	for i, f := range g {
+		lead[i] = f.LeadExp()
+	}
+
+	type pair struct {
+		i, j int
+		lcm  []uint64
+	}
+	var pairs []pair
+	done := make(map[[2]int]bool)
+	addPairs := func(j int) {
+		for i := 0; i < j; i++ {
+			pairs = append(pairs, pair{i, j, lcm(lead[i], lead[j])})
+		}
+	}
+	for j := range g {
+		addPairs(j)
+	}
+
+	for len(pairs) > 0 {
+		// Normal selection strategy: smallest lcm degree first.
+		k := 0
+		for i := range pairs {
+			if degree(pairs[i].lcm) < degree(pairs[k].lcm) {
+				k = i
+			}
+		}
+		p := pairs[k]
+		pairs = append(pairs[:k], pairs[k+1:]...)
+		done[[2]int{p.i, p.j}] = true
+
+		if coprime(lead[p.i], lead[p.j]) || chain(p.i, p.j, p.lcm, lead, done) {
+			continue
+		}
+		s := spoly(g[p.i], g[p.j], lead[p.i], lead[p.j], p.lcm)
+		s.Reduce(s, g)
+		if s.IsZero() {
+			continue
+		}
+		s.MakeMonic(s)
+		g = append(g, s)
+		lead = append(lead, s.LeadExp())
+		addPairs(len(g) - 1)
+	}
+	return reduce(g)
+}
+
+// NormalForm returns the normal form of f with respect to the
+// Gröbner basis g: the unique remainder of f modulo the ideal
+// that no leading monomial of g divides.
+func NormalForm[P Poly[P]](f P, g []P) P {
+	return f.Copy().Reduce(f, g)
+}
+
+// Contains reports whether f lies in the ideal with Gröbner basis g.
+func Contains[P Poly[P]](g []P, f P) bool {
+	return NormalForm(f, g).IsZero()
+}
+
+// Eliminate returns the reduced Gröbner basis of the elimination
+// ideal obtained by intersecting the ideal generated by gens with
+// the ring in all but the first k variables.  The context of gens
+// must use the lexicographic ordering (fmpz.Lex); otherwise
+// Eliminate panics.
+func Eliminate[P Poly[P]](gens []P, k int) []P {
+	for _, f := range gens {
+		if o, ok := ordering(f); ok && o != fmpz.Lex {
+			panic("groebner: Eliminate requires the lex ordering, not " + o.String())
+		}
+	}
+	var r []P
+	for _, f := range Basis(gens) {
+		free := true
+		for i := 0; i < k; i++ {
+			if f.Degree(i) > 0 {
+				free = false
+				break
+			}
+		}
+		if free {
+			r = append(r, f)
+		}
+	}
+	return r
+}
+
+// ordering returns the monomial ordering of the context of f if f
+// is an *fmpq.MPoly or an *nmod.MPoly.
+func ordering(f any) (fmpz.Ordering, bool) {
+	switch f := f.(type) {
+	case *fmpq.MPoly:
+		return f.Ctx().Ordering(), true
+	case *nmod.MPoly:
+		return f.Ctx().Ordering(), true
+	}
+	return 0, false
+}
+
+// IsBasis reports whether g is a Gröbner basis of the ideal it
+// generates, that is whether every S-polynomial reduces to zero.
+func IsBasis[P Poly[P]](g []P) bool {
+	lead := make([][]uint64, len(g))
+	for i, f := range g {
+		if f.IsZero() {
+			return false
+		}
+		lead[i] = f.LeadExp()
+	}
+	for j := range g {
+		for i := 0; i < j; i++ {
+			if coprime(lead[i], lead[j]) {
+				continue
+			}
+			m := lcm(lead[i], lead[j])
+			s := spoly(g[i], g[j], lead[i], lead[j], m)
+			if !s.Reduce(s, g).IsZero() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// spoly returns the S-polynomial of the monic polynomials f and g
+// with leading exponents a and b and m = lcm(a, b).
+func spoly[P Poly[P]](f, g P, a, b, m []uint64) P {
+	u := f.Copy().SetMonomial(sub(m, a))
+	v := g.Copy().SetMonomial(sub(m, b))
+	u.Mul(u, f)
+	v.Mul(v, g)
+	return u.Sub(u, v)
+}
+
+// chain reports whether the pair (i, j) may be skipped by Buchberger's
+// chain criterion: some other leading monomial divides m and the
+// pairs it forms with i and j have already been treated.
+func chain(i, j int, m []uint64, lead [][]uint64, done map[[2]int]bool) bool {
+	key := func(a, b int) [2]int {
+		if a > b {
+			a, b = b, a
+		}
+		return [2]int{a, b}
+	}
+	for k := range lead {
+		if k == i || k == j || !divides(lead[k], m) {
+			continue
+		}
+		if done[key(i, k)] && done[key(j, k)] {
+			return true
+		}
+	}
+	return false
+}
+
+// reduce turns a Gröbner basis into the reduced Gröbner basis.
+func reduce[P Poly[P]](g []P) []P {
+	// Drop elements whose leading monomial is divisible by that of
+	// another element.
+	var m []P
+	for i, f := range g {
+		a := f.LeadExp()
+		keep := true
+		for j, h := range g {
+			b := h.LeadExp()
+			if j != i && divides(b, a) && (!equal(a, b) || j < i) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			m = append(m, f)
+		}
+	}
+
+	r := make([]P, len(m))
+	others := make([]P, 0, len(m))
+	for i, f := range m {
+		others = append(others[:0], m[:i]...)
+		others = append(others, m[i+1:]...)
+		r[i] = f.Copy().Reduce(f, others)
+		r[i].MakeMonic(r[i])
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return less(r[j].LeadExp(), r[i].LeadExp())
+	})
+	return r
+}
+
+func lcm(a, b []uint64) []uint64 {
+	m := make([]uint64, len(a))
+	for i := range a {
+		m[i] = max(a[i], b[i])
+	}
+	return m
+}
+
+func sub(a, b []uint64) []uint64 {
+	d := make([]uint64, len(a))
+	for i := range a {
+		d[i] = a[i] - b[i]
+	}
+	return d
+}
+
+func degree(a []uint64) uint64 {
+	var d uint64
+	for _, e := range a {
+		d += e
+	}
+	return d
+}
+
+// divides reports whether the monomial a divides b.
+func divides(a, b []uint64) bool {
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func coprime(a, b []uint64) bool {
+	for i := range a {
+		if a[i] != 0 && b[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func equal(a, b []uint64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// less orders exponent vectors lexicographically.  It only serves
+// to make the order of basis elements deterministic.
+func less(a, b []uint64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package groebner
+
+import (
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/fmpq"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+	"github.com/frithjof-schulze/go.flint/nmod"
+)
+
+func qpolys(t *testing.T, c *fmpq.MPolyCtx, ss ...string) []*fmpq.MPoly {
+	t.Helper()
+	fs := make([]*fmpq.MPoly, len(ss))
+	for i, s := range ss {
+		var ok bool
+		if fs[i], ok = fmpq.NewMPoly(c).SetString(s); !ok {
+			t.Fatalf("SetString(%q) failed", s)
+		}
+	}
+	return fs
+}
+
+func npolys(t *testing.T, c *nmod.MPolyCtx, ss ...string) []*nmod.MPoly {
+	t.Helper()
+	fs := make([]*nmod.MPoly, len(ss))
+	for i, s := range ss {
+		var ok bool
+		if fs[i], ok = nmod.NewMPoly(c).SetString(s); !ok {
+			t.Fatalf("SetString(%q) failed", s)
+		}
+	}
+	return fs
+}
+
+// equalBases reports whether g and h hold equal polynomials in the
+// same order.
+func equalBases[P Poly[P]](g, h []P) bool {
+	if len(g) != len(h) {
+		return false
+	}
+	for i := range g {
+		if !g[i].Equal(h[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBasis(t *testing.T) {
+	c := fmpq.NewMPolyCtx([]string{"x", "y"}, fmpz.Lex)
+	gens := qpolys(t, c, "x^2 + y^2 - 1", "x - y")
+	g := Basis(gens)
+	if want := qpolys(t, c, "x - y", "y^2 - 1/2"); !equalBases(g, want) {
+		t.Errorf("Basis = %v, want %v", g, want)
+	}
+	if !IsBasis(g) || IsBasis(gens) {
+		t.Errorf("IsBasis(%v) = %v, IsBasis(%v) = %v", g, IsBasis(g), gens, IsBasis(gens))
+	}
+	for _, tc := range []struct {
+		f, nf string
+	}{
+		{"x^2", "1/2"},
+		{"x*y + x", "y + 1/2"},
+		{"x^2 + y^2 - 1", "0"},
+	} {
+		f := qpolys(t, c, tc.f, tc.nf)
+		if nf := NormalForm(f[0], g); !nf.Equal(f[1]) {
+			t.Errorf("NormalForm(%s) = %v, want %s", tc.f, nf, tc.nf)
+		}
+		if got, want := Contains(g, f[0]), tc.nf == "0"; got != want {
+			t.Errorf("Contains(%s) = %v, want %v", tc.f, got, want)
+		}
+	}
+	if g := Basis(qpolys(t, c, "0")); g != nil {
+		t.Errorf("Basis of the zero ideal = %v, want nil", g)
+	}
+	if g := Basis(qpolys(t, c, "x - y", "2")); !equalBases(g, qpolys(t, c, "1")) {
+		t.Errorf("Basis of the unit ideal = %v, want [1]", g)
+	}
+}
+
+func TestBasisNmod(t *testing.T) {
+	c := nmod.NewMPolyCtx([]string{"x", "y"}, fmpz.Lex, 7)
+	g := Basis(npolys(t, c, "x^2 + y^2 - 1", "x - y"))
+	if want := npolys(t, c, "x + 6*y", "y^2 + 3"); !equalBases(g, want) {
+		t.Errorf("Basis = %v, want %v", g, want)
+	}
+}
+
+func TestEliminate(t *testing.T) {
+	c := fmpq.NewMPolyCtx([]string{"t", "x", "y"}, fmpz.Lex)
+	// The parametrisation x = t^2, y = t^3 of the cusp y^2 = x^3.
+	gens := qpolys(t, c, "x - t^2", "y - t^3")
+	if g, want := Eliminate(gens, 1), qpolys(t, c, "x^3 - y^2"); !equalBases(g, want) {
+		t.Errorf("Eliminate = %v, want %v", g, want)
+	}
+
+	d := fmpq.NewMPolyCtx([]string{"t", "x", "y"}, fmpz.DegRevLex)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Eliminate with the degrevlex ordering did not panic")
+		}
+	}()
+	Eliminate(qpolys(t, d, "x - t^2", "y - t^3"), 1)
+}
+
+func TestBasisModular(t *testing.T) {
+	for _, ord := range []fmpz.Ordering{fmpz.Lex, fmpz.DegRevLex} {
+		c := fmpq.NewMPolyCtx([]string{"x", "y", "z"}, ord)
+		gens := qpolys(t, c,
+			"3*x^2 + 2/5*y*z - 7",
+			"x*y - 11/3*z^2 + 1",
+			"y^2 - 13*x*z + 1/2")
+		g, ok := BasisModular(gens, 1<<16)
+		if want := Basis(gens); !ok || !equalBases(g, want) {
+			t.Errorf("%v: BasisModular = %v, %v, want %v", ord, g, ok, want)
+		}
+		// One prime cannot confirm a reconstruction.
+		if g, ok := BasisModular(gens, 0); ok || g != nil {
+			t.Errorf("%v: BasisModular beyond its limit = %v, %v", ord, g, ok)
+		}
+	}
+	if g, ok := BasisModular([]*fmpq.MPoly{}, 0); !ok || g != nil {
+		t.Errorf("BasisModular of no generators = %v, %v, want nil, true", g, ok)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package groebner
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/frithjof-schulze/go.flint/extras"
+	"github.com/frithjof-schulze/go.flint/fmpq"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+	"github.com/frithjof-schulze/go.flint/nmod"
+)
+
+// BasisModular returns the reduced Gröbner basis of the ideal of
+// Q[x_1, ..., x_n] generated by gens, like Basis, and true, but
+// avoids the coefficient swell of computing over Q: it computes
+// bases modulo a sequence of word-size primes, lifts them with the
+// Chinese remainder theorem and rational reconstruction until the
+// lifted basis stabilises, and returns it once it has been checked
+// over Q to be a Gröbner basis containing the generators.
+//
+// The modular bases are grouped by their shape, the exponent vectors
+// of their terms, and each group is lifted on its own.  Only the
+// shape seen for the most primes is reconstructed, so an unlucky
+// prime, whose basis has a different shape, is outvoted instead of
+// discarding the primes lifted before it.  As with all modular
+// methods, the result is not proven to generate no more than the
+// ideal of gens.
+//
+// maxBits bounds the work: once the primes tried, bad and unlucky
+// ones included, have more than maxBits bits in total, BasisModular
+// gives up and returns (nil, false).  If all of gens are zero, it
+// returns (nil, true).
+func BasisModular(gens []*fmpq.MPoly, maxBits int) ([]*fmpq.MPoly, bool) {
+	var fs []*fmpq.MPoly
+	for _, f := range gens {
+		if !f.IsZero() {
+			fs = append(fs, f)
+		}
+	}
+	if len(fs) == 0 {
+		return nil, true
+	}
+	ctx := fs[0].Ctx()
+
+	var (
+		lifts = make(map[string]*lift)
+		best  *lift
+		used  int // bits of the primes tried
+	)
+	p := uint64(1) << 62
+	for used <= maxBits {
+		p = extras.NextPrime(p, false)
+		used += bits.Len64(p)
+		img, ok := reduceMod(fs, ctx, p)
+		if !ok {
+			continue
+		}
+		sh, es, cs := split(Basis(img))
+		l := lifts[sh]
+		if l == nil {
+			l = newLift(es, cs)
+			lifts[sh] = l
+		}
+		l.add(cs, p)
+		if best == nil || l.primes > best.primes {
+			best = l
+		}
+		if l != best {
+			continue
+		}
+
+		cand, ok := reconstruct(ctx, l.exps, l.res, l.m)
+		if !ok {
+			continue
+		}
+		s := fmt.Sprint(cand)
+		if s == l.prev && verify(cand, fs) {
+			return cand, true
+		}
+		l.prev = s
+	}
+	return nil, false
+}
+
+// A lift holds the coefficients, lifted modulo m, of the modular
+// bases of one shape.
+type lift struct {
+	exps   [][][]uint64  // exponent vectors of the basis
+	res    [][]*fmpz.Int // lifted coefficients
+	m      *fmpz.Int     // product of the primes
+	primes int           // number of the primes
+	prev   string        // the last reconstruction
+}
+
+// newLift returns an empty lift for bases with exponent vectors es
+// and coefficients shaped like cs.
+func newLift(es [][][]uint64, cs [][]uint64) *lift {
+	l := &lift{exps: es, res: make([][]*fmpz.Int, len(cs)), m: fmpz.NewInt(1)}
+	for i := range cs {
+		l.res[i] = make([]*fmpz.Int, len(cs[i]))
+		for j := range cs[i] {
+			l.res[i][j] = fmpz.NewInt(0)
+		}
+	}
+	return l
+}
+
+// add lifts the coefficients cs of a basis modulo p into l.
+func (l *lift) add(cs [][]uint64, p uint64) {
+	q, t := fmpz.NewInt(0).SetUint64(p), fmpz.NewInt(0)
+	for i := range cs {
+		for j, c := range cs[i] {
+			l.res[i][j].CRT(l.res[i][j], l.m, t.SetUint64(c), q, false)
+		}
+	}
+	l.m.Mul(l.m, q)
+	l.primes++
+}
+
+// reduceMod returns the images of fs in Z/pZ[x_1, ..., x_n], or
+// false if p divides a denominator.
+func reduceMod(fs []*fmpq.MPoly, ctx *fmpq.MPolyCtx, p uint64) ([]*nmod.MPoly, bool) {
+	nctx := nmod.NewMPolyCtx(ctx.Vars(), ctx.Ordering(), p)
+	q := fmpz.NewInt(0).SetUint64(p)
+	num, den := fmpz.NewInt(0), fmpz.NewInt(0)
+	img := make([]*nmod.MPoly, len(fs))
+	for i, f := range fs {
+		n := f.Len()
+		cs := make([]uint64, n)
+		es := make([][]uint64, n)
+		for j := 0; j < n; j++ {
+			c, e := f.Term(j)
+			c.Num(num)
+			c.Denom(den)
+			if den.FRemUint64(p) == 0 {
+				return nil, false
+			}
+			den.Exp(den, fmpz.NewInt(-1), q)
+			cs[j] = num.Mul(num, den).FRemUint64(p)
+			es[j] = e
+		}
+		img[i] = nmod.NewMPoly(nctx).SetTerms(cs, es)
+	}
+	return img, true
+}
+
+// split returns a description of the supports of the polynomials
+// in g, their exponent vectors and their coefficients.
+func split(g []*nmod.MPoly) (string, [][][]uint64, [][]uint64) {
+	es := make([][][]uint64, len(g))
+	cs := make([][]uint64, len(g))
+	for i, f := range g {
+		n := f.Len()
+		es[i] = make([][]uint64, n)
+		cs[i] = make([]uint64, n)
+		for j := 0; j < n; j++ {
+			cs[i][j], es[i][j] = f.Term(j)
+		}
+	}
+	return fmt.Sprint(es), es, cs
+}
+
+// reconstruct returns the polynomials over Q whose coefficients are
+// the rational reconstructions of res modulo m.
+func reconstruct(ctx *fmpq.MPolyCtx, exps [][][]uint64, res [][]*fmpz.Int, m *fmpz.Int) ([]*fmpq.MPoly, bool) {
+	g := make([]*fmpq.MPoly, len(res))
+	for i := range res {
+		cs := make([]*fmpq.Rat, len(res[i]))
+		for j, r := range res[i] {
+			var ok bool
+			if cs[j], ok = fmpq.NewRat(0, 1).Reconstruct(r, m); !ok {
+				return nil, false
+			}
+		}
+		g[i] = fmpq.NewMPoly(ctx).SetTerms(cs, exps[i])
+	}
+	return g, true
+}
+
+// verify reports whether g is a Gröbner basis containing every
+// polynomial of fs.
+func verify(g, fs []*fmpq.MPoly) bool {
+	for _, f := range fs {
+		if !Contains(g, f) {
+			return false
+		}
+	}
+	return IsBasis(g)
+}
@@ -0,0 +1,44 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+// Package testutil holds helpers shared by the tests of the go.flint
+// packages.  It must only be imported from tests.
+package testutil
+
+import (
+	"strings"
+	"testing"
+)
+
+// Must returns a function that returns z, or fails the test if ok is
+// false.  It wraps the (z, ok) results of partial operations:
+//
+//	p := testutil.Must[*MPoly](t)(NewMPoly(c).SetString("x + y"))
+func Must[T any](t testing.TB) func(z T, ok bool) T {
+	return func(z T, ok bool) T {
+		t.Helper()
+		if !ok {
+			t.Fatalf("operation failed")
+		}
+		return z
+	}
+}
+
+// Misuse calls f and reports whether it panics the way the go.flint
+// packages report misuse: with a string naming package pkg, such as
+// "fmpz: MPoly term index out of range".  Like flint.Try, it
+// propagates any other panic, including an *flint.Error.
+func Misuse(pkg string, f func()) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg, isString := r.(string)
+			if !isString {
+				panic(r)
+			}
+			ok = strings.HasPrefix(msg, pkg+": ")
+		}
+	}()
+	f()
+	return false
+}
@@ -0,0 +1,42 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package testutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+)
+
+func TestMust(t *testing.T) {
+	if z := Must[int](t)(7, true); z != 7 {
+		t.Errorf("Must(7, true) = %d, want 7", z)
+	}
+}
+
+func TestMisuse(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		f    func()
+		want bool
+	}{
+		{"no panic", func() {}, false},
+		{"misuse", func() { panic("fmpz: MPoly term index out of range") }, true},
+		{"other package", func() { panic("fmpq: slice length mismatch") }, false},
+	} {
+		if got := Misuse("fmpz", tc.f); got != tc.want {
+			t.Errorf("Misuse(%s) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	// An *flint.Error is not misuse and is passed on.
+	err := flint.Try(func() {
+		Misuse("fmpz", func() { flint.Panic("fmpz.Int.Div", flint.ErrDivisionByZero) })
+	})
+	if !errors.Is(err, flint.ErrDivisionByZero) {
+		t.Errorf("Misuse recovered a *flint.Error: err = %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo
+
+package util
+
+// #include <stdlib.h>
+import "C"
+
+import "unsafe"
+
+// C types are private to the package that imports "C", so the
+// functions below deal in unsafe.Pointer; callers convert to their
+// own C types.
+
+// CStrings returns a C array of C copies of s, terminated by nil,
+// as a char **.  It must be released with FreeCStrings.
+func CStrings(s []string) unsafe.Pointer {
+	p := C.malloc(C.size_t(len(s)+1) * C.size_t(unsafe.Sizeof((*C.char)(nil))))
+	a := unsafe.Slice((**C.char)(p), len(s)+1)
+	for i, v := range s {
+		a[i] = C.CString(v)
+	}
+	a[len(s)] = nil
+	return p
+}
+
+// FreeCStrings releases an array of n strings returned by CStrings.
+func FreeCStrings(p unsafe.Pointer, n int) {
+	for _, v := range unsafe.Slice((**C.char)(p), n) {
+		C.free(unsafe.Pointer(v))
+	}
+	C.free(p)
+}
+
+// PointerArray allocates C memory for n values of the given size and
+// for an array of n pointers to them, and returns both.  FLINT
+// functions that take arrays of pointers to their operands cannot be
+// passed Go memory holding Go pointers, so callers copy the operands
+// into the values instead.  Both must be released with Free.
+func PointerArray(n int, size uintptr) (vals, ptrs unsafe.Pointer) {
+	vals = C.malloc(C.size_t(n) * C.size_t(size))
+	ptrs = C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(vals)))
+	ps := unsafe.Slice((*unsafe.Pointer)(ptrs), n)
+	for i := range ps {
+		ps[i] = unsafe.Add(vals, uintptr(i)*size)
+	}
+	return vals, ptrs
+}
+
+// Free releases C memory allocated by PointerArray.
+func Free(ps ...unsafe.Pointer) {
+	for _, p := range ps {
+		C.free(p)
+	}
+}
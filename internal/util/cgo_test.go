@@ -0,0 +1,52 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo
+
+package util
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestCStrings(t *testing.T) {
+	s := []string{"x", "y1", ""}
+	p := CStrings(s)
+	defer FreeCStrings(p, len(s))
+	a := unsafe.Slice((**byte)(p), len(s)+1)
+	for i, v := range s {
+		if got := cString(a[i]); got != v {
+			t.Errorf("string %d = %q, want %q", i, got, v)
+		}
+	}
+	if a[len(s)] != nil {
+		t.Errorf("array is not terminated by nil")
+	}
+}
+
+// cString returns the Go copy of the C string at p.
+func cString(p *byte) string {
+	n := 0
+	for *(*byte)(unsafe.Add(unsafe.Pointer(p), n)) != 0 {
+		n++
+	}
+	return string(unsafe.Slice(p, n))
+}
+
+func TestPointerArray(t *testing.T) {
+	const n = 5
+	vals, ptrs := PointerArray(n, unsafe.Sizeof(int64(0)))
+	defer Free(vals, ptrs)
+	vs := unsafe.Slice((*int64)(vals), n)
+	ps := unsafe.Slice((**int64)(ptrs), n)
+	for i := range vs {
+		vs[i] = int64(i * i)
+	}
+	for i, p := range ps {
+		if p != &vs[i] || *p != int64(i*i) {
+			t.Errorf("pointer %d does not point to value %d", i, i)
+		}
+	}
+}
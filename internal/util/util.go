@@ -0,0 +1,26 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+// Package util holds helpers shared by the go.flint packages that
+// are not part of their API.
+package util
+
+// CheckLen panics unless all of ms equal n.  pkg names the calling
+// package in the panic message.
+func CheckLen(pkg string, n int, ms ...int) {
+	for _, m := range ms {
+		if m != n {
+			panic(pkg + ": slice length mismatch")
+		}
+	}
+}
+
+// CheckVar panics unless 0 <= i < n, where n is the number of
+// variables of the context of an MPoly.  pkg names the calling
+// package in the panic message.
+func CheckVar(pkg string, i, n int) {
+	if i < 0 || i >= n {
+		panic(pkg + ": MPoly variable index out of range")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+package util
+
+import "testing"
+
+func panics(f func()) (msg any) {
+	defer func() { msg = recover() }()
+	f()
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		f    func()
+		want any
+	}{
+		{"CheckLen ok", func() { CheckLen("fmpz", 2, 2, 2) }, nil},
+		{"CheckLen none", func() { CheckLen("fmpz", 2) }, nil},
+		{"CheckLen", func() { CheckLen("fmpq", 2, 2, 3) }, "fmpq: slice length mismatch"},
+		{"CheckVar ok", func() { CheckVar("nmod", 0, 1) }, nil},
+		{"CheckVar negative", func() { CheckVar("nmod", -1, 3) }, "nmod: MPoly variable index out of range"},
+		{"CheckVar too large", func() { CheckVar("fmpz", 3, 3) }, "fmpz: MPoly variable index out of range"},
+	} {
+		if got := panics(tc.f); got != tc.want {
+			t.Errorf("%s: panic %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package nmod
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/ulong_extras.h>
+// #include <flint/nmod_poly.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// BerlekampMassey returns the coefficients c_0, ..., c_L, with c_L
+// = 1, of the minimal polynomial of the sequence xs over Z/nZ for
+// a prime n: the monic polynomial of least degree such that
+//
+//	c_0*xs[j] + c_1*xs[j+1] + ... + c_L*xs[j+L] = 0 (mod n)
+//
+// for 0 <= j < len(xs)-L.  The recurrence is only determined by
+// the data if 2*L < len(xs).
+func BerlekampMassey(xs []uint64, n uint64) []uint64 {
+	if n < 2 {
+		panic("nmod: BerlekampMassey modulus must be prime")
+	}
+	var b C.nmod_berlekamp_massey_struct
+	C.nmod_berlekamp_massey_init(&b, C.mp_limb_t(n))
+	defer C.nmod_berlekamp_massey_clear(&b)
+
+	if len(xs) > 0 {
+		ys := make([]C.mp_limb_t, len(xs))
+		for i, x := range xs {
+			ys[i] = C.mp_limb_t(x % n)
+		}
+		C.nmod_berlekamp_massey_add_points(&b, &ys[0], C.slong(len(ys)))
+	}
+	C.nmod_berlekamp_massey_reduce(&b)
+
+	v := C.nmod_berlekamp_massey_V_poly(&b)
+	if v.length == 0 {
+		return []uint64{1}
+	}
+	vs := unsafe.Slice(v.coeffs, v.length)
+	inv := C.n_invmod(vs[len(vs)-1], C.mp_limb_t(n))
+	ninv := C.n_preinvert_limb(C.mp_limb_t(n))
+	cs := make([]uint64, len(vs))
+	for i, c := range vs {
+		cs[i] = uint64(C.n_mulmod2_preinv(c, inv, C.mp_limb_t(n), ninv))
+	}
+	return cs
+}
@@ -0,0 +1,38 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package nmod
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBerlekampMassey(t *testing.T) {
+	for _, tc := range []struct {
+		xs   []uint64
+		n    uint64
+		want []uint64
+	}{
+		// x_(j+2) = x_(j+1) + x_j.
+		{[]uint64{1, 1, 2, 3, 5, 8, 13, 21}, 101, []uint64{100, 100, 1}},
+		// x_(j+1) = 2*x_j, with reduction of the inputs.
+		{[]uint64{1, 2, 11, 8, 16, 32}, 7, []uint64{5, 1}},
+		{[]uint64{0, 0, 0, 0}, 5, []uint64{1}},
+		{nil, 3, []uint64{1}},
+	} {
+		got := BerlekampMassey(tc.xs, tc.n)
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("BerlekampMassey(%v, %d) = %v, want %v", tc.xs, tc.n, got, tc.want)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("BerlekampMassey with modulus 1 did not panic")
+		}
+	}()
+	BerlekampMassey([]uint64{1}, 1)
+}
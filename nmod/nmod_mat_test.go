@@ -1 +1,3 @@
+//go:build cgo && !noflint
+
 package nmod
\ No newline at end of file
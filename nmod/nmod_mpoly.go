@@ -0,0 +1,459 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package nmod
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/ulong_extras.h>
+// #include <flint/mpoly.h>
+// #include <flint/nmod_mpoly.h>
+// #include <flint/nmod_mpoly_factor.h>
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+	"github.com/frithjof-schulze/go.flint/internal/util"
+)
+
+// An MPolyCtx describes a ring Z/nZ[x_1, ..., x_n] of multivariate
+// polynomials with named variables and a monomial ordering, for a
+// word-size modulus n.
+type MPolyCtx struct {
+	c    C.nmod_mpoly_ctx_struct
+	vars []string
+	cv   **C.char
+}
+
+// NewMPolyCtx returns the polynomial ring over Z/nZ in the given
+// variables with monomial ordering ord.  GCD and Factor require n
+// to be prime.
+func NewMPolyCtx(vars []string, ord fmpz.Ordering, n uint64) *MPolyCtx {
+	if len(vars) == 0 {
+		panic("nmod: MPolyCtx needs at least one variable")
+	}
+	if n == 0 {
+		panic("nmod: modulus must be positive")
+	}
+	c := &MPolyCtx{vars: append([]string(nil), vars...)}
+	c.cv = (**C.char)(util.CStrings(vars))
+	C.nmod_mpoly_ctx_init(&c.c, C.slong(len(vars)), C.ordering_t(ord), C.mp_limb_t(n))
+	runtime.SetFinalizer(c, (*MPolyCtx).destroy)
+	return c
+}
+
+func (c *MPolyCtx) destroy() {
+	C.nmod_mpoly_ctx_clear(&c.c)
+	util.FreeCStrings(unsafe.Pointer(c.cv), len(c.vars))
+}
+
+// Vars returns the names of the variables of c.
+func (c *MPolyCtx) Vars() []string {
+	return append([]string(nil), c.vars...)
+}
+
+// NVars returns the number of variables of c.
+func (c *MPolyCtx) NVars() int {
+	return len(c.vars)
+}
+
+// Modulus returns the modulus n of c.
+func (c *MPolyCtx) Modulus() uint64 {
+	return uint64(C.nmod_mpoly_ctx_modulus(&c.c))
+}
+
+// Ordering returns the monomial ordering of c.
+func (c *MPolyCtx) Ordering() fmpz.Ordering {
+	return fmpz.Ordering(C.nmod_mpoly_ctx_ord(&c.c))
+}
+
+// An MPoly represents a multivariate polynomial with coefficients
+// in Z/nZ.  Its terms are kept sorted in decreasing order
+// with respect to the monomial ordering of its context.
+type MPoly struct {
+	p C.nmod_mpoly_struct
+	c *MPolyCtx
+}
+
+// NewMPoly returns the zero polynomial in c.
+func NewMPoly(c *MPolyCtx) *MPoly {
+	z := &MPoly{c: c}
+	C.nmod_mpoly_init(&z.p, &c.c)
+	runtime.SetFinalizer(z, (*MPoly).destroy)
+	return z
+}
+
+func (z *MPoly) destroy() {
+	C.nmod_mpoly_clear(&z.p, &z.c.c)
+}
+
+func (z *MPoly) ctx() *C.nmod_mpoly_ctx_struct {
+	return &z.c.c
+}
+
+// same panics unless all of xs belong to the context of z.
+func (z *MPoly) same(xs ...*MPoly) {
+	for _, x := range xs {
+		if x.c != z.c {
+			panic("nmod: MPoly operands belong to different contexts")
+		}
+	}
+}
+
+// Ctx returns the context of z.
+func (z *MPoly) Ctx() *MPolyCtx {
+	return z.c
+}
+
+// SetString sets z to the value of s, a polynomial expression in
+// the variables of the context such as "2*x^2*y - 3*(y + 1)^2",
+// and returns (z, true).  If s cannot be parsed, SetString returns
+// (nil, false).
+func (z *MPoly) SetString(s string) (*MPoly, bool) {
+	p := C.CString(s)
+	defer C.free(unsafe.Pointer(p))
+	if C.nmod_mpoly_set_str_pretty(&z.p, p, z.c.cv, z.ctx()) != 0 {
+		return nil, false
+	}
+	return z, true
+}
+
+// String returns a string representation of z in the variables
+// of its context.
+func (z *MPoly) String() string {
+	p := C.nmod_mpoly_get_str_pretty(&z.p, z.c.cv, z.ctx())
+	defer C.free(unsafe.Pointer(p))
+	return C.GoString(p)
+}
+
+// Set sets z = x and returns z.
+func (z *MPoly) Set(x *MPoly) *MPoly {
+	z.same(x)
+	C.nmod_mpoly_set(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// SetUint64 sets z to the constant x mod n and returns z.
+func (z *MPoly) SetUint64(x uint64) *MPoly {
+	C.nmod_mpoly_set_ui(&z.p, C.ulong(x), z.ctx())
+	return z
+}
+
+// SetVar sets z to the i-th variable of its context and returns z.
+func (z *MPoly) SetVar(i int) *MPoly {
+	util.CheckVar("nmod", i, z.c.NVars())
+	C.nmod_mpoly_gen(&z.p, C.slong(i), z.ctx())
+	return z
+}
+
+// SetTerms sets z to the sum of the terms cs[i] * x^es[i], where
+// es[i] holds one exponent per variable, and returns z.
+func (z *MPoly) SetTerms(cs []uint64, es [][]uint64) *MPoly {
+	util.CheckLen("nmod", len(cs), len(es))
+	C.nmod_mpoly_zero(&z.p, z.ctx())
+	exp := make([]C.ulong, z.c.NVars())
+	for i, c := range cs {
+		util.CheckLen("nmod", len(exp), len(es[i]))
+		for j, e := range es[i] {
+			exp[j] = C.ulong(e)
+		}
+		C.nmod_mpoly_push_term_ui_ui(&z.p, C.ulong(c), &exp[0], z.ctx())
+	}
+	C.nmod_mpoly_sort_terms(&z.p, z.ctx())
+	C.nmod_mpoly_combine_like_terms(&z.p, z.ctx())
+	return z
+}
+
+// Len returns the number of terms of z.
+func (z *MPoly) Len() int {
+	return int(C.nmod_mpoly_length(&z.p, z.ctx()))
+}
+
+// Term returns the coefficient and the exponent vector of the i-th
+// term of z, 0 <= i < z.Len().  Term 0 is the leading term.
+func (z *MPoly) Term(i int) (uint64, []uint64) {
+	if i < 0 || i >= z.Len() {
+		panic("nmod: MPoly term index out of range")
+	}
+	c := uint64(C.nmod_mpoly_get_term_coeff_ui(&z.p, C.slong(i), z.ctx()))
+	if C.nmod_mpoly_term_exp_fits_ui(&z.p, C.slong(i), z.ctx()) == 0 {
+		panic("nmod: MPoly exponent does not fit in a word")
+	}
+	exp := make([]C.ulong, z.c.NVars())
+	C.nmod_mpoly_get_term_exp_ui(&exp[0], &z.p, C.slong(i), z.ctx())
+	e := make([]uint64, len(exp))
+	for j, v := range exp {
+		e[j] = uint64(v)
+	}
+	return c, e
+}
+
+// IsZero reports whether z is zero.
+func (z *MPoly) IsZero() bool {
+	return C.nmod_mpoly_is_zero(&z.p, z.ctx()) != 0
+}
+
+// Equal reports whether z and x are equal.
+func (z *MPoly) Equal(x *MPoly) bool {
+	z.same(x)
+	return C.nmod_mpoly_equal(&z.p, &x.p, z.ctx()) != 0
+}
+
+// Degree returns the degree of z in the i-th variable.  The degree
+// of the zero polynomial is -1.
+func (z *MPoly) Degree(i int) int {
+	util.CheckVar("nmod", i, z.c.NVars())
+	return int(C.nmod_mpoly_degree_si(&z.p, C.slong(i), z.ctx()))
+}
+
+// TotalDegree returns the total degree of z.  The total degree of
+// the zero polynomial is -1.
+func (z *MPoly) TotalDegree() int {
+	return int(C.nmod_mpoly_total_degree_si(&z.p, z.ctx()))
+}
+
+// Add sets z = x + y and returns z.
+func (z *MPoly) Add(x, y *MPoly) *MPoly {
+	z.same(x, y)
+	C.nmod_mpoly_add(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *MPoly) Sub(x, y *MPoly) *MPoly {
+	z.same(x, y)
+	C.nmod_mpoly_sub(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *MPoly) Neg(x *MPoly) *MPoly {
+	z.same(x)
+	C.nmod_mpoly_neg(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *MPoly) Mul(x, y *MPoly) *MPoly {
+	z.same(x, y)
+	C.nmod_mpoly_mul(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// ScalarMul sets z = c*x and returns z.
+func (z *MPoly) ScalarMul(x *MPoly, c uint64) *MPoly {
+	z.same(x)
+	C.nmod_mpoly_scalar_mul_ui(&z.p, &x.p, C.ulong(c), z.ctx())
+	return z
+}
+
+// Pow sets z = x^k and returns (z, true).  If the exponents of the
+// result would overflow, z is unchanged and Pow returns (z, false).
+func (z *MPoly) Pow(x *MPoly, k uint64) (*MPoly, bool) {
+	z.same(x)
+	t := NewMPoly(z.c)
+	if C.nmod_mpoly_pow_ui(&t.p, &x.p, C.ulong(k), z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Div sets z = x / y and returns (z, true) if y divides x exactly.
+// Otherwise z is unchanged and Div returns (z, false).  If y is
+// zero, a division-by-zero run-time panic occurs.
+func (z *MPoly) Div(x, y *MPoly) (*MPoly, bool) {
+	z.same(x, y)
+	if y.IsZero() {
+		flint.Panic("nmod.MPoly.Div", flint.ErrDivisionByZero)
+	}
+	t := NewMPoly(z.c)
+	if C.nmod_mpoly_divides(&t.p, &x.p, &y.p, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// DivMod sets z to the quotient and m to the remainder of the
+// multivariate division of x by y with respect to the monomial
+// ordering, and returns (z, m).  If y is zero, a division-by-zero
+// run-time panic occurs.
+func (z *MPoly) DivMod(x, y, m *MPoly) (*MPoly, *MPoly) {
+	z.same(x, y, m)
+	if y.IsZero() {
+		flint.Panic("nmod.MPoly.DivMod", flint.ErrDivisionByZero)
+	}
+	C.nmod_mpoly_divrem(&z.p, &m.p, &x.p, &y.p, z.ctx())
+	return z, m
+}
+
+// GCD sets z to the monic greatest common divisor of x and y and
+// returns (z, true).  If FLINT cannot compute it, z is unchanged
+// and GCD returns (z, false).
+func (z *MPoly) GCD(x, y *MPoly) (*MPoly, bool) {
+	z.same(x, y)
+	t := NewMPoly(z.c)
+	if C.nmod_mpoly_gcd(&t.p, &x.p, &y.p, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Derivative sets z to the partial derivative of x with respect to
+// the i-th variable and returns z.
+func (z *MPoly) Derivative(x *MPoly, i int) *MPoly {
+	z.same(x)
+	util.CheckVar("nmod", i, x.c.NVars())
+	C.nmod_mpoly_derivative(&z.p, &x.p, C.slong(i), z.ctx())
+	return z
+}
+
+// Evaluate returns the value of z at the point vals, which holds
+// one value per variable.
+func (z *MPoly) Evaluate(vals []uint64) uint64 {
+	util.CheckLen("nmod", z.c.NVars(), len(vals))
+	v := make([]C.ulong, len(vals))
+	for i, x := range vals {
+		v[i] = C.ulong(x)
+	}
+	return uint64(C.nmod_mpoly_evaluate_all_ui(&z.p, &v[0], z.ctx()))
+}
+
+// EvaluateOne sets z to x with the i-th variable replaced by val
+// and returns z.
+func (z *MPoly) EvaluateOne(x *MPoly, i int, val uint64) *MPoly {
+	z.same(x)
+	util.CheckVar("nmod", i, x.c.NVars())
+	C.nmod_mpoly_evaluate_one_ui(&z.p, &x.p, C.slong(i), C.ulong(val), z.ctx())
+	return z
+}
+
+// Copy returns a new MPoly equal to z.
+func (z *MPoly) Copy() *MPoly {
+	return NewMPoly(z.c).Set(z)
+}
+
+// LeadExp returns the exponent vector of the leading term of z.
+// It panics if z is zero.
+func (z *MPoly) LeadExp() []uint64 {
+	_, e := z.Term(0)
+	return e
+}
+
+// SetMonomial sets z to the monomial x^e with coefficient 1, where
+// e holds one exponent per variable, and returns z.
+func (z *MPoly) SetMonomial(e []uint64) *MPoly {
+	util.CheckLen("nmod", z.c.NVars(), len(e))
+	exp := make([]C.ulong, len(e))
+	for i, v := range e {
+		exp[i] = C.ulong(v)
+	}
+	C.nmod_mpoly_zero(&z.p, z.ctx())
+	C.nmod_mpoly_push_term_ui_ui(&z.p, 1, &exp[0], z.ctx())
+	return z
+}
+
+// MakeMonic sets z to x divided by its leading coefficient and
+// returns z.  If x is zero, a division-by-zero run-time panic occurs.
+func (z *MPoly) MakeMonic(x *MPoly) *MPoly {
+	z.same(x)
+	if x.IsZero() {
+		flint.Panic("nmod.MPoly.MakeMonic", flint.ErrDivisionByZero)
+	}
+	C.nmod_mpoly_make_monic(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// Reduce sets z to the remainder of the multivariate division of x
+// by gs: x minus a combination of the gs such that no term of z is
+// divisible by the leading monomial of any of the gs.  It returns z.
+// If gs is a Gröbner basis, z is the unique normal form of x.
+func (z *MPoly) Reduce(x *MPoly, gs []*MPoly) *MPoly {
+	z.same(x)
+	z.same(gs...)
+	n := len(gs)
+	if n == 0 {
+		return z.Set(x)
+	}
+
+	// The first n values hold the quotients, the last n the divisors.
+	vals, ptrs := util.PointerArray(2*n, unsafe.Sizeof(C.nmod_mpoly_struct{}))
+	defer util.Free(vals, ptrs)
+	cs := unsafe.Slice((*C.nmod_mpoly_struct)(vals), 2*n)
+	ps := unsafe.Slice((**C.nmod_mpoly_struct)(ptrs), 2*n)
+	for i := range cs {
+		C.nmod_mpoly_init(&cs[i], z.ctx())
+		defer C.nmod_mpoly_clear(&cs[i], z.ctx())
+	}
+	for i, g := range gs {
+		if g.IsZero() {
+			flint.Panic("nmod.MPoly.Reduce", flint.ErrDivisionByZero)
+		}
+		C.nmod_mpoly_set(&cs[n+i], &g.p, z.ctx())
+	}
+
+	r := NewMPoly(z.c)
+	C.nmod_mpoly_divrem_ideal(&ps[0], &r.p, &x.p, &ps[n], C.slong(n), z.ctx())
+	return z.Set(r)
+}
+
+// Compose sets z to x with the i-th variable of x replaced by
+// ys[i] and returns (z, true).  The context of x must have one
+// variable per entry of ys; z and all ys belong to a common,
+// possibly different context.  If the result is too large to
+// compute, z is unchanged and Compose returns (z, false).
+func (z *MPoly) Compose(x *MPoly, ys []*MPoly) (*MPoly, bool) {
+	n := x.c.NVars()
+	util.CheckLen("nmod", n, len(ys))
+	z.same(ys...)
+
+	vals, ptrs := util.PointerArray(n, unsafe.Sizeof(C.nmod_mpoly_struct{}))
+	defer util.Free(vals, ptrs)
+	cs := unsafe.Slice((*C.nmod_mpoly_struct)(vals), n)
+	for i, y := range ys {
+		C.nmod_mpoly_init(&cs[i], z.ctx())
+		defer C.nmod_mpoly_clear(&cs[i], z.ctx())
+		C.nmod_mpoly_set(&cs[i], &y.p, z.ctx())
+	}
+
+	t := NewMPoly(z.c)
+	if C.nmod_mpoly_compose_nmod_mpoly(&t.p, &x.p, (**C.nmod_mpoly_struct)(ptrs), x.ctx(), z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Factor returns the factorisation of z into monic irreducible
+// polynomials: a constant, the factors and their multiplicities.
+// If FLINT cannot factor z, Factor returns ok == false.
+func (z *MPoly) Factor() (c uint64, fs []*MPoly, es []int, ok bool) {
+	var f C.nmod_mpoly_factor_struct
+	C.nmod_mpoly_factor_init(&f, z.ctx())
+	defer C.nmod_mpoly_factor_clear(&f, z.ctx())
+	if C.nmod_mpoly_factor(&f, &z.p, z.ctx()) == 0 {
+		return 0, nil, nil, false
+	}
+
+	c = uint64(f.constant)
+	n := int(f.num)
+	if n == 0 {
+		return c, nil, nil, true
+	}
+	ps := unsafe.Slice(f.poly, n)
+	exps := unsafe.Slice(f.exp, n)
+	fs = make([]*MPoly, n)
+	es = make([]int, n)
+	for i := 0; i < n; i++ {
+		fs[i] = NewMPoly(z.c)
+		C.nmod_mpoly_set(&fs[i].p, &ps[i], z.ctx())
+		es[i] = int(C.fmpz_get_si(&exps[i]))
+	}
+	return c, fs, es, true
+}
@@ -0,0 +1,131 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package nmod
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+	"github.com/frithjof-schulze/go.flint/internal/testutil"
+)
+
+func mpoly(t *testing.T, c *MPolyCtx, s string) *MPoly {
+	t.Helper()
+	return mustMPoly(t)(NewMPoly(c).SetString(s))
+}
+
+var mustMPoly = testutil.Must[*MPoly]
+
+func TestMPolyArith(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, fmpz.Lex, 7)
+	if c.Modulus() != 7 || c.NVars() != 2 || c.Ordering() != fmpz.Lex {
+		t.Errorf("MPolyCtx has modulus %d, variables %v and ordering %v", c.Modulus(), c.Vars(), c.Ordering())
+	}
+	p := mpoly(t, c, "x^2 - y^2")
+	a := mpoly(t, c, "x + y")
+	b := mpoly(t, c, "x + 6*y")
+	for _, tc := range []struct {
+		name      string
+		got, want *MPoly
+	}{
+		{"Mul", NewMPoly(c).Mul(a, b), p},
+		{"Add", NewMPoly(c).Add(a, b), mpoly(t, c, "2*x")},
+		{"Sub", NewMPoly(c).Sub(a, b), mpoly(t, c, "2*y")},
+		{"Neg", NewMPoly(c).Neg(a), mpoly(t, c, "6*x + 6*y")},
+		{"ScalarMul", NewMPoly(c).ScalarMul(a, 10), mpoly(t, c, "3*x + 3*y")},
+		{"SetUint64", NewMPoly(c).SetUint64(9), mpoly(t, c, "2")},
+		{"SetTerms", NewMPoly(c).SetTerms([]uint64{1, 6}, [][]uint64{{2, 0}, {0, 2}}), p},
+		{"SetMonomial", NewMPoly(c).SetMonomial([]uint64{0, 2}), mpoly(t, c, "y^2")},
+		{"MakeMonic", NewMPoly(c).MakeMonic(mpoly(t, c, "2*x + 1")), mpoly(t, c, "x + 4")},
+		{"Derivative", NewMPoly(c).Derivative(p, 0), mpoly(t, c, "2*x")},
+		{"EvaluateOne", NewMPoly(c).EvaluateOne(p, 0, 3), mpoly(t, c, "2 - y^2")},
+		{"Compose", mustMPoly(t)(NewMPoly(c).Compose(p, []*MPoly{mpoly(t, c, "y"), mpoly(t, c, "x")})), NewMPoly(c).Neg(p)},
+		{"Pow", mustMPoly(t)(NewMPoly(c).Pow(a, 7)), mpoly(t, c, "x^7 + y^7")},
+		{"Div", mustMPoly(t)(NewMPoly(c).Div(p, b)), a},
+		{"GCD", mustMPoly(t)(NewMPoly(c).GCD(mpoly(t, c, "3*x^2 - 3*y^2"), mpoly(t, c, "2*x + 2*y"))), a},
+	} {
+		if !tc.got.Equal(tc.want) {
+			t.Errorf("%s = %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+
+	if v := p.Evaluate([]uint64{5, 1}); v != 3 {
+		t.Errorf("p(5, 1) = %d, want 3", v)
+	}
+	if p.Degree(1) != 2 || a.TotalDegree() != 1 || NewMPoly(c).Degree(0) != -1 {
+		t.Errorf("Degree and TotalDegree give wrong results")
+	}
+	if k, e := p.Term(1); k != 6 || e[0] != 0 || e[1] != 2 {
+		t.Errorf("Term(1) = %d, %v, want 6, [0 2]", k, e)
+	}
+	q, r := NewMPoly(c).DivMod(mpoly(t, c, "x^2 + y"), mpoly(t, c, "x + 1"), NewMPoly(c))
+	if !q.Equal(mpoly(t, c, "x + 6")) || !r.Equal(mpoly(t, c, "y + 1")) {
+		t.Errorf("DivMod(x^2 + y, x + 1) = %v, %v, want x + 6, y + 1", q, r)
+	}
+}
+
+func TestMPolyReduce(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, fmpz.Lex, 5)
+	gs := []*MPoly{mpoly(t, c, "x - y"), mpoly(t, c, "y^2 - 2")}
+	for _, tc := range []struct {
+		x, want string
+	}{
+		{"x^2", "2"},
+		{"x*y + 4", "1"},
+		{"x^3 + y", "3*y"},
+	} {
+		if got := NewMPoly(c).Reduce(mpoly(t, c, tc.x), gs); !got.Equal(mpoly(t, c, tc.want)) {
+			t.Errorf("Reduce(%s) = %v, want %s", tc.x, got, tc.want)
+		}
+	}
+}
+
+func TestMPolyFactor(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, fmpz.DegRevLex, 5)
+	p := mpoly(t, c, "3*x^2*y + 3*x*y^2")
+	k, fs, es, ok := p.Factor()
+	if !ok || k != 3 || len(fs) != 3 {
+		t.Fatalf("Factor = %d, %v, %v, %v", k, fs, es, ok)
+	}
+	prod := NewMPoly(c).SetUint64(k)
+	for i := range fs {
+		prod.Mul(prod, mustMPoly(t)(NewMPoly(c).Pow(fs[i], uint64(es[i]))))
+	}
+	if !prod.Equal(p) {
+		t.Errorf("product of the factors = %v, want %v", prod, p)
+	}
+}
+
+func TestMPolyErrors(t *testing.T) {
+	c := NewMPolyCtx([]string{"x", "y"}, fmpz.DegLex, 11)
+	p := mpoly(t, c, "x*y + 1")
+	zero := NewMPoly(c)
+	for name, f := range map[string]func(){
+		"Div":       func() { NewMPoly(c).Div(p, zero) },
+		"DivMod":    func() { NewMPoly(c).DivMod(p, zero, NewMPoly(c)) },
+		"MakeMonic": func() { NewMPoly(c).MakeMonic(zero) },
+		"Reduce":    func() { NewMPoly(c).Reduce(p, []*MPoly{zero}) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDivisionByZero) {
+			t.Errorf("%s by zero: err = %v, want ErrDivisionByZero", name, err)
+		}
+	}
+	for name, f := range map[string]func(){
+		"Degree(-1)":     func() { p.Degree(-1) },
+		"Degree(2)":      func() { p.Degree(2) },
+		"SetVar(2)":      func() { NewMPoly(c).SetVar(2) },
+		"Derivative(2)":  func() { NewMPoly(c).Derivative(p, 2) },
+		"EvaluateOne(2)": func() { NewMPoly(c).EvaluateOne(p, 2, 1) },
+		"Evaluate":       func() { p.Evaluate([]uint64{1, 2, 3}) },
+	} {
+		if !testutil.Misuse("nmod", f) {
+			t.Errorf("%s did not report misuse", name)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+// Package padic implements p-adic numbers and their unramified
+// extensions (q-adic numbers) to a fixed precision.
+//
+// An element x is stored as p^v * u with a unit u and precision
+// N, meaning x is known modulo p^N.  Following FLINT, the result
+// of an operation is computed to the precision of the receiver.
+package padic
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/fmpq.h>
+// #include <flint/padic.h>
+import "C"
+
+import (
+	"runtime"
+	"strconv"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpq"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// A PrintMode selects how String formats p-adic numbers.
+type PrintMode int
+
+const (
+	// Terse prints the rational number represented, e.g. 1/3.
+	Terse PrintMode = C.PADIC_TERSE
+	// Series prints a p-adic expansion, e.g. 2 + 3*5 + 1*5^2.
+	Series PrintMode = C.PADIC_SERIES
+	// ValUnit prints the unit and valuation, e.g. 3*5^2.
+	ValUnit PrintMode = C.PADIC_VAL_UNIT
+)
+
+// A Ctx describes the p-adic numbers Q_p with a default precision.
+type Ctx struct {
+	c    C.padic_ctx_struct
+	prec int
+}
+
+// NewCtx returns a context for Q_p whose elements are created with
+// precision n, printed according to mode.  NewCtx panics with
+// flint.ErrDomain if p is not prime.
+func NewCtx(p *fmpz.Int, n int, mode PrintMode) *Ctx {
+	if !p.IsPrime() {
+		flint.Panic("padic.NewCtx", flint.ErrDomain)
+	}
+	c := &Ctx{prec: n}
+	C.padic_ctx_init(&c.c, (*C.fmpz)(p), C.slong(max(0, n-10)), C.slong(max(0, n+10)), C.enum_padic_print_mode(mode))
+	runtime.SetFinalizer(c, (*Ctx).destroy)
+	return c
+}
+
+func (c *Ctx) destroy() {
+	C.padic_ctx_clear(&c.c)
+}
+
+// Prime returns the prime p of c.
+func (c *Ctx) Prime() *fmpz.Int {
+	p := fmpz.NewInt(0)
+	C.fmpz_set((*C.fmpz)(p), &c.c.p[0])
+	return p
+}
+
+// Prec returns the precision of new elements of c.
+func (c *Ctx) Prec() int {
+	return c.prec
+}
+
+// An Elem represents a p-adic number p^v * u known modulo p^N.
+type Elem struct {
+	p C.padic_struct
+	c *Ctx
+}
+
+// NewElem returns the zero element of c with the default precision.
+func NewElem(c *Ctx) *Elem {
+	return NewElemPrec(c, c.prec)
+}
+
+// NewElemPrec returns the zero element of c with precision n.
+func NewElemPrec(c *Ctx, n int) *Elem {
+	z := &Elem{c: c}
+	C.padic_init2(&z.p, C.slong(n))
+	runtime.SetFinalizer(z, (*Elem).destroy)
+	return z
+}
+
+func (z *Elem) destroy() {
+	C.padic_clear(&z.p)
+}
+
+func (z *Elem) ctx() *C.padic_ctx_struct {
+	return &z.c.c
+}
+
+// same panics unless all of xs belong to the context of z.
+func (z *Elem) same(xs ...*Elem) {
+	for _, x := range xs {
+		if x.c != z.c {
+			panic("padic: operands belong to different contexts")
+		}
+	}
+}
+
+// Ctx returns the context of z.
+func (z *Elem) Ctx() *Ctx {
+	return z.c
+}
+
+// Prec returns the precision N of z.
+func (z *Elem) Prec() int {
+	return int(z.p.N)
+}
+
+// SetPrec sets the precision of z to n, reducing z if n is
+// smaller than the current precision, and returns z.
+func (z *Elem) SetPrec(n int) *Elem {
+	z.p.N = C.slong(n)
+	C.padic_reduce(&z.p, z.ctx())
+	return z
+}
+
+// Val returns the valuation v of z.  The valuation of 0 is 0.
+func (z *Elem) Val() int {
+	return int(z.p.v)
+}
+
+// Unit returns the unit part u of z.
+func (z *Elem) Unit() *fmpz.Int {
+	u := fmpz.NewInt(0)
+	C.fmpz_set((*C.fmpz)(u), &z.p.u)
+	return u
+}
+
+// IsZero reports whether z is zero to its precision.
+func (z *Elem) IsZero() bool {
+	return C.padic_is_zero(&z.p) != 0
+}
+
+// Equal reports whether z and x have the same representation.
+func (z *Elem) Equal(x *Elem) bool {
+	z.same(x)
+	return C.padic_equal(&z.p, &x.p) != 0
+}
+
+// Set sets z = x to the precision of z and returns z.
+func (z *Elem) Set(x *Elem) *Elem {
+	z.same(x)
+	C.padic_set(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// SetInt64 sets z = x and returns z.
+func (z *Elem) SetInt64(x int64) *Elem {
+	C.padic_set_si(&z.p, C.slong(x), z.ctx())
+	return z
+}
+
+// SetInt sets z = x and returns z.
+func (z *Elem) SetInt(x *fmpz.Int) *Elem {
+	C.padic_set_fmpz(&z.p, (*C.fmpz)(x), z.ctx())
+	return z
+}
+
+// SetRat sets z = x and returns z.
+func (z *Elem) SetRat(x *fmpq.Rat) *Elem {
+	C.padic_set_fmpq(&z.p, (*C.fmpq)(unsafe.Pointer(x)), z.ctx())
+	return z
+}
+
+// Int returns the integer in [0, p^N) congruent to z and true.
+// If z is not a p-adic integer, Int returns (nil, false).
+func (z *Elem) Int() (*fmpz.Int, bool) {
+	if !z.IsZero() && z.Val() < 0 {
+		return nil, false
+	}
+	x := fmpz.NewInt(0)
+	C.padic_get_fmpz((*C.fmpz)(x), &z.p, z.ctx())
+	return x, true
+}
+
+// Rat returns the rational number p^v * u represented by z.
+func (z *Elem) Rat() *fmpq.Rat {
+	x := fmpq.NewRat(0, 1)
+	C.padic_get_fmpq((*C.fmpq)(unsafe.Pointer(x)), &z.p, z.ctx())
+	return x
+}
+
+// String returns a representation of z in the print mode of its
+// context, followed by the precision, e.g. "2 + 3*5 + O(5^3)".
+func (z *Elem) String() string {
+	o := "O(" + z.c.Prime().String() + "^" + strconv.Itoa(z.Prec()) + ")"
+	if z.IsZero() {
+		return o
+	}
+	p := C.padic_get_str(nil, &z.p, z.ctx())
+	defer C.free(unsafe.Pointer(p))
+	return C.GoString(p) + " + " + o
+}
+
+// Add sets z = x + y and returns z.
+func (z *Elem) Add(x, y *Elem) *Elem {
+	z.same(x, y)
+	C.padic_add(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *Elem) Sub(x, y *Elem) *Elem {
+	z.same(x, y)
+	C.padic_sub(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *Elem) Neg(x *Elem) *Elem {
+	z.same(x)
+	C.padic_neg(&z.p, &x.p, z.ctx())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *Elem) Mul(x, y *Elem) *Elem {
+	z.same(x, y)
+	C.padic_mul(&z.p, &x.p, &y.p, z.ctx())
+	return z
+}
+
+// Div sets z = x / y and returns (z, true).  If y is zero, z is
+// unchanged and Div returns (z, false).
+func (z *Elem) Div(x, y *Elem) (*Elem, bool) {
+	z.same(x, y)
+	if y.IsZero() {
+		return z, false
+	}
+	C.padic_div(&z.p, &x.p, &y.p, z.ctx())
+	return z, true
+}
+
+// Inv sets z = 1/x and returns (z, true).  If x is zero, z is
+// unchanged and Inv returns (z, false).
+func (z *Elem) Inv(x *Elem) (*Elem, bool) {
+	z.same(x)
+	if x.IsZero() {
+		return z, false
+	}
+	C.padic_inv(&z.p, &x.p, z.ctx())
+	return z, true
+}
+
+// Pow sets z = x^e and returns (z, true).  If x is zero and e is
+// negative, z is unchanged and Pow returns (z, false).
+func (z *Elem) Pow(x *Elem, e int) (*Elem, bool) {
+	z.same(x)
+	if e < 0 && x.IsZero() {
+		return z, false
+	}
+	C.padic_pow_si(&z.p, &x.p, C.slong(e), z.ctx())
+	return z, true
+}
+
+// Sqrt sets z to a square root of x and returns (z, true).  If x
+// is not a square in Q_p, z is unchanged and Sqrt returns
+// (z, false).
+func (z *Elem) Sqrt(x *Elem) (*Elem, bool) {
+	z.same(x)
+	t := NewElemPrec(z.c, z.Prec())
+	if C.padic_sqrt(&t.p, &x.p, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Exp sets z = exp(x) and returns (z, true).  The series converges
+// only for val(x) >= 1 (val(x) >= 2 if p = 2); otherwise z is
+// unchanged and Exp returns (z, false).
+func (z *Elem) Exp(x *Elem) (*Elem, bool) {
+	z.same(x)
+	t := NewElemPrec(z.c, z.Prec())
+	if C.padic_exp(&t.p, &x.p, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Log sets z = log(x) and returns (z, true).  The series converges
+// only for val(x - 1) >= 1; otherwise z is unchanged and Log
+// returns (z, false).
+func (z *Elem) Log(x *Elem) (*Elem, bool) {
+	z.same(x)
+	t := NewElemPrec(z.c, z.Prec())
+	if C.padic_log(&t.p, &x.p, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Teichmuller sets z to the Teichmüller lift of x, the unique
+// (p-1)-th root of unity congruent to x modulo p, and returns
+// (z, true).  If x is divisible by p the lift is zero.  If x is
+// not a p-adic integer, z is unchanged and Teichmuller returns
+// (z, false).
+func (z *Elem) Teichmuller(x *Elem) (*Elem, bool) {
+	z.same(x)
+	if !x.IsZero() && x.Val() < 0 {
+		return z, false
+	}
+	C.padic_teichmuller(&z.p, &x.p, z.ctx())
+	return z, true
+}
@@ -0,0 +1,209 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build cgo && !noflint
+
+package padic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpq"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+func TestElem(t *testing.T) {
+	c := NewCtx(fmpz.NewInt(5), 10, Terse)
+	if c.Prime().Int64() != 5 || c.Prec() != 10 {
+		t.Errorf("Ctx has p = %v and precision %d", c.Prime(), c.Prec())
+	}
+
+	x := NewElem(c).SetInt64(50)
+	if x.Val() != 2 || x.Unit().Int64() != 2 || x.Prec() != 10 {
+		t.Errorf("50 = 5^%d * %v + O(5^%d)", x.Val(), x.Unit(), x.Prec())
+	}
+	if s := x.String(); s != "50 + O(5^10)" {
+		t.Errorf("String = %q, want %q", s, "50 + O(5^10)")
+	}
+	if s := NewElem(c).String(); s != "O(5^10)" {
+		t.Errorf("String(0) = %q, want O(5^10)", s)
+	}
+	if n, ok := NewElem(c).SetInt64(-1).Int(); !ok || n.Int64() != 9765624 {
+		t.Errorf("Int(-1) = %v, %v, want 5^10 - 1", n, ok)
+	}
+	if !NewElemPrec(c, 2).Set(x).IsZero() {
+		t.Errorf("50 + O(5^2) is not zero")
+	}
+	if !NewElem(c).Set(x).SetPrec(2).IsZero() {
+		t.Errorf("SetPrec(2) of 50 is not zero")
+	}
+
+	third := NewElem(c).SetRat(fmpq.NewRat(1, 3))
+	if n, ok := third.Int(); !ok || fmpz.NewInt(0).MulInt64(n, 3).Int64()%9765625 != 1 {
+		t.Errorf("Int(1/3) = %v, %v", n, ok)
+	}
+	three := NewElem(c).SetInt(fmpz.NewInt(3))
+	if z := NewElem(c).Mul(third, three); !z.Equal(NewElem(c).SetInt64(1)) {
+		t.Errorf("1/3 * 3 = %v, want 1", z)
+	}
+	small := NewElem(c).SetRat(fmpq.NewRat(2, 25))
+	if _, ok := small.Int(); ok || small.Val() != -2 {
+		t.Errorf("2/25 has valuation %d and is integral: %v", small.Val(), ok)
+	}
+	if r := small.Rat(); r.String() != "2/25" {
+		t.Errorf("Rat(2/25) = %v", r)
+	}
+	if z := NewElem(c).Sub(NewElem(c).Add(x, three), three); !z.Equal(x) || !NewElem(c).Add(x, NewElem(c).Neg(x)).IsZero() {
+		t.Errorf("Add, Sub and Neg are inconsistent")
+	}
+}
+
+func TestElemDivision(t *testing.T) {
+	c := NewCtx(fmpz.NewInt(5), 10, Terse)
+	zero := NewElem(c)
+	x := NewElem(c).SetInt64(10)
+	if z, ok := NewElem(c).Div(x, NewElem(c).SetInt64(25)); !ok || z.Val() != -1 || z.Unit().Int64() != 2 {
+		t.Errorf("10/25 = %v, %v, want 2/5", z, ok)
+	}
+	if z, ok := NewElem(c).Inv(x); !ok || !NewElem(c).Mul(z, x).Equal(NewElem(c).SetInt64(1)) {
+		t.Errorf("Inv(10) = %v, %v", z, ok)
+	}
+	if z, ok := NewElem(c).Pow(x, -2); !ok || z.Val() != -2 {
+		t.Errorf("10^-2 = %v, %v", z, ok)
+	}
+	if _, ok := NewElem(c).Inv(zero); ok {
+		t.Errorf("Inv(0) succeeded")
+	}
+	if _, ok := NewElem(c).Div(x, zero); ok {
+		t.Errorf("10/0 succeeded")
+	}
+	if _, ok := NewElem(c).Pow(zero, -1); ok {
+		t.Errorf("0^-1 succeeded")
+	}
+}
+
+func TestElemFunctions(t *testing.T) {
+	c := NewCtx(fmpz.NewInt(5), 10, Terse)
+	one := NewElem(c).SetInt64(1)
+
+	// -1 is a square in Q_5 as 5 = 1 mod 4; 2 is not a square mod 5.
+	m := NewElem(c).SetInt64(-1)
+	if s, ok := NewElem(c).Sqrt(m); !ok || !NewElem(c).Mul(s, s).Equal(m) {
+		t.Errorf("Sqrt(-1) = %v, %v", s, ok)
+	}
+	if _, ok := NewElem(c).Sqrt(NewElem(c).SetInt64(2)); ok {
+		t.Errorf("Sqrt(2) succeeded")
+	}
+
+	x := NewElem(c).SetInt64(5)
+	e, ok := NewElem(c).Exp(x)
+	if !ok {
+		t.Fatalf("Exp(5) failed")
+	}
+	if l, ok := NewElem(c).Log(e); !ok || !l.Equal(x) {
+		t.Errorf("Log(Exp(5)) = %v, %v, want 5", l, ok)
+	}
+	if _, ok := NewElem(c).Exp(one); ok {
+		t.Errorf("Exp(1) succeeded")
+	}
+	if _, ok := NewElem(c).Log(NewElem(c).SetInt64(2)); ok {
+		t.Errorf("Log(2) succeeded")
+	}
+
+	// The Teichmüller lift of 2 is a 4th root of unity congruent to 2.
+	w, ok := NewElem(c).Teichmuller(NewElem(c).SetInt64(2))
+	if p, _ := NewElem(c).Pow(w, 4); !ok || !p.Equal(one) {
+		t.Errorf("Teichmuller(2)^4 = %v, want 1", p)
+	}
+	if n, _ := w.Int(); n.Int64()%5 != 2 {
+		t.Errorf("Teichmuller(2) = %v, want 2 mod 5", w)
+	}
+	if _, ok := NewElem(c).Teichmuller(NewElem(c).SetRat(fmpq.NewRat(1, 5))); ok {
+		t.Errorf("Teichmuller(1/5) succeeded")
+	}
+}
+
+func TestQadic(t *testing.T) {
+	c := NewQadicCtx(fmpz.NewInt(3), 2, 10, "a", Terse)
+	if c.Degree() != 2 || c.Prec() != 10 || c.Base().Prime().Int64() != 3 {
+		t.Errorf("QadicCtx has degree %d, precision %d over Q_%v", c.Degree(), c.Prec(), c.Base().Prime())
+	}
+	one := NewQadicElem(c).SetInt64(1)
+	a := NewQadicElem(c).Gen()
+
+	if z := NewQadicElem(c).Frobenius(a, 2); !z.Equal(a) {
+		t.Errorf("Frobenius^2(a) = %v, want a", z)
+	}
+	if z := NewQadicElem(c).Frobenius(a, 1); z.Equal(a) {
+		t.Errorf("Frobenius(a) = a")
+	}
+	if z, ok := NewQadicElem(c).Inv(a); !ok || !NewQadicElem(c).Mul(z, a).Equal(one) {
+		t.Errorf("a * a^-1 = %v, want 1", NewQadicElem(c).Mul(z, a))
+	}
+	if _, ok := NewQadicElem(c).Inv(NewQadicElem(c)); ok {
+		t.Errorf("Inv(0) succeeded")
+	}
+	a2 := NewQadicElem(c).Mul(a, a)
+	if s, ok := NewQadicElem(c).Sqrt(a2); !ok || !NewQadicElem(c).Mul(s, s).Equal(a2) {
+		t.Errorf("Sqrt(a^2) = %v, %v", s, ok)
+	}
+	if z, ok := NewQadicElem(c).Pow(a, 2); !ok || !z.Equal(a2) {
+		t.Errorf("Pow(a, 2) = %v, %v, want %v", z, ok, a2)
+	}
+	if z, ok := NewQadicElem(c).Pow(a2, -1); !ok || !NewQadicElem(c).Mul(z, a2).Equal(one) {
+		t.Errorf("Pow(a^2, -1) = %v, %v, want 1/a^2", z, ok)
+	}
+	if _, ok := NewQadicElem(c).Pow(NewQadicElem(c), -1); ok {
+		t.Errorf("Pow(0, -1) succeeded")
+	}
+	if z := NewQadicElem(c).Sub(NewQadicElem(c).Add(a, one), one); !z.Equal(a) || !NewQadicElem(c).Add(a, NewQadicElem(c).Neg(a)).IsZero() {
+		t.Errorf("Add, Sub and Neg are inconsistent")
+	}
+
+	// For x in Q_p, Tr(x) = 2x and N(x) = x^2.
+	b := NewElem(c.Base()).SetInt64(2)
+	x := NewQadicElem(c).SetElem(b)
+	if n, _ := x.Trace().Int(); n.Int64() != 4 {
+		t.Errorf("Tr(2) = %v, want 4", x.Trace())
+	}
+	if n, _ := x.Norm().Int(); n.Int64() != 4 {
+		t.Errorf("N(2) = %v, want 4", x.Norm())
+	}
+	cs := NewQadicElem(c).SetInt64(7).Coeffs()
+	if n, _ := cs[0].Int(); len(cs) != 2 || n.Int64() != 7 || !cs[1].IsZero() {
+		t.Errorf("Coeffs(7) = %v, want [7 0]", cs)
+	}
+
+	// The Teichmüller lift of a is an 8th root of unity.
+	w, ok := NewQadicElem(c).Teichmuller(a)
+	if p, _ := NewQadicElem(c).Pow(w, 8); !ok || !p.Equal(one) {
+		t.Errorf("Teichmuller(a)^8 = %v, want 1", p)
+	}
+
+	y := NewQadicElem(c).Mul(NewQadicElem(c).SetInt64(3), a)
+	e, ok := NewQadicElem(c).Exp(y)
+	if !ok {
+		t.Fatalf("Exp(3a) failed")
+	}
+	if l, ok := NewQadicElem(c).Log(e); !ok || !l.Equal(y) {
+		t.Errorf("Log(Exp(3a)) = %v, %v, want %v", l, ok, y)
+	}
+	if y.Val() != 1 || NewQadicElem(c).Val() != 0 {
+		t.Errorf("Val(3a) = %d, want 1", y.Val())
+	}
+}
+
+func TestCtxDomain(t *testing.T) {
+	for name, f := range map[string]func(){
+		"NewCtx(4)":         func() { NewCtx(fmpz.NewInt(4), 10, Terse) },
+		"NewQadicCtx(4, 2)": func() { NewQadicCtx(fmpz.NewInt(4), 2, 10, "a", Terse) },
+		"NewQadicCtx(3, 0)": func() { NewQadicCtx(fmpz.NewInt(3), 0, 10, "a", Terse) },
+	} {
+		if err := flint.Try(f); !errors.Is(err, flint.ErrDomain) {
+			t.Errorf("%s: err = %v, want ErrDomain", name, err)
+		}
+	}
+}
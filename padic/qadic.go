@@ -0,0 +1,343 @@
+// Copyright 2012 go.flint authors. All rights reserved.
+// Use of this source code is governed by the GNU General
+// Public License version 2 (or any later version).
+
+//go:build !noflint
+
+package padic
+
+// #include <stdlib.h>
+// #include <flint/flint.h>
+// #include <flint/fmpz.h>
+// #include <flint/padic.h>
+// #include <flint/padic_poly.h>
+// #include <flint/qadic.h>
+import "C"
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/frithjof-schulze/go.flint/flint"
+	"github.com/frithjof-schulze/go.flint/fmpz"
+)
+
+// A QadicCtx describes the unramified extension Q_q of Q_p of
+// degree d, q = p^d, with a default precision.  Its residue field
+// is GF(q), defined by a Conway polynomial where one is known.
+type QadicCtx struct {
+	c    C.qadic_ctx_struct
+	base *Ctx
+	prec int
+}
+
+// NewQadicCtx returns a context for the unramified extension of
+// Q_p of degree d, whose elements are created with precision n.
+// The generator is printed as v.  NewQadicCtx panics with
+// flint.ErrDomain if p is not prime or d < 1.
+func NewQadicCtx(p *fmpz.Int, d, n int, v string, mode PrintMode) *QadicCtx {
+	if !p.IsPrime() || d < 1 {
+		flint.Panic("padic.NewQadicCtx", flint.ErrDomain)
+	}
+	base := NewCtx(p, n, mode)
+	c := &QadicCtx{base: base, prec: n}
+	s := C.CString(v)
+	defer C.free(unsafe.Pointer(s))
+	C.qadic_ctx_init(&c.c, (*C.fmpz)(p), C.slong(d), C.slong(max(0, n-10)), C.slong(max(0, n+10)), s, C.enum_padic_print_mode(mode))
+	runtime.SetFinalizer(c, (*QadicCtx).destroy)
+	return c
+}
+
+func (c *QadicCtx) destroy() {
+	C.qadic_ctx_clear(&c.c)
+}
+
+func (c *QadicCtx) pctx() *C.padic_ctx_struct {
+	return &c.c.pctx
+}
+
+// Base returns the context of the p-adic numbers under c.  Traces,
+// norms and coefficients of elements of c belong to it.
+func (c *QadicCtx) Base() *Ctx {
+	return c.base
+}
+
+// Degree returns the degree d of c over Q_p.
+func (c *QadicCtx) Degree() int {
+	return int(C.qadic_ctx_degree(&c.c))
+}
+
+// Prec returns the precision of new elements of c.
+func (c *QadicCtx) Prec() int {
+	return c.prec
+}
+
+// A QadicElem represents an element of an unramified extension
+// of Q_p, known modulo p^N.
+type QadicElem struct {
+	q C.qadic_struct
+	c *QadicCtx
+}
+
+// NewQadicElem returns the zero element of c with the default
+// precision.
+func NewQadicElem(c *QadicCtx) *QadicElem {
+	return NewQadicElemPrec(c, c.prec)
+}
+
+// NewQadicElemPrec returns the zero element of c with precision n.
+func NewQadicElemPrec(c *QadicCtx, n int) *QadicElem {
+	z := &QadicElem{c: c}
+	C.qadic_init2(&z.q, C.slong(n))
+	runtime.SetFinalizer(z, (*QadicElem).destroy)
+	return z
+}
+
+func (z *QadicElem) destroy() {
+	C.qadic_clear(&z.q)
+}
+
+func (z *QadicElem) ctx() *C.qadic_ctx_struct {
+	return &z.c.c
+}
+
+// same panics unless all of xs belong to the context of z.
+func (z *QadicElem) same(xs ...*QadicElem) {
+	for _, x := range xs {
+		if x.c != z.c {
+			panic("padic: operands belong to different contexts")
+		}
+	}
+}
+
+// Ctx returns the context of z.
+func (z *QadicElem) Ctx() *QadicCtx {
+	return z.c
+}
+
+// Prec returns the precision N of z.
+func (z *QadicElem) Prec() int {
+	return int(z.q.N)
+}
+
+// Val returns the valuation of z.  The valuation of 0 is 0.
+func (z *QadicElem) Val() int {
+	if z.IsZero() {
+		return 0
+	}
+	return int(z.q.val)
+}
+
+// IsZero reports whether z is zero to its precision.
+func (z *QadicElem) IsZero() bool {
+	return C.qadic_is_zero(&z.q) != 0
+}
+
+// Equal reports whether z and x have the same representation.
+func (z *QadicElem) Equal(x *QadicElem) bool {
+	z.same(x)
+	return C.qadic_equal(&z.q, &x.q) != 0
+}
+
+// Set sets z = x to the precision of z and returns z.
+func (z *QadicElem) Set(x *QadicElem) *QadicElem {
+	z.same(x)
+	C.qadic_set(&z.q, &x.q, z.ctx())
+	return z
+}
+
+// SetInt64 sets z = x and returns z.
+func (z *QadicElem) SetInt64(x int64) *QadicElem {
+	C.padic_poly_set_si(&z.q, C.slong(x), z.c.pctx())
+	C.qadic_reduce(&z.q, z.ctx())
+	return z
+}
+
+// SetElem sets z to the p-adic number x and returns z.
+func (z *QadicElem) SetElem(x *Elem) *QadicElem {
+	if x.c != z.c.base {
+		panic("padic: operands belong to different contexts")
+	}
+	C.padic_poly_set_padic(&z.q, &x.p, z.c.pctx())
+	C.qadic_reduce(&z.q, z.ctx())
+	return z
+}
+
+// Gen sets z to the generator of the extension, a lift of the
+// generator of the residue field, and returns z.
+func (z *QadicElem) Gen() *QadicElem {
+	C.qadic_gen(&z.q, z.ctx())
+	return z
+}
+
+// Coeffs returns the coordinates of z in the basis 1, a, ...,
+// a^(d-1) as p-adic numbers of the base context, where a is the
+// generator.
+func (z *QadicElem) Coeffs() []*Elem {
+	c := make([]*Elem, z.c.Degree())
+	cs := unsafe.Slice(z.q.coeffs, int(z.q.length))
+	for i := range c {
+		c[i] = NewElemPrec(z.c.base, z.Prec())
+		if i < len(cs) {
+			C.fmpz_set(&c[i].p.u, &cs[i])
+			c[i].p.v = z.q.val
+			C._padic_canonicalise(&c[i].p, c[i].ctx())
+			C.padic_reduce(&c[i].p, c[i].ctx())
+		}
+	}
+	return c
+}
+
+// String returns a representation of z as a polynomial in the
+// generator with p-adic coefficients, followed by the precision.
+func (z *QadicElem) String() string {
+	o := "O(" + z.c.base.Prime().String() + "^" + strconv.Itoa(z.Prec()) + ")"
+	if z.IsZero() {
+		return o
+	}
+	v := C.GoString(z.c.c._var)
+	var terms []string
+	for i, a := range z.Coeffs() {
+		if a.IsZero() {
+			continue
+		}
+		p := C.padic_get_str(nil, &a.p, a.ctx())
+		s := "(" + C.GoString(p) + ")"
+		C.free(unsafe.Pointer(p))
+		switch i {
+		case 0:
+		case 1:
+			s += "*" + v
+		default:
+			s += "*" + v + "^" + strconv.Itoa(i)
+		}
+		terms = append(terms, s)
+	}
+	return strings.Join(terms, " + ") + " + " + o
+}
+
+// Add sets z = x + y and returns z.
+func (z *QadicElem) Add(x, y *QadicElem) *QadicElem {
+	z.same(x, y)
+	C.qadic_add(&z.q, &x.q, &y.q, z.ctx())
+	return z
+}
+
+// Sub sets z = x - y and returns z.
+func (z *QadicElem) Sub(x, y *QadicElem) *QadicElem {
+	z.same(x, y)
+	C.qadic_sub(&z.q, &x.q, &y.q, z.ctx())
+	return z
+}
+
+// Neg sets z = -x and returns z.
+func (z *QadicElem) Neg(x *QadicElem) *QadicElem {
+	z.same(x)
+	C.qadic_neg(&z.q, &x.q, z.ctx())
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *QadicElem) Mul(x, y *QadicElem) *QadicElem {
+	z.same(x, y)
+	C.qadic_mul(&z.q, &x.q, &y.q, z.ctx())
+	return z
+}
+
+// Inv sets z = 1/x and returns (z, true).  If x is zero, z is
+// unchanged and Inv returns (z, false).
+func (z *QadicElem) Inv(x *QadicElem) (*QadicElem, bool) {
+	z.same(x)
+	if x.IsZero() {
+		return z, false
+	}
+	C.qadic_inv(&z.q, &x.q, z.ctx())
+	return z, true
+}
+
+// Pow sets z = x^e and returns (z, true).  If x is zero and e is
+// negative, z is unchanged and Pow returns (z, false).
+func (z *QadicElem) Pow(x *QadicElem, e int) (*QadicElem, bool) {
+	z.same(x)
+	n := fmpz.NewInt(int64(e))
+	if e < 0 {
+		// qadic_pow takes only non-negative exponents.
+		t, ok := NewQadicElemPrec(z.c, z.Prec()).Inv(x)
+		if !ok {
+			return z, false
+		}
+		x = t
+		n.Neg(n)
+	}
+	C.qadic_pow(&z.q, &x.q, (*C.fmpz)(n), z.ctx())
+	return z, true
+}
+
+// Sqrt sets z to a square root of x and returns (z, true).  If x
+// is not a square, z is unchanged and Sqrt returns (z, false).
+func (z *QadicElem) Sqrt(x *QadicElem) (*QadicElem, bool) {
+	z.same(x)
+	t := NewQadicElemPrec(z.c, z.Prec())
+	if C.qadic_sqrt(&t.q, &x.q, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Exp sets z = exp(x) and returns (z, true).  If the series does
+// not converge, z is unchanged and Exp returns (z, false).
+func (z *QadicElem) Exp(x *QadicElem) (*QadicElem, bool) {
+	z.same(x)
+	t := NewQadicElemPrec(z.c, z.Prec())
+	if C.qadic_exp(&t.q, &x.q, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Log sets z = log(x) and returns (z, true).  If the series does
+// not converge, z is unchanged and Log returns (z, false).
+func (z *QadicElem) Log(x *QadicElem) (*QadicElem, bool) {
+	z.same(x)
+	t := NewQadicElemPrec(z.c, z.Prec())
+	if C.qadic_log(&t.q, &x.q, z.ctx()) == 0 {
+		return z, false
+	}
+	return z.Set(t), true
+}
+
+// Teichmuller sets z to the Teichmüller lift of x and returns
+// (z, true).  If x is not integral, z is unchanged and Teichmuller
+// returns (z, false).
+func (z *QadicElem) Teichmuller(x *QadicElem) (*QadicElem, bool) {
+	z.same(x)
+	if x.Val() < 0 {
+		return z, false
+	}
+	C.qadic_teichmuller(&z.q, &x.q, z.ctx())
+	return z, true
+}
+
+// Frobenius sets z to the image of x under the e-th power of the
+// Frobenius automorphism and returns z.
+func (z *QadicElem) Frobenius(x *QadicElem, e int) *QadicElem {
+	z.same(x)
+	C.qadic_frobenius(&z.q, &x.q, C.slong(e), z.ctx())
+	return z
+}
+
+// Trace returns the trace of z over Q_p.
+func (z *QadicElem) Trace() *Elem {
+	t := NewElemPrec(z.c.base, z.Prec())
+	C.qadic_trace(&t.p, &z.q, z.ctx())
+	return t
+}
+
+// Norm returns the norm of z over Q_p.
+func (z *QadicElem) Norm() *Elem {
+	t := NewElemPrec(z.c.base, z.Prec())
+	C.qadic_norm(&t.p, &z.q, z.ctx())
+	return t
+}